@@ -1,7 +1,13 @@
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -10,6 +16,7 @@ import (
 	"github.com/aquasecurity/starboard/pkg/apis/aquasecurity"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 const (
@@ -225,6 +232,65 @@ const (
 	SeverityUnknown  Severity = "UNKNOWN"
 )
 
+// severityRanks orders severity levels from the least to the most severe.
+// Unknown severity is considered more severe than none, but less severe than
+// low.
+var severityRanks = map[Severity]int{
+	SeverityNone:     0,
+	SeverityUnknown:  1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// ParseSeverity parses the specified severity level. Case and surrounding
+// whitespace are ignored, e.g. " high" is parsed as SeverityHigh. An error is
+// returned if the severity is not recognized.
+func ParseSeverity(value string) (Severity, error) {
+	severity := Severity(strings.ToUpper(strings.TrimSpace(value)))
+	if _, ok := severityRanks[severity]; !ok {
+		return "", fmt.Errorf("unrecognized severity: %q", value)
+	}
+	return severity, nil
+}
+
+// Rank returns the position of the severity in the order of severity levels
+// from the least to the most severe, i.e. NONE, UNKNOWN, LOW, MEDIUM, HIGH and
+// CRITICAL, starting at zero. It returns -1 if the severity is not recognized,
+// which makes it less severe than any recognized one.
+func (s Severity) Rank() int {
+	rank, ok := severityRanks[s]
+	if !ok {
+		return -1
+	}
+	return rank
+}
+
+// Compare returns a negative number if the severity is less severe than the
+// specified one, zero if they're equally severe, and a positive number if it's
+// more severe, according to Rank.
+func (s Severity) Compare(other Severity) int {
+	return s.Rank() - other.Rank()
+}
+
+// VulnerabilityStatus is the status of a vulnerability in the distribution
+// of the vulnerable package. It tells whether the vulnerability can be
+// resolved by upgrading the package, e.g. it's will_not_fix if the vendor
+// won't fix it.
+type VulnerabilityStatus string
+
+const (
+	VulnerabilityStatusUnknown            VulnerabilityStatus = "unknown"
+	VulnerabilityStatusNotAffected        VulnerabilityStatus = "not_affected"
+	VulnerabilityStatusAffected           VulnerabilityStatus = "affected"
+	VulnerabilityStatusFixed              VulnerabilityStatus = "fixed"
+	VulnerabilityStatusUnderInvestigation VulnerabilityStatus = "under_investigation"
+	VulnerabilityStatusWillNotFix         VulnerabilityStatus = "will_not_fix"
+	VulnerabilityStatusFixDeferred        VulnerabilityStatus = "fix_deferred"
+	VulnerabilityStatusEndOfLife          VulnerabilityStatus = "end_of_life"
+)
+
 type VulnerabilitySummary struct {
 	CriticalCount int `json:"criticalCount"`
 	HighCount     int `json:"highCount"`
@@ -232,10 +298,62 @@ type VulnerabilitySummary struct {
 	LowCount      int `json:"lowCount"`
 	NoneCount     int `json:"noneCount"`
 	UnknownCount  int `json:"unknownCount"`
+	// RiskScore is the sum of the weights of all vulnerabilities, where each
+	// vulnerability is weighted according to its severity.
+	RiskScore int `json:"riskScore,omitempty"`
+	// Fixable counts vulnerabilities that have a fixed version available.
+	Fixable *FixableSummary `json:"fixable,omitempty"`
+}
+
+// FixableSummary is a summary of vulnerabilities that have a fixed version
+// available, counted by severity.
+type FixableSummary struct {
+	CriticalCount int `json:"criticalCount"`
+	HighCount     int `json:"highCount"`
+	MediumCount   int `json:"mediumCount"`
+	LowCount      int `json:"lowCount"`
+	UnknownCount  int `json:"unknownCount"`
+}
+
+// Total returns the total number of vulnerabilities of all severities,
+// including unknown.
+func (s VulnerabilitySummary) Total() int {
+	return s.CriticalCount + s.HighCount + s.MediumCount + s.LowCount + s.NoneCount + s.UnknownCount
+}
+
+// FilterStats is the spec for the numbers of detected vulnerabilities that
+// were omitted from a scan result, by the reason they were omitted.
+type FilterStats struct {
+	// BelowThreshold is the number of vulnerabilities below the severity
+	// threshold.
+	BelowThreshold int `json:"belowThreshold,omitempty"`
+	// BelowMinScore is the number of vulnerabilities below the minimum CVSS
+	// score, or without a score if unscored vulnerabilities are dropped.
+	BelowMinScore int `json:"belowMinScore,omitempty"`
+	// Ignored is the number of vulnerabilities matched by an unexpired rule
+	// of the ignore policy.
+	Ignored int `json:"ignored,omitempty"`
+	// Unfixed is the number of vulnerabilities without a fixed version that
+	// were omitted because unfixed vulnerabilities are ignored.
+	Unfixed int `json:"unfixed,omitempty"`
+	// Deduplicated is the number of vulnerabilities that duplicated another
+	// one of the same package.
+	Deduplicated int `json:"deduplicated,omitempty"`
+	// ExcludedPackages is the number of vulnerabilities of packages that are
+	// not included by, or are excluded by, the package patterns.
+	ExcludedPackages int `json:"excludedPackages,omitempty"`
+}
+
+// Total returns the total number of omitted vulnerabilities.
+func (s FilterStats) Total() int {
+	return s.BelowThreshold + s.BelowMinScore + s.Ignored + s.Unfixed + s.Deduplicated + s.ExcludedPackages
 }
 
 type Registry struct {
 	Server string `json:"server"`
+	// IsDefaultRegistry indicates whether the image reference omitted the
+	// registry, which therefore defaulted to Docker Hub.
+	IsDefaultRegistry bool `json:"isDefaultRegistry,omitempty"`
 }
 
 // Artifact is the spec for an artifact that can be scanned.
@@ -244,18 +362,79 @@ type Artifact struct {
 	Digest     string `json:"digest,omitempty"`
 	Tag        string `json:"tag,omitempty"`
 	MimeType   string `json:"mimeType,omitempty"`
+	// Architecture is the CPU architecture of the scanned image, e.g. amd64
+	// or arm64, which tells apart results of images of a multi-arch manifest.
+	Architecture string `json:"architecture,omitempty"`
 }
 
 // Vulnerability is the spec for a vulnerability record.
 type Vulnerability struct {
-	VulnerabilityID  string   `json:"vulnerabilityID"`
-	Resource         string   `json:"resource"`
-	InstalledVersion string   `json:"installedVersion"`
-	FixedVersion     string   `json:"fixedVersion"`
-	Severity         Severity `json:"severity"`
-	Title            string   `json:"title"`
-	Description      string   `json:"description"`
-	Links            []string `json:"links"`
+	VulnerabilityID  string              `json:"vulnerabilityID"`
+	Resource         string              `json:"resource"`
+	PkgPath          string              `json:"pkgPath,omitempty"`
+	PkgIdentifier    *PkgIdentifier      `json:"pkgIdentifier,omitempty"`
+	InstalledVersion string              `json:"installedVersion"`
+	FixedVersion     string              `json:"fixedVersion"`
+	FixedVersions    []string            `json:"fixedVersions"`
+	Severity         Severity            `json:"severity"`
+	VendorSeverity   map[string]string   `json:"vendorSeverity,omitempty"`
+	Status           VulnerabilityStatus `json:"status,omitempty"`
+	Title            string              `json:"title"`
+	Description      string              `json:"description"`
+	Links            []string            `json:"links"`
+	PrimaryURL       string              `json:"primaryURL,omitempty"`
+	Score            *float64            `json:"score,omitempty"`
+	CVSSVector       string              `json:"cvssVector,omitempty"`
+	Target           string              `json:"target,omitempty"`
+	Layer            *Layer              `json:"layer,omitempty"`
+	CweIDs           []string            `json:"cweIDs"`
+	PublishedDate    *metav1.Time        `json:"publishedDate,omitempty"`
+	LastModifiedDate *metav1.Time        `json:"lastModifiedDate,omitempty"`
+	DataSource       DataSource          `json:"dataSource,omitempty"`
+	ScannerVersion   string              `json:"scannerVersion,omitempty"`
+	Aliases          []string            `json:"aliases,omitempty"`
+	// AffectedPackages are the names of all packages affected by the
+	// vulnerability if vulnerabilities with the same identifier were
+	// consolidated into a single entry.
+	AffectedPackages []string `json:"affectedPackages,omitempty"`
+	// CategorizedLinks are the Links grouped by the type of the resource
+	// they refer to.
+	CategorizedLinks *CategorizedLinks `json:"categorizedLinks,omitempty"`
+	// Ecosystem is the type of the target the vulnerability was detected in,
+	// e.g. alpine or npm, which keys the EcosystemSummary of the scan result.
+	Ecosystem string `json:"ecosystem,omitempty"`
+}
+
+// PkgIdentifier is the spec for the identifiers of a Java package, i.e. its
+// Package URL and its Maven coordinates.
+type PkgIdentifier struct {
+	PURL       string `json:"purl,omitempty"`
+	GroupID    string `json:"groupID,omitempty"`
+	ArtifactID string `json:"artifactID,omitempty"`
+	Version    string `json:"version,omitempty"`
+}
+
+// CategorizedLinks is the spec for the links of a vulnerability grouped by
+// type, i.e. links to advisories, patches, exploits, and other links.
+type CategorizedLinks struct {
+	Advisory []string `json:"advisory,omitempty"`
+	Patch    []string `json:"patch,omitempty"`
+	Exploit  []string `json:"exploit,omitempty"`
+	Other    []string `json:"other,omitempty"`
+}
+
+// DataSource is the spec for the source of the advisory of a vulnerability,
+// e.g. Alpine SecDB or GitHub Security Advisory.
+type DataSource struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Layer is the spec for an image layer that introduced a vulnerable package.
+type Layer struct {
+	Digest string `json:"digest,omitempty"`
+	DiffID string `json:"diffID,omitempty"`
 }
 
 // +genclient
@@ -277,8 +456,268 @@ type VulnerabilityScanResult struct {
 	Scanner         Scanner              `json:"scanner"`
 	Registry        Registry             `json:"registry"`
 	Artifact        Artifact             `json:"artifact"`
+	OSFamily        string               `json:"osFamily,omitempty"`
+	OSVersion       string               `json:"osVersion,omitempty"`
+	ImageType       ImageType            `json:"imageType,omitempty"`
 	Summary         VulnerabilitySummary `json:"summary"`
 	Vulnerabilities []Vulnerability      `json:"vulnerabilities"`
+	UpdateTimestamp metav1.Time          `json:"updateTimestamp"`
+	ScanDuration    metav1.Duration      `json:"scanDuration,omitempty"`
+	// Truncated indicates whether some vulnerabilities were dropped to keep
+	// the report within the configured limit. The Summary still reflects all
+	// detected vulnerabilities.
+	Truncated    bool `json:"truncated,omitempty"`
+	DroppedCount int  `json:"droppedCount,omitempty"`
+	// Warnings describe non-fatal issues encountered while the scan result
+	// was produced, e.g. an image reference that could not be parsed.
+	Warnings []string `json:"warnings,omitempty"`
+	// WorkloadKind, WorkloadName and Namespace identify the Kubernetes
+	// workload that runs the scanned image, if the result was produced for
+	// a workload.
+	WorkloadKind string `json:"workloadKind,omitempty"`
+	WorkloadName string `json:"workloadName,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+	// Secrets are secrets, e.g. access keys, detected in the artifact.
+	Secrets []SecretFinding `json:"secrets,omitempty"`
+	// ScannedTargets are the targets scanned by the scanner, e.g. the OS
+	// packages and a lock file of language packages, in the order they were
+	// reported.
+	ScannedTargets []string `json:"scannedTargets,omitempty"`
+	// EcosystemSummary are summaries of vulnerabilities keyed by the type
+	// of the target they were detected in, e.g. debian, npm or pip.
+	EcosystemSummary map[string]VulnerabilitySummary `json:"ecosystemSummary,omitempty"`
+	// FilterStats are the numbers of detected vulnerabilities omitted from
+	// the result by reason, if any were omitted.
+	FilterStats *FilterStats `json:"filterStats,omitempty"`
+	// RawReport is the original JSON output of the scanner, if it was
+	// retained. It's not serialized, so that it can be stored separately,
+	// e.g. in an annotation or an object store.
+	RawReport []byte `json:"-"`
+}
+
+// ImageType is the type of a scanned image, which tells by the targets found
+// by the scan whether the absence of vulnerabilities of OS packages means
+// that the image has no vulnerable OS packages.
+type ImageType string
+
+const (
+	// ImageTypeFull is the type of an image with OS packages.
+	ImageTypeFull ImageType = "full"
+	// ImageTypeDistroless is the type of an image with language packages,
+	// e.g. of an application, but without a package manager of the OS.
+	ImageTypeDistroless ImageType = "distroless"
+	// ImageTypeScratch is the type of an image without any packages, e.g.
+	// of a statically linked binary built from scratch.
+	ImageTypeScratch ImageType = "scratch"
+)
+
+// SecretFinding is the spec for a secret detected in a scanned artifact.
+// The matched content is not retained, so that a report does not leak
+// the secret.
+type SecretFinding struct {
+	RuleID    string   `json:"ruleID"`
+	Category  string   `json:"category,omitempty"`
+	Severity  Severity `json:"severity"`
+	Title     string   `json:"title,omitempty"`
+	Target    string   `json:"target"`
+	StartLine int      `json:"startLine,omitempty"`
+	EndLine   int      `json:"endLine,omitempty"`
+}
+
+// HasOperatingSystem checks whether the scanned artifact has an operating
+// system. It's false for images built from scratch, in which case the absence
+// of vulnerabilities does not mean that OS packages are not vulnerable.
+func (r VulnerabilityScanResult) HasOperatingSystem() bool {
+	return r.OSFamily != ""
+}
+
+// ImageRef returns the full reference of the scanned artifact, e.g.
+// index.docker.io/library/nginx:1.16. The digest is preferred over the tag if
+// both are present, because it identifies the scanned image exactly.
+func (r VulnerabilityScanResult) ImageRef() string {
+	ref := r.Artifact.Repository
+	if r.Registry.Server != "" {
+		ref = r.Registry.Server + "/" + ref
+	}
+	switch {
+	case r.Artifact.Digest != "":
+		ref += "@" + r.Artifact.Digest
+	case r.Artifact.Tag != "":
+		ref += ":" + r.Artifact.Tag
+	}
+	return ref
+}
+
+// HasVulnerabilities checks whether the scan found any vulnerabilities. It's
+// false for a clean scan, which is serialized with zero counts in the summary
+// and an empty list of vulnerabilities rather than with those omitted. The
+// summary is consulted as well, because it counts vulnerabilities dropped
+// from a truncated result.
+func (r VulnerabilityScanResult) HasVulnerabilities() bool {
+	return len(r.Vulnerabilities) > 0 || r.Summary.Total() > 0
+}
+
+// HighestSeverity returns the most severe severity with a non-zero count in
+// the summary of the result. Unknown severity is considered less severe than
+// low. SeverityNone is returned if the result has no vulnerabilities of other
+// severities.
+func (r VulnerabilityScanResult) HighestSeverity() Severity {
+	s := r.Summary
+	switch {
+	case s.CriticalCount > 0:
+		return SeverityCritical
+	case s.HighCount > 0:
+		return SeverityHigh
+	case s.MediumCount > 0:
+		return SeverityMedium
+	case s.LowCount > 0:
+		return SeverityLow
+	case s.UnknownCount > 0:
+		return SeverityUnknown
+	default:
+		return SeverityNone
+	}
+}
+
+// Fingerprint returns the hex encoded SHA-256 digest of the findings of the
+// result, so that results of scans that found the same vulnerabilities of the
+// same artifact have the same fingerprint. The digest covers the scanner, the
+// registry, the artifact, the operating system, the workload, the summaries
+// and the vulnerabilities and secrets regardless of their order. Volatile fields,
+// such as UpdateTimestamp, ScanDuration and Warnings, are excluded.
+//
+// An empty string is returned if the result cannot be encoded, e.g. because a
+// score is not a number.
+func (r VulnerabilityScanResult) Fingerprint() string {
+	vulnerabilities, err := sortedJSON(len(r.Vulnerabilities), func(i int) interface{} { return r.Vulnerabilities[i] })
+	if err != nil {
+		return ""
+	}
+	secrets, err := sortedJSON(len(r.Secrets), func(i int) interface{} { return r.Secrets[i] })
+	if err != nil {
+		return ""
+	}
+	data, err := json.Marshal(struct {
+		Scanner          Scanner
+		Registry         Registry
+		Artifact         Artifact
+		OSFamily         string
+		OSVersion        string
+		WorkloadKind     string
+		WorkloadName     string
+		Namespace        string
+		Summary          VulnerabilitySummary
+		EcosystemSummary map[string]VulnerabilitySummary
+		Vulnerabilities  []json.RawMessage
+		Secrets          []json.RawMessage
+	}{
+		Scanner:          r.Scanner,
+		Registry:         r.Registry,
+		Artifact:         r.Artifact,
+		OSFamily:         r.OSFamily,
+		OSVersion:        r.OSVersion,
+		WorkloadKind:     r.WorkloadKind,
+		WorkloadName:     r.WorkloadName,
+		Namespace:        r.Namespace,
+		Summary:          r.Summary,
+		EcosystemSummary: r.EcosystemSummary,
+		Vulnerabilities:  vulnerabilities,
+		Secrets:          secrets,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sortedJSON encodes the n elements returned by the specified function and
+// sorts the encoded elements, so that the order of the elements does not
+// affect the encoding.
+func sortedJSON(n int, element func(i int) interface{}) ([]json.RawMessage, error) {
+	encoded := make([]json.RawMessage, n)
+	for i := range encoded {
+		data, err := json.Marshal(element(i))
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = data
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return string(encoded[i]) < string(encoded[j])
+	})
+	return encoded, nil
+}
+
+// Validate checks the structure of the result and returns an aggregate of
+// all problems found, e.g. an empty scanner name, negative summary counts or
+// vulnerabilities without an ID. It returns nil if the result is valid.
+func (r VulnerabilityScanResult) Validate() error {
+	var errs []error
+	if r.Scanner.Name == "" {
+		errs = append(errs, fmt.Errorf("scanner.name must not be empty"))
+	}
+	s := r.Summary
+	errs = appendNegativeCountErrors(errs, "summary", []namedCount{
+		{"criticalCount", s.CriticalCount},
+		{"highCount", s.HighCount},
+		{"mediumCount", s.MediumCount},
+		{"lowCount", s.LowCount},
+		{"noneCount", s.NoneCount},
+		{"unknownCount", s.UnknownCount},
+		{"riskScore", s.RiskScore},
+	})
+	if f := s.Fixable; f != nil {
+		errs = appendNegativeCountErrors(errs, "summary.fixable", []namedCount{
+			{"criticalCount", f.CriticalCount},
+			{"highCount", f.HighCount},
+			{"mediumCount", f.MediumCount},
+			{"lowCount", f.LowCount},
+			{"unknownCount", f.UnknownCount},
+		})
+	}
+	if r.DroppedCount < 0 {
+		errs = append(errs, fmt.Errorf("droppedCount must not be negative: %d", r.DroppedCount))
+	}
+	if f := r.FilterStats; f != nil {
+		errs = appendNegativeCountErrors(errs, "filterStats", []namedCount{
+			{"belowThreshold", f.BelowThreshold},
+			{"belowMinScore", f.BelowMinScore},
+			{"ignored", f.Ignored},
+			{"unfixed", f.Unfixed},
+			{"deduplicated", f.Deduplicated},
+			{"excludedPackages", f.ExcludedPackages},
+		})
+	}
+	for i, v := range r.Vulnerabilities {
+		if v.VulnerabilityID == "" {
+			errs = append(errs, fmt.Errorf("vulnerabilities[%d].vulnerabilityID must not be empty", i))
+		}
+		if !isValidSeverity(v.Severity) {
+			errs = append(errs, fmt.Errorf("vulnerabilities[%d].severity is invalid: %q", i, v.Severity))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// +k8s:deepcopy-gen=false
+type namedCount struct {
+	name  string
+	count int
+}
+
+func appendNegativeCountErrors(errs []error, path string, counts []namedCount) []error {
+	for _, c := range counts {
+		if c.count < 0 {
+			errs = append(errs, fmt.Errorf("%s.%s must not be negative: %d", path, c.name, c.count))
+		}
+	}
+	return errs
+}
+
+func isValidSeverity(severity Severity) bool {
+	_, ok := severityRanks[severity]
+	return ok
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,481 @@
+package v1alpha1_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestVulnerabilitySummary_Total(t *testing.T) {
+	testCases := []struct {
+		name          string
+		summary       v1alpha1.VulnerabilitySummary
+		expectedTotal int
+	}{
+		{
+			name:          "Should return zero for empty summary",
+			summary:       v1alpha1.VulnerabilitySummary{},
+			expectedTotal: 0,
+		},
+		{
+			name: "Should return count of single severity",
+			summary: v1alpha1.VulnerabilitySummary{
+				HighCount: 3,
+			},
+			expectedTotal: 3,
+		},
+		{
+			name: "Should return sum of all severities including unknown",
+			summary: v1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				HighCount:     2,
+				MediumCount:   3,
+				LowCount:      4,
+				NoneCount:     5,
+				UnknownCount:  6,
+			},
+			expectedTotal: 21,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedTotal, tc.summary.Total())
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		value            string
+		expectedSeverity v1alpha1.Severity
+		expectedError    string
+	}{
+		{
+			name:             "Should parse canonical severity",
+			value:            "CRITICAL",
+			expectedSeverity: v1alpha1.SeverityCritical,
+		},
+		{
+			name:             "Should parse lowercase severity",
+			value:            "high",
+			expectedSeverity: v1alpha1.SeverityHigh,
+		},
+		{
+			name:             "Should parse severity surrounded by whitespace",
+			value:            " Medium\n",
+			expectedSeverity: v1alpha1.SeverityMedium,
+		},
+		{
+			name:             "Should parse none severity",
+			value:            "NONE",
+			expectedSeverity: v1alpha1.SeverityNone,
+		},
+		{
+			name:             "Should parse unknown severity",
+			value:            "unknown",
+			expectedSeverity: v1alpha1.SeverityUnknown,
+		},
+		{
+			name:          "Should return error for unrecognized severity",
+			value:         "SEVERE",
+			expectedError: `unrecognized severity: "SEVERE"`,
+		},
+		{
+			name:          "Should return error for empty severity",
+			value:         "",
+			expectedError: `unrecognized severity: ""`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			severity, err := v1alpha1.ParseSeverity(tc.value)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSeverity, severity)
+		})
+	}
+}
+
+func TestSeverity_Rank(t *testing.T) {
+	ordered := []v1alpha1.Severity{
+		v1alpha1.SeverityNone,
+		v1alpha1.SeverityUnknown,
+		v1alpha1.SeverityLow,
+		v1alpha1.SeverityMedium,
+		v1alpha1.SeverityHigh,
+		v1alpha1.SeverityCritical,
+	}
+	for i, severity := range ordered {
+		assert.Equal(t, i, severity.Rank(), severity)
+	}
+	assert.Equal(t, -1, v1alpha1.Severity("SEVERE").Rank())
+	assert.Equal(t, -1, v1alpha1.Severity("high").Rank())
+}
+
+func TestSeverity_Compare(t *testing.T) {
+	testCases := []struct {
+		a, b     v1alpha1.Severity
+		expected int
+	}{
+		{a: v1alpha1.SeverityCritical, b: v1alpha1.SeverityHigh, expected: 1},
+		{a: v1alpha1.SeverityHigh, b: v1alpha1.SeverityCritical, expected: -1},
+		{a: v1alpha1.SeverityMedium, b: v1alpha1.SeverityMedium, expected: 0},
+		{a: v1alpha1.SeverityLow, b: v1alpha1.SeverityUnknown, expected: 1},
+		{a: v1alpha1.SeverityUnknown, b: v1alpha1.SeverityNone, expected: 1},
+		{a: v1alpha1.Severity("SEVERE"), b: v1alpha1.SeverityNone, expected: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.a)+" vs "+string(tc.b), func(t *testing.T) {
+			cmp := tc.a.Compare(tc.b)
+			switch {
+			case tc.expected > 0:
+				assert.Greater(t, cmp, 0)
+			case tc.expected < 0:
+				assert.Less(t, cmp, 0)
+			default:
+				assert.Zero(t, cmp)
+			}
+		})
+	}
+
+	t.Run("Should sort severities from the most severe", func(t *testing.T) {
+		severities := []v1alpha1.Severity{
+			v1alpha1.SeverityLow,
+			v1alpha1.SeverityUnknown,
+			v1alpha1.SeverityCritical,
+			v1alpha1.SeverityNone,
+			v1alpha1.SeverityMedium,
+			v1alpha1.SeverityHigh,
+		}
+		sort.Slice(severities, func(i, j int) bool {
+			return severities[i].Compare(severities[j]) > 0
+		})
+		assert.Equal(t, []v1alpha1.Severity{
+			v1alpha1.SeverityCritical,
+			v1alpha1.SeverityHigh,
+			v1alpha1.SeverityMedium,
+			v1alpha1.SeverityLow,
+			v1alpha1.SeverityUnknown,
+			v1alpha1.SeverityNone,
+		}, severities)
+	})
+}
+
+func TestVulnerabilityScanResult_HasVulnerabilities(t *testing.T) {
+	testCases := []struct {
+		name     string
+		result   v1alpha1.VulnerabilityScanResult
+		expected bool
+	}{
+		{
+			name: "Should return false for clean result",
+			result: v1alpha1.VulnerabilityScanResult{
+				Vulnerabilities: []v1alpha1.Vulnerability{},
+			},
+			expected: false,
+		},
+		{
+			name: "Should return true for result with vulnerabilities",
+			result: v1alpha1.VulnerabilityScanResult{
+				Summary: v1alpha1.VulnerabilitySummary{
+					LowCount: 1,
+				},
+				Vulnerabilities: []v1alpha1.Vulnerability{
+					{VulnerabilityID: "CVE-2019-1547", Severity: v1alpha1.SeverityLow},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Should return true for result with vulnerabilities of none severity",
+			result: v1alpha1.VulnerabilityScanResult{
+				Summary: v1alpha1.VulnerabilitySummary{
+					NoneCount: 1,
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Should return true for truncated result without vulnerabilities",
+			result: v1alpha1.VulnerabilityScanResult{
+				Summary: v1alpha1.VulnerabilitySummary{
+					HighCount: 2,
+				},
+				Vulnerabilities: []v1alpha1.Vulnerability{},
+				Truncated:       true,
+				DroppedCount:    2,
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.result.HasVulnerabilities())
+		})
+	}
+}
+
+func TestVulnerabilityScanResult_HighestSeverity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		summary          v1alpha1.VulnerabilitySummary
+		expectedSeverity v1alpha1.Severity
+	}{
+		{
+			name:             "Should return none for clean result",
+			summary:          v1alpha1.VulnerabilitySummary{},
+			expectedSeverity: v1alpha1.SeverityNone,
+		},
+		{
+			name: "Should return none when there are only vulnerabilities of none severity",
+			summary: v1alpha1.VulnerabilitySummary{
+				NoneCount: 2,
+			},
+			expectedSeverity: v1alpha1.SeverityNone,
+		},
+		{
+			name: "Should return unknown when there are only vulnerabilities of unknown severity",
+			summary: v1alpha1.VulnerabilitySummary{
+				NoneCount:    1,
+				UnknownCount: 1,
+			},
+			expectedSeverity: v1alpha1.SeverityUnknown,
+		},
+		{
+			name: "Should return low for low-only result",
+			summary: v1alpha1.VulnerabilitySummary{
+				LowCount: 3,
+			},
+			expectedSeverity: v1alpha1.SeverityLow,
+		},
+		{
+			name: "Should return critical for result with criticals",
+			summary: v1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				HighCount:     2,
+				MediumCount:   3,
+				LowCount:      4,
+				UnknownCount:  5,
+			},
+			expectedSeverity: v1alpha1.SeverityCritical,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := v1alpha1.VulnerabilityScanResult{Summary: tc.summary}
+			assert.Equal(t, tc.expectedSeverity, result.HighestSeverity())
+		})
+	}
+}
+
+func TestVulnerabilityScanResult_ImageRef(t *testing.T) {
+	testCases := []struct {
+		name        string
+		registry    v1alpha1.Registry
+		artifact    v1alpha1.Artifact
+		expectedRef string
+	}{
+		{
+			name:        "Should return reference with tag",
+			registry:    v1alpha1.Registry{Server: "index.docker.io"},
+			artifact:    v1alpha1.Artifact{Repository: "library/nginx", Tag: "1.16"},
+			expectedRef: "index.docker.io/library/nginx:1.16",
+		},
+		{
+			name:     "Should prefer digest over tag",
+			registry: v1alpha1.Registry{Server: "core.harbor.domain"},
+			artifact: v1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+			expectedRef: "core.harbor.domain/library/nginx@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+		},
+		{
+			name:        "Should return repository without registry",
+			artifact:    v1alpha1.Artifact{Repository: "/srv/app"},
+			expectedRef: "/srv/app",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := v1alpha1.VulnerabilityScanResult{Registry: tc.registry, Artifact: tc.artifact}
+			assert.Equal(t, tc.expectedRef, result.ImageRef())
+		})
+	}
+}
+
+func TestVulnerabilityScanResult_Validate(t *testing.T) {
+	valid := v1alpha1.VulnerabilityScanResult{
+		Scanner: v1alpha1.Scanner{Name: "Trivy", Vendor: "Aqua Security", Version: "0.9.1"},
+		Summary: v1alpha1.VulnerabilitySummary{MediumCount: 1, Fixable: &v1alpha1.FixableSummary{MediumCount: 1}},
+		Vulnerabilities: []v1alpha1.Vulnerability{
+			{VulnerabilityID: "CVE-2019-1549", Resource: "openssl", Severity: v1alpha1.SeverityMedium},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		mutate           func(r *v1alpha1.VulnerabilityScanResult)
+		expectedMessages []string
+	}{
+		{
+			name:   "Should accept valid result",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {},
+		},
+		{
+			name: "Should accept result without vulnerabilities",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {
+				r.Summary = v1alpha1.VulnerabilitySummary{}
+				r.Vulnerabilities = nil
+			},
+		},
+		{
+			name: "Should reject empty scanner name",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {
+				r.Scanner.Name = ""
+			},
+			expectedMessages: []string{"scanner.name must not be empty"},
+		},
+		{
+			name: "Should reject negative summary counts",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {
+				r.Summary.HighCount = -1
+				r.Summary.RiskScore = -3
+				r.Summary.Fixable.LowCount = -2
+				r.DroppedCount = -5
+			},
+			expectedMessages: []string{
+				"summary.highCount must not be negative: -1",
+				"summary.riskScore must not be negative: -3",
+				"summary.fixable.lowCount must not be negative: -2",
+				"droppedCount must not be negative: -5",
+			},
+		},
+		{
+			name: "Should reject negative filter stats",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {
+				r.FilterStats = &v1alpha1.FilterStats{
+					Ignored:      -1,
+					Deduplicated: 2,
+				}
+			},
+			expectedMessages: []string{
+				"filterStats.ignored must not be negative: -1",
+			},
+		},
+		{
+			name: "Should reject vulnerabilities without ID or with invalid severity",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {
+				r.Vulnerabilities = append(r.Vulnerabilities,
+					v1alpha1.Vulnerability{Resource: "musl", Severity: v1alpha1.SeverityLow},
+					v1alpha1.Vulnerability{VulnerabilityID: "CVE-2019-1547", Severity: "SEVERE"},
+				)
+			},
+			expectedMessages: []string{
+				"vulnerabilities[1].vulnerabilityID must not be empty",
+				`vulnerabilities[2].severity is invalid: "SEVERE"`,
+			},
+		},
+		{
+			name: "Should list all problems",
+			mutate: func(r *v1alpha1.VulnerabilityScanResult) {
+				r.Scanner.Name = ""
+				r.Summary.CriticalCount = -1
+				r.Vulnerabilities[0].VulnerabilityID = ""
+			},
+			expectedMessages: []string{
+				"scanner.name must not be empty",
+				"summary.criticalCount must not be negative: -1",
+				"vulnerabilities[0].vulnerabilityID must not be empty",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := *valid.DeepCopy()
+			tc.mutate(&result)
+			err := result.Validate()
+			if tc.expectedMessages == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var agg utilerrors.Aggregate
+			require.True(t, errors.As(err, &agg))
+			var messages []string
+			for _, e := range agg.Errors() {
+				messages = append(messages, e.Error())
+			}
+			assert.Equal(t, tc.expectedMessages, messages)
+		})
+	}
+}
+
+func TestVulnerabilityScanResult_Fingerprint(t *testing.T) {
+	newResult := func() v1alpha1.VulnerabilityScanResult {
+		return v1alpha1.VulnerabilityScanResult{
+			Scanner:  v1alpha1.Scanner{Name: "Trivy", Vendor: "Aqua Security", Version: "0.9.1"},
+			Registry: v1alpha1.Registry{Server: "index.docker.io"},
+			Artifact: v1alpha1.Artifact{Repository: "library/alpine", Tag: "3.10.2"},
+			Summary:  v1alpha1.VulnerabilitySummary{MediumCount: 1, LowCount: 1},
+			Vulnerabilities: []v1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2019-1549", Resource: "openssl", Severity: v1alpha1.SeverityMedium},
+				{VulnerabilityID: "CVE-2019-1547", Resource: "openssl", Severity: v1alpha1.SeverityLow},
+			},
+			UpdateTimestamp: metav1.NewTime(time.Date(2020, 10, 14, 10, 0, 0, 0, time.UTC)),
+		}
+	}
+	fingerprint := newResult().Fingerprint()
+	assert.Len(t, fingerprint, 64)
+
+	t.Run("Should ignore volatile fields", func(t *testing.T) {
+		result := newResult()
+		result.UpdateTimestamp = metav1.NewTime(time.Date(2020, 10, 15, 10, 0, 0, 0, time.UTC))
+		result.ScanDuration = metav1.Duration{Duration: time.Minute}
+		result.Warnings = []string{"resolving scanner version: not found"}
+		assert.Equal(t, fingerprint, result.Fingerprint())
+	})
+
+	t.Run("Should ignore order of vulnerabilities", func(t *testing.T) {
+		result := newResult()
+		result.Vulnerabilities[0], result.Vulnerabilities[1] = result.Vulnerabilities[1], result.Vulnerabilities[0]
+		assert.Equal(t, fingerprint, result.Fingerprint())
+	})
+
+	t.Run("Should change when vulnerability is added", func(t *testing.T) {
+		result := newResult()
+		result.Vulnerabilities = append(result.Vulnerabilities,
+			v1alpha1.Vulnerability{VulnerabilityID: "CVE-2019-1551", Resource: "openssl", Severity: v1alpha1.SeverityLow})
+		assert.NotEqual(t, fingerprint, result.Fingerprint())
+	})
+
+	t.Run("Should change when artifact changes", func(t *testing.T) {
+		result := newResult()
+		result.Artifact.Tag = "3.10.3"
+		assert.NotEqual(t, fingerprint, result.Fingerprint())
+	})
+
+	t.Run("Should change when scanner version changes", func(t *testing.T) {
+		result := newResult()
+		result.Scanner.Version = "0.10.0"
+		assert.NotEqual(t, fingerprint, result.Fingerprint())
+	})
+}
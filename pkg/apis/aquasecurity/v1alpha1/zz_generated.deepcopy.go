@@ -185,6 +185,42 @@ func (in *CISKubeBenchTests) DeepCopy() *CISKubeBenchTests {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CategorizedLinks) DeepCopyInto(out *CategorizedLinks) {
+	*out = *in
+	if in.Advisory != nil {
+		in, out := &in.Advisory, &out.Advisory
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exploit != nil {
+		in, out := &in.Exploit, &out.Exploit
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Other != nil {
+		in, out := &in.Other, &out.Other
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CategorizedLinks.
+func (in *CategorizedLinks) DeepCopy() *CategorizedLinks {
+	if in == nil {
+		return nil
+	}
+	out := new(CategorizedLinks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Check) DeepCopyInto(out *Check) {
 	*out = *in
@@ -298,6 +334,54 @@ func (in *ConfigAuditReportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSource) DeepCopyInto(out *DataSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
+func (in *DataSource) DeepCopy() *DataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterStats) DeepCopyInto(out *FilterStats) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterStats.
+func (in *FilterStats) DeepCopy() *FilterStats {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FixableSummary) DeepCopyInto(out *FixableSummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FixableSummary.
+func (in *FixableSummary) DeepCopy() *FixableSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(FixableSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeHunterOutput) DeepCopyInto(out *KubeHunterOutput) {
 	*out = *in
@@ -396,6 +480,38 @@ func (in *KubeHunterVulnerability) DeepCopy() *KubeHunterVulnerability {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Layer) DeepCopyInto(out *Layer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Layer.
+func (in *Layer) DeepCopy() *Layer {
+	if in == nil {
+		return nil
+	}
+	out := new(Layer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PkgIdentifier) DeepCopyInto(out *PkgIdentifier) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PkgIdentifier.
+func (in *PkgIdentifier) DeepCopy() *PkgIdentifier {
+	if in == nil {
+		return nil
+	}
+	out := new(PkgIdentifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Registry) DeepCopyInto(out *Registry) {
 	*out = *in
@@ -428,14 +544,86 @@ func (in *Scanner) DeepCopy() *Scanner {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretFinding) DeepCopyInto(out *SecretFinding) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretFinding.
+func (in *SecretFinding) DeepCopy() *SecretFinding {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretFinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Vulnerability) DeepCopyInto(out *Vulnerability) {
 	*out = *in
+	if in.PkgIdentifier != nil {
+		in, out := &in.PkgIdentifier, &out.PkgIdentifier
+		*out = new(PkgIdentifier)
+		**out = **in
+	}
+	if in.FixedVersions != nil {
+		in, out := &in.FixedVersions, &out.FixedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VendorSeverity != nil {
+		in, out := &in.VendorSeverity, &out.VendorSeverity
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Links != nil {
 		in, out := &in.Links, &out.Links
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Layer != nil {
+		in, out := &in.Layer, &out.Layer
+		*out = new(Layer)
+		**out = **in
+	}
+	if in.CweIDs != nil {
+		in, out := &in.CweIDs, &out.CweIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublishedDate != nil {
+		in, out := &in.PublishedDate, &out.PublishedDate
+		*out = (*in).DeepCopy()
+	}
+	if in.LastModifiedDate != nil {
+		in, out := &in.LastModifiedDate, &out.LastModifiedDate
+		*out = (*in).DeepCopy()
+	}
+	out.DataSource = in.DataSource
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AffectedPackages != nil {
+		in, out := &in.AffectedPackages, &out.AffectedPackages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CategorizedLinks != nil {
+		in, out := &in.CategorizedLinks, &out.CategorizedLinks
+		*out = new(CategorizedLinks)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -515,7 +703,7 @@ func (in *VulnerabilityScanResult) DeepCopyInto(out *VulnerabilityScanResult) {
 	out.Scanner = in.Scanner
 	out.Registry = in.Registry
 	out.Artifact = in.Artifact
-	out.Summary = in.Summary
+	in.Summary.DeepCopyInto(&out.Summary)
 	if in.Vulnerabilities != nil {
 		in, out := &in.Vulnerabilities, &out.Vulnerabilities
 		*out = make([]Vulnerability, len(*in))
@@ -523,6 +711,40 @@ func (in *VulnerabilityScanResult) DeepCopyInto(out *VulnerabilityScanResult) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.UpdateTimestamp.DeepCopyInto(&out.UpdateTimestamp)
+	out.ScanDuration = in.ScanDuration
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]SecretFinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScannedTargets != nil {
+		in, out := &in.ScannedTargets, &out.ScannedTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EcosystemSummary != nil {
+		in, out := &in.EcosystemSummary, &out.EcosystemSummary
+		*out = make(map[string]VulnerabilitySummary, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.FilterStats != nil {
+		in, out := &in.FilterStats, &out.FilterStats
+		*out = new(FilterStats)
+		**out = **in
+	}
+	if in.RawReport != nil {
+		in, out := &in.RawReport, &out.RawReport
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -539,6 +761,11 @@ func (in *VulnerabilityScanResult) DeepCopy() *VulnerabilityScanResult {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VulnerabilitySummary) DeepCopyInto(out *VulnerabilitySummary) {
 	*out = *in
+	if in.Fixable != nil {
+		in, out := &in.Fixable, &out.Fixable
+		*out = new(FixableSummary)
+		**out = **in
+	}
 	return
 }
 
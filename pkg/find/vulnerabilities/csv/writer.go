@@ -0,0 +1,62 @@
+package csv
+
+import (
+	"encoding/csv"
+	"io"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+var header = []string{
+	"Image",
+	"Registry",
+	"Vulnerability ID",
+	"Package",
+	"Installed Version",
+	"Fixed Version",
+	"Severity",
+	"Title",
+}
+
+// Writer is the interface that wraps the Write method.
+//
+// Write writes the specified VulnerabilityScanResult to the given io.Writer
+// in the CSV format described by RFC 4180, with a header row followed by one
+// row per vulnerability.
+type Writer interface {
+	Write(result starboardv1alpha1.VulnerabilityScanResult, w io.Writer) error
+}
+
+type writer struct {
+}
+
+// NewWriter constructs a new CSV Writer.
+func NewWriter() Writer {
+	return &writer{}
+}
+
+func (w *writer) Write(result starboardv1alpha1.VulnerabilityScanResult, out io.Writer) error {
+	csvWriter := csv.NewWriter(out)
+	err := csvWriter.Write(header)
+	if err != nil {
+		return err
+	}
+	image := result.ImageRef()
+	for _, v := range result.Vulnerabilities {
+		err = csvWriter.Write([]string{
+			image,
+			result.Registry.Server,
+			v.VulnerabilityID,
+			v.Resource,
+			v.InstalledVersion,
+			v.FixedVersion,
+			string(v.Severity),
+			v.Title,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
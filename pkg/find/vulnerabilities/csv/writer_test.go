@@ -0,0 +1,103 @@
+package csv_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/csv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Write(t *testing.T) {
+	result := starboardv1alpha1.VulnerabilityScanResult{
+		Registry: starboardv1alpha1.Registry{
+			Server: "index.docker.io",
+		},
+		Artifact: starboardv1alpha1.Artifact{
+			Repository: "library/alpine",
+			Tag:        "3.10.2",
+		},
+		Vulnerabilities: []starboardv1alpha1.Vulnerability{
+			{
+				VulnerabilityID:  "CVE-2019-1549",
+				Resource:         "openssl",
+				InstalledVersion: "1.1.1c-r0",
+				FixedVersion:     "1.1.1d-r0",
+				Severity:         starboardv1alpha1.SeverityMedium,
+				Title:            "openssl: information disclosure in fork()",
+			},
+			{
+				VulnerabilityID:  "CVE-2019-1547",
+				Resource:         "openssl",
+				InstalledVersion: "1.1.1c-r0",
+				FixedVersion:     "1.1.1d-r0",
+				Severity:         starboardv1alpha1.SeverityLow,
+				Title:            "openssl: side-channel weak encryption vulnerability, \"ECDSA\"",
+			},
+			{
+				VulnerabilityID:  "CVE-2019-18276",
+				Resource:         "bash",
+				InstalledVersion: "5.0-4",
+				Severity:         starboardv1alpha1.SeverityLow,
+				Title:            "bash: saved UID is not dropped\nwhen effective UID is not equal to its real UID",
+			},
+		},
+	}
+
+	golden, err := ioutil.ReadFile("testdata/golden.csv")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = csv.NewWriter().Write(result, &out)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), out.String())
+}
+
+func TestWriter_Write_Image(t *testing.T) {
+	testCases := []struct {
+		name          string
+		registry      starboardv1alpha1.Registry
+		artifact      starboardv1alpha1.Artifact
+		expectedImage string
+	}{
+		{
+			name:          "Should combine registry, repository and tag",
+			registry:      starboardv1alpha1.Registry{Server: "quay.io"},
+			artifact:      starboardv1alpha1.Artifact{Repository: "prometheus/node-exporter", Tag: "v1.0.1"},
+			expectedImage: "quay.io/prometheus/node-exporter:v1.0.1",
+		},
+		{
+			name:     "Should combine registry, repository and digest",
+			registry: starboardv1alpha1.Registry{Server: "core.harbor.domain"},
+			artifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+			expectedImage: "core.harbor.domain/library/nginx@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+		},
+		{
+			name:          "Should omit empty registry",
+			artifact:      starboardv1alpha1.Artifact{Repository: "nginx", Tag: "1.16"},
+			expectedImage: "nginx:1.16",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := starboardv1alpha1.VulnerabilityScanResult{
+				Registry: tc.registry,
+				Artifact: tc.artifact,
+				Vulnerabilities: []starboardv1alpha1.Vulnerability{
+					{VulnerabilityID: "CVE-2019-1549"},
+				},
+			}
+			var out bytes.Buffer
+			err := csv.NewWriter().Write(result, &out)
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "\n"+tc.expectedImage+",")
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package html
+
+import (
+	"html/template"
+)
+
+var defaultTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Vulnerability report of {{ .Image }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+.summary { display: flex; gap: 1em; margin-bottom: 2em; }
+.summary div { padding: 0.5em 1em; border-radius: 4px; color: #fff; }
+.CRITICAL { background: #b00020; }
+.HIGH { background: #e65100; }
+.MEDIUM { background: #f9a825; }
+.LOW { background: #1565c0; }
+.UNKNOWN { background: #616161; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #d1d5da; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+</style>
+</head>
+<body>
+<h1>Vulnerability report of {{ .Image }}</h1>
+<p>Scanned by {{ .Result.Scanner.Name }} {{ .Result.Scanner.Version }}</p>
+<div class="summary">
+<div class="CRITICAL">Critical: {{ .Summary.CriticalCount }}</div>
+<div class="HIGH">High: {{ .Summary.HighCount }}</div>
+<div class="MEDIUM">Medium: {{ .Summary.MediumCount }}</div>
+<div class="LOW">Low: {{ .Summary.LowCount }}</div>
+<div class="UNKNOWN">Unknown: {{ .Summary.UnknownCount }}</div>
+</div>
+{{- range .Groups }}
+<h2 class="{{ .Severity }}">{{ .Severity }}</h2>
+<table>
+<tr><th>Vulnerability ID</th><th>Package</th><th>Installed Version</th><th>Fixed Version</th><th>Title</th><th>Description</th></tr>
+{{- range .Vulnerabilities }}
+<tr>
+<td>{{ if .PrimaryURL }}<a href="{{ .PrimaryURL }}">{{ .VulnerabilityID }}</a>{{ else }}{{ .VulnerabilityID }}{{ end }}</td>
+<td>{{ .Resource }}</td>
+<td>{{ .InstalledVersion }}</td>
+<td>{{ .FixedVersion }}</td>
+<td>{{ .Title }}</td>
+<td>{{ .Description }}</td>
+</tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No vulnerabilities found.</p>
+{{- end }}
+</body>
+</html>
+`))
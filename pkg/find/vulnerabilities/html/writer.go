@@ -0,0 +1,94 @@
+package html
+
+import (
+	"html/template"
+	"io"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+// Writer is the interface that wraps the Write method.
+//
+// Write renders the specified VulnerabilityScanResult as a self-contained HTML
+// page and writes it to the given io.Writer.
+type Writer interface {
+	Write(result starboardv1alpha1.VulnerabilityScanResult, w io.Writer) error
+}
+
+// WriterOption configures the Writer returned by NewWriter.
+type WriterOption func(*writer)
+
+// WithTemplate sets the template used to render the HTML page instead of the
+// default one. The template is executed with the Page data.
+func WithTemplate(tmpl *template.Template) WriterOption {
+	return func(w *writer) {
+		w.template = tmpl
+	}
+}
+
+// Page is the data passed to the template of the HTML page.
+type Page struct {
+	// Image is the reference of the scanned image.
+	Image   string
+	Result  starboardv1alpha1.VulnerabilityScanResult
+	Summary starboardv1alpha1.VulnerabilitySummary
+	// Groups are vulnerabilities grouped by severity, the most severe first.
+	// Severities without vulnerabilities are omitted.
+	Groups []SeverityGroup
+}
+
+// SeverityGroup holds vulnerabilities of the same severity.
+type SeverityGroup struct {
+	Severity        starboardv1alpha1.Severity
+	Vulnerabilities []starboardv1alpha1.Vulnerability
+}
+
+var severities = []starboardv1alpha1.Severity{
+	starboardv1alpha1.SeverityCritical,
+	starboardv1alpha1.SeverityHigh,
+	starboardv1alpha1.SeverityMedium,
+	starboardv1alpha1.SeverityLow,
+	starboardv1alpha1.SeverityUnknown,
+}
+
+type writer struct {
+	template *template.Template
+}
+
+// NewWriter constructs a new HTML Writer with the specified options.
+func NewWriter(opts ...WriterOption) Writer {
+	w := &writer{
+		template: defaultTemplate,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func (w *writer) Write(result starboardv1alpha1.VulnerabilityScanResult, out io.Writer) error {
+	return w.template.Execute(out, Page{
+		Image:   result.ImageRef(),
+		Result:  result,
+		Summary: result.Summary,
+		Groups:  w.toGroups(result.Vulnerabilities),
+	})
+}
+
+func (w *writer) toGroups(vulnerabilities []starboardv1alpha1.Vulnerability) []SeverityGroup {
+	bySeverity := make(map[starboardv1alpha1.Severity][]starboardv1alpha1.Vulnerability)
+	for _, v := range vulnerabilities {
+		bySeverity[v.Severity] = append(bySeverity[v.Severity], v)
+	}
+	var groups []SeverityGroup
+	for _, severity := range severities {
+		if len(bySeverity[severity]) == 0 {
+			continue
+		}
+		groups = append(groups, SeverityGroup{
+			Severity:        severity,
+			Vulnerabilities: bySeverity[severity],
+		})
+	}
+	return groups
+}
@@ -0,0 +1,100 @@
+package html_test
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var result = starboardv1alpha1.VulnerabilityScanResult{
+	Scanner: starboardv1alpha1.Scanner{
+		Name:    "Trivy",
+		Vendor:  "Aqua Security",
+		Version: "0.9.1",
+	},
+	Registry: starboardv1alpha1.Registry{
+		Server: "index.docker.io",
+	},
+	Artifact: starboardv1alpha1.Artifact{
+		Repository: "library/alpine",
+		Tag:        "3.10.2",
+	},
+	Summary: starboardv1alpha1.VulnerabilitySummary{
+		CriticalCount: 1,
+		MediumCount:   2,
+		LowCount:      1,
+	},
+	Vulnerabilities: []starboardv1alpha1.Vulnerability{
+		{
+			VulnerabilityID: "CVE-2019-1549",
+			Resource:        "openssl",
+			Severity:        starboardv1alpha1.SeverityMedium,
+			PrimaryURL:      "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+		},
+		{
+			VulnerabilityID: "CVE-2019-14697",
+			Resource:        "musl",
+			Severity:        starboardv1alpha1.SeverityCritical,
+			Description:     `<script>alert("pwned")</script>`,
+		},
+		{
+			VulnerabilityID: "CVE-2019-1547",
+			Resource:        "openssl",
+			Severity:        starboardv1alpha1.SeverityLow,
+		},
+		{
+			VulnerabilityID: "CVE-2019-1563",
+			Resource:        "openssl",
+			Severity:        starboardv1alpha1.SeverityMedium,
+		},
+	},
+}
+
+func TestWriter_Write(t *testing.T) {
+	var out bytes.Buffer
+	err := html.NewWriter().Write(result, &out)
+	require.NoError(t, err)
+	page := out.String()
+
+	t.Run("Should render image reference in header", func(t *testing.T) {
+		assert.Contains(t, page, "<h1>Vulnerability report of index.docker.io/library/alpine:3.10.2</h1>")
+	})
+
+	t.Run("Should render summary counts", func(t *testing.T) {
+		assert.Contains(t, page, `<div class="CRITICAL">Critical: 1</div>`)
+		assert.Contains(t, page, `<div class="HIGH">High: 0</div>`)
+		assert.Contains(t, page, `<div class="MEDIUM">Medium: 2</div>`)
+		assert.Contains(t, page, `<div class="LOW">Low: 1</div>`)
+		assert.Contains(t, page, `<div class="UNKNOWN">Unknown: 0</div>`)
+	})
+
+	t.Run("Should group vulnerabilities by severity", func(t *testing.T) {
+		critical := strings.Index(page, `<h2 class="CRITICAL">`)
+		medium := strings.Index(page, `<h2 class="MEDIUM">`)
+		low := strings.Index(page, `<h2 class="LOW">`)
+		assert.True(t, 0 < critical && critical < medium && medium < low)
+		assert.NotContains(t, page, `<h2 class="HIGH">`)
+		assert.True(t, medium < strings.Index(page, "CVE-2019-1563") && strings.Index(page, "CVE-2019-1563") < low)
+	})
+
+	t.Run("Should escape description", func(t *testing.T) {
+		assert.NotContains(t, page, "<script>")
+		assert.Contains(t, page, "&lt;script&gt;alert(&#34;pwned&#34;)&lt;/script&gt;")
+	})
+}
+
+func TestWriter_Write_CustomTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("custom").Parse(
+		`{{ .Image }}{{ range .Groups }} {{ .Severity }}={{ len .Vulnerabilities }}{{ end }}`))
+
+	var out bytes.Buffer
+	err := html.NewWriter(html.WithTemplate(tmpl)).Write(result, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "index.docker.io/library/alpine:3.10.2 CRITICAL=1 MEDIUM=2 LOW=1", out.String())
+}
@@ -0,0 +1,121 @@
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+// Writer is the interface that wraps the Write method.
+//
+// Write writes the specified VulnerabilityScanResult to the given io.Writer
+// as a SARIF 2.1.0 log with a single run. Each unique vulnerability is
+// reported as a rule, and each vulnerable package as a result of that rule.
+type Writer interface {
+	Write(result starboardv1alpha1.VulnerabilityScanResult, w io.Writer) error
+}
+
+type writer struct {
+}
+
+// NewWriter constructs a new SARIF Writer.
+func NewWriter() Writer {
+	return &writer{}
+}
+
+func (w *writer) Write(result starboardv1alpha1.VulnerabilityScanResult, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(w.toLog(result))
+}
+
+func (w *writer) toLog(result starboardv1alpha1.VulnerabilityScanResult) Log {
+	rules := make([]Rule, 0)
+	results := make([]Result, 0)
+	ruleIndexes := make(map[string]int)
+
+	for _, v := range result.Vulnerabilities {
+		level := ToLevel(v.Severity)
+		index, ok := ruleIndexes[v.VulnerabilityID]
+		if !ok {
+			index = len(rules)
+			ruleIndexes[v.VulnerabilityID] = index
+			rules = append(rules, w.toRule(v, level))
+		}
+		results = append(results, Result{
+			RuleID:    v.VulnerabilityID,
+			RuleIndex: index,
+			Level:     level,
+			Message: Message{
+				Text: w.toMessage(v),
+			},
+			Locations: []Location{
+				{
+					PhysicalLocation: PhysicalLocation{
+						ArtifactLocation: ArtifactLocation{
+							URI: v.Resource,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []Run{
+			{
+				Tool: Tool{
+					Driver: Driver{
+						Name:    result.Scanner.Name,
+						Version: result.Scanner.Version,
+						Rules:   rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+func (w *writer) toRule(v starboardv1alpha1.Vulnerability, level Level) Rule {
+	rule := Rule{
+		ID:      v.VulnerabilityID,
+		HelpURI: v.PrimaryURL,
+		DefaultConfig: &ReportingConfiguration{
+			Level: level,
+		},
+	}
+	if v.Title != "" {
+		rule.ShortDescription = &Message{Text: v.Title}
+	}
+	if v.Description != "" {
+		rule.FullDescription = &Message{Text: v.Description}
+	}
+	return rule
+}
+
+func (w *writer) toMessage(v starboardv1alpha1.Vulnerability) string {
+	message := fmt.Sprintf("Package %s %s is affected by %s (%s).", v.Resource, v.InstalledVersion, v.VulnerabilityID, v.Severity)
+	if v.FixedVersion != "" {
+		message += fmt.Sprintf(" Fixed version: %s.", v.FixedVersion)
+	}
+	return message
+}
+
+// ToLevel maps the specified severity to the level of a SARIF result.
+// Critical and high severities are errors, medium severity is a warning, and
+// any other severity is a note.
+func ToLevel(severity starboardv1alpha1.Severity) Level {
+	switch severity {
+	case starboardv1alpha1.SeverityCritical, starboardv1alpha1.SeverityHigh:
+		return LevelError
+	case starboardv1alpha1.SeverityMedium:
+		return LevelWarning
+	default:
+		return LevelNote
+	}
+}
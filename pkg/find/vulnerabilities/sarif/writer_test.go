@@ -0,0 +1,207 @@
+package sarif_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/sarif"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var result = starboardv1alpha1.VulnerabilityScanResult{
+	Scanner: starboardv1alpha1.Scanner{
+		Name:    "Trivy",
+		Vendor:  "Aqua Security",
+		Version: "0.9.1",
+	},
+	Vulnerabilities: []starboardv1alpha1.Vulnerability{
+		{
+			VulnerabilityID:  "CVE-2019-1549",
+			Resource:         "openssl",
+			InstalledVersion: "1.1.1c-r0",
+			FixedVersion:     "1.1.1d-r0",
+			Severity:         starboardv1alpha1.SeverityMedium,
+			Title:            "openssl: information disclosure in fork()",
+			PrimaryURL:       "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+		},
+		{
+			VulnerabilityID:  "CVE-2019-1549",
+			Resource:         "libssl1.1",
+			InstalledVersion: "1.1.1c-r0",
+			FixedVersion:     "1.1.1d-r0",
+			Severity:         starboardv1alpha1.SeverityMedium,
+			Title:            "openssl: information disclosure in fork()",
+		},
+		{
+			VulnerabilityID:  "CVE-2019-14697",
+			Resource:         "musl",
+			InstalledVersion: "1.1.22-r2",
+			Severity:         starboardv1alpha1.SeverityCritical,
+		},
+	},
+}
+
+// TestWriter_Write_Schema validates the output against the definitions of the
+// SARIF 2.1.0 JSON schema for the objects written by the Writer, and checks
+// the constraints that the schema cannot express, e.g. that results refer to
+// the rule at their ruleIndex.
+func TestWriter_Write_Schema(t *testing.T) {
+	var out bytes.Buffer
+	err := sarif.NewWriter().Write(result, &out)
+	require.NoError(t, err)
+
+	assertValidSARIF(t, out.Bytes())
+
+	var log map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &log))
+
+	assert.Equal(t, "2.1.0", log["version"])
+	assert.Equal(t, "https://json.schemastore.org/sarif-2.1.0.json", log["$schema"])
+
+	runs := requireArray(t, log, "runs")
+	require.Len(t, runs, 1)
+	run := runs[0].(map[string]interface{})
+
+	driver := requireObject(t, requireObject(t, run, "tool"), "driver")
+	assert.Equal(t, "Trivy", driver["name"])
+	assert.Equal(t, "0.9.1", driver["version"])
+
+	rules := requireArray(t, driver, "rules")
+	ruleIDs := make([]string, 0)
+	for _, r := range rules {
+		rule := r.(map[string]interface{})
+		require.IsType(t, "", rule["id"])
+		ruleIDs = append(ruleIDs, rule["id"].(string))
+	}
+	assert.Equal(t, []string{"CVE-2019-1549", "CVE-2019-14697"}, ruleIDs, "rule identifiers must be unique")
+
+	results := requireArray(t, run, "results")
+	require.Len(t, results, 3)
+	for _, r := range results {
+		result := r.(map[string]interface{})
+		message := requireObject(t, result, "message")
+		assert.NotEmpty(t, message["text"])
+		assert.Contains(t, []interface{}{"none", "note", "warning", "error"}, result["level"])
+
+		ruleIndex := int(result["ruleIndex"].(float64))
+		require.True(t, ruleIndex >= 0 && ruleIndex < len(ruleIDs))
+		assert.Equal(t, ruleIDs[ruleIndex], result["ruleId"])
+
+		locations := requireArray(t, result, "locations")
+		require.Len(t, locations, 1)
+		artifactLocation := requireObject(t, requireObject(t, locations[0].(map[string]interface{}), "physicalLocation"), "artifactLocation")
+		assert.NotEmpty(t, artifactLocation["uri"])
+	}
+}
+
+func TestWriter_Write(t *testing.T) {
+	var out bytes.Buffer
+	err := sarif.NewWriter().Write(result, &out)
+	require.NoError(t, err)
+
+	var log sarif.Log
+	require.NoError(t, json.Unmarshal(out.Bytes(), &log))
+	require.Len(t, log.Runs, 1)
+
+	assert.Equal(t, []sarif.Result{
+		{
+			RuleID:    "CVE-2019-1549",
+			RuleIndex: 0,
+			Level:     sarif.LevelWarning,
+			Message:   sarif.Message{Text: "Package openssl 1.1.1c-r0 is affected by CVE-2019-1549 (MEDIUM). Fixed version: 1.1.1d-r0."},
+			Locations: []sarif.Location{{PhysicalLocation: sarif.PhysicalLocation{ArtifactLocation: sarif.ArtifactLocation{URI: "openssl"}}}},
+		},
+		{
+			RuleID:    "CVE-2019-1549",
+			RuleIndex: 0,
+			Level:     sarif.LevelWarning,
+			Message:   sarif.Message{Text: "Package libssl1.1 1.1.1c-r0 is affected by CVE-2019-1549 (MEDIUM). Fixed version: 1.1.1d-r0."},
+			Locations: []sarif.Location{{PhysicalLocation: sarif.PhysicalLocation{ArtifactLocation: sarif.ArtifactLocation{URI: "libssl1.1"}}}},
+		},
+		{
+			RuleID:    "CVE-2019-14697",
+			RuleIndex: 1,
+			Level:     sarif.LevelError,
+			Message:   sarif.Message{Text: "Package musl 1.1.22-r2 is affected by CVE-2019-14697 (CRITICAL)."},
+			Locations: []sarif.Location{{PhysicalLocation: sarif.PhysicalLocation{ArtifactLocation: sarif.ArtifactLocation{URI: "musl"}}}},
+		},
+	}, log.Runs[0].Results)
+	assert.Equal(t, "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549", log.Runs[0].Tool.Driver.Rules[0].HelpURI)
+	assert.Equal(t, &sarif.Message{Text: "openssl: information disclosure in fork()"}, log.Runs[0].Tool.Driver.Rules[0].ShortDescription)
+}
+
+func TestToLevel(t *testing.T) {
+	testCases := []struct {
+		severity      starboardv1alpha1.Severity
+		expectedLevel sarif.Level
+	}{
+		{severity: starboardv1alpha1.SeverityCritical, expectedLevel: sarif.LevelError},
+		{severity: starboardv1alpha1.SeverityHigh, expectedLevel: sarif.LevelError},
+		{severity: starboardv1alpha1.SeverityMedium, expectedLevel: sarif.LevelWarning},
+		{severity: starboardv1alpha1.SeverityLow, expectedLevel: sarif.LevelNote},
+		{severity: starboardv1alpha1.SeverityUnknown, expectedLevel: sarif.LevelNote},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.severity), func(t *testing.T) {
+			assert.Equal(t, tc.expectedLevel, sarif.ToLevel(tc.severity))
+		})
+	}
+}
+
+func TestSARIFSchema(t *testing.T) {
+	t.Run("Should reject result without message", func(t *testing.T) {
+		errs := validateSARIF(t, []byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"Trivy"}},"results":[{"ruleId":"CVE-2019-1549"}]}]}`))
+		assert.Equal(t, []string{"runs.0.results.0: message is required"}, errs)
+	})
+
+	t.Run("Should reject unsupported level", func(t *testing.T) {
+		errs := validateSARIF(t, []byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"Trivy"}},"results":[{"level":"critical","message":{"text":"m"}}]}]}`))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0], "runs.0.results.0.level")
+	})
+}
+
+// sarifSchema is the SARIF 2.1.0 JSON schema restricted to the objects written
+// by the Writer.
+const sarifSchema = "testdata/sarif-schema-2.1.0-subset.json"
+
+// assertValidSARIF asserts that the specified SARIF log conforms to the SARIF
+// JSON schema.
+func assertValidSARIF(t *testing.T, log []byte) {
+	t.Helper()
+	assert.Empty(t, validateSARIF(t, log), "output must conform to the SARIF 2.1.0 schema")
+}
+
+// validateSARIF returns the problems of the specified SARIF log that does not
+// conform to the SARIF JSON schema.
+func validateSARIF(t *testing.T, log []byte) []string {
+	t.Helper()
+	schema, err := ioutil.ReadFile(sarifSchema)
+	require.NoError(t, err)
+	validation, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(log))
+	require.NoError(t, err)
+	var errs []string
+	for _, e := range validation.Errors() {
+		errs = append(errs, e.String())
+	}
+	return errs
+}
+
+func requireObject(t *testing.T, parent map[string]interface{}, key string) map[string]interface{} {
+	t.Helper()
+	value, ok := parent[key].(map[string]interface{})
+	require.True(t, ok, "expected object property %q", key)
+	return value
+}
+
+func requireArray(t *testing.T, parent map[string]interface{}, key string) []interface{} {
+	t.Helper()
+	value, ok := parent[key].([]interface{})
+	require.True(t, ok, "expected array property %q", key)
+	return value
+}
@@ -0,0 +1,128 @@
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+var header = []string{
+	"SEVERITY",
+	"VULNERABILITY ID",
+	"PACKAGE",
+	"INSTALLED VERSION",
+	"FIXED VERSION",
+	"TITLE",
+}
+
+var severities = []starboardv1alpha1.Severity{
+	starboardv1alpha1.SeverityCritical,
+	starboardv1alpha1.SeverityHigh,
+	starboardv1alpha1.SeverityMedium,
+	starboardv1alpha1.SeverityLow,
+	starboardv1alpha1.SeverityUnknown,
+}
+
+// Writer is the interface that wraps the Write method.
+//
+// Write renders the specified VulnerabilityScanResult as a plain text table
+// aligned for terminal output and writes it to the given io.Writer. The table
+// is preceded by the image reference and the summary of vulnerabilities.
+type Writer interface {
+	Write(result starboardv1alpha1.VulnerabilityScanResult, w io.Writer) error
+}
+
+// WriterOption configures the Writer returned by NewWriter.
+type WriterOption func(*writer)
+
+// WithMaxTitleWidth sets the maximum number of characters of a title, beyond
+// which the title is truncated. Zero means that titles are not truncated.
+func WithMaxTitleWidth(width int) WriterOption {
+	return func(w *writer) {
+		w.maxTitleWidth = width
+	}
+}
+
+type writer struct {
+	maxTitleWidth int
+}
+
+// NewWriter constructs a new table Writer with the specified options.
+// By default titles are truncated to 50 characters.
+func NewWriter(opts ...WriterOption) Writer {
+	w := &writer{
+		maxTitleWidth: 50,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func (w *writer) Write(result starboardv1alpha1.VulnerabilityScanResult, out io.Writer) error {
+	s := result.Summary
+	_, err := fmt.Fprintf(out, "%s\nTotal: %d (CRITICAL: %d, HIGH: %d, MEDIUM: %d, LOW: %d, UNKNOWN: %d)\n\n",
+		result.ImageRef(), s.Total(), s.CriticalCount, s.HighCount, s.MediumCount, s.LowCount, s.UnknownCount)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, err = fmt.Fprintln(tw, strings.Join(header, "\t"))
+	if err != nil {
+		return err
+	}
+	for _, v := range w.sortBySeverity(result.Vulnerabilities) {
+		_, err = fmt.Fprintln(tw, strings.Join([]string{
+			string(v.Severity),
+			v.VulnerabilityID,
+			v.Resource,
+			v.InstalledVersion,
+			v.FixedVersion,
+			w.truncate(v.Title),
+		}, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// sortBySeverity returns a copy of the specified vulnerabilities ordered by
+// severity, the most severe first. Vulnerabilities of the same severity, and
+// those of unrecognized severities which come last, keep their order.
+func (w *writer) sortBySeverity(vulnerabilities []starboardv1alpha1.Vulnerability) []starboardv1alpha1.Vulnerability {
+	sorted := make([]starboardv1alpha1.Vulnerability, 0, len(vulnerabilities))
+	known := make(map[starboardv1alpha1.Severity]bool, len(severities))
+	for _, severity := range severities {
+		known[severity] = true
+		for _, v := range vulnerabilities {
+			if v.Severity == severity {
+				sorted = append(sorted, v)
+			}
+		}
+	}
+	for _, v := range vulnerabilities {
+		if !known[v.Severity] {
+			sorted = append(sorted, v)
+		}
+	}
+	return sorted
+}
+
+// truncate returns the specified title on a single line, shortened to the
+// maximum width with an ellipsis if it's longer.
+func (w *writer) truncate(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	runes := []rune(title)
+	if w.maxTitleWidth <= 0 || len(runes) <= w.maxTitleWidth {
+		return title
+	}
+	if w.maxTitleWidth <= 3 {
+		return string(runes[:w.maxTitleWidth])
+	}
+	return string(runes[:w.maxTitleWidth-3]) + "..."
+}
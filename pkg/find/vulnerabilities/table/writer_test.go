@@ -0,0 +1,112 @@
+package table_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Write(t *testing.T) {
+	result := starboardv1alpha1.VulnerabilityScanResult{
+		Registry: starboardv1alpha1.Registry{
+			Server: "index.docker.io",
+		},
+		Artifact: starboardv1alpha1.Artifact{
+			Repository: "library/nginx",
+			Tag:        "1.16",
+		},
+		Summary: starboardv1alpha1.VulnerabilitySummary{
+			CriticalCount: 1,
+			MediumCount:   1,
+			LowCount:      2,
+		},
+		Vulnerabilities: []starboardv1alpha1.Vulnerability{
+			{
+				VulnerabilityID:  "CVE-2019-18276",
+				Resource:         "bash",
+				InstalledVersion: "5.0-4",
+				Severity:         starboardv1alpha1.SeverityLow,
+				Title:            "bash: when effective UID is not equal to its real UID the saved UID is not dropped",
+			},
+			{
+				VulnerabilityID:  "CVE-2019-1549",
+				Resource:         "openssl",
+				InstalledVersion: "1.1.1c-r0",
+				FixedVersion:     "1.1.1d-r0",
+				Severity:         starboardv1alpha1.SeverityMedium,
+				Title:            "openssl: information disclosure in fork()",
+			},
+			{
+				VulnerabilityID:  "CVE-2020-9546",
+				Resource:         "com.fasterxml.jackson.core:jackson-databind",
+				InstalledVersion: "2.9.10.3",
+				FixedVersion:     "2.9.10.4",
+				Severity:         starboardv1alpha1.SeverityCritical,
+				Title:            "jackson-databind: Serialization gadgets in shaded-hikari-config",
+			},
+			{
+				VulnerabilityID:  "CVE-2019-1547",
+				Resource:         "openssl",
+				InstalledVersion: "1.1.1c-r0",
+				FixedVersion:     "1.1.1d-r0",
+				Severity:         starboardv1alpha1.SeverityLow,
+				Title:            "openssl: side-channel\nweak encryption vulnerability",
+			},
+		},
+	}
+
+	golden, err := ioutil.ReadFile("testdata/golden.txt")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = table.NewWriter().Write(result, &out)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), out.String())
+}
+
+func TestWriter_Write_MaxTitleWidth(t *testing.T) {
+	result := starboardv1alpha1.VulnerabilityScanResult{
+		Artifact: starboardv1alpha1.Artifact{
+			Repository: "alpine",
+			Tag:        "3.10.2",
+		},
+		Vulnerabilities: []starboardv1alpha1.Vulnerability{
+			{
+				VulnerabilityID: "CVE-2019-1549",
+				Severity:        starboardv1alpha1.SeverityMedium,
+				Title:           "openssl: information disclosure in fork()",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		options       []table.WriterOption
+		expectedTitle string
+	}{
+		{
+			name:          "Should truncate title to max width",
+			options:       []table.WriterOption{table.WithMaxTitleWidth(20)},
+			expectedTitle: "openssl: informat...",
+		},
+		{
+			name:          "Should not truncate title when max width is zero",
+			options:       []table.WriterOption{table.WithMaxTitleWidth(0)},
+			expectedTitle: "openssl: information disclosure in fork()",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := table.NewWriter(tc.options...).Write(result, &out)
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "  "+tc.expectedTitle+"\n")
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package trivy
+
+import (
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+)
+
+// ClusterVulnerabilityReport is the rollup of results of scans of many images,
+// e.g. of all images in a namespace.
+type ClusterVulnerabilityReport struct {
+	// Summary is the summary of the distinct vulnerabilities of all images, so
+	// that a vulnerability of a base image shared by many images is counted
+	// once.
+	Summary starboardv1alpha1.VulnerabilitySummary
+	// Images are the summaries of individual images in the order of results.
+	Images []ImageVulnerabilitySummary
+}
+
+// ImageVulnerabilitySummary is the summary of a result of a scan of an image
+// within a ClusterVulnerabilityReport.
+type ImageVulnerabilitySummary struct {
+	// Image is the reference of the scanned image, e.g.
+	// index.docker.io/library/nginx:1.16.
+	Image        string
+	WorkloadKind string
+	WorkloadName string
+	Namespace    string
+	// Summary is the summary of the result as is.
+	Summary starboardv1alpha1.VulnerabilitySummary
+}
+
+// AggregateResults rolls up the specified results of scans of images into a
+// ClusterVulnerabilityReport. Vulnerabilities of different results are the
+// same if they have the same identifier, package name and installed version.
+// The total summary is computed from the distinct vulnerabilities with the
+// default risk score weights, hence vulnerabilities dropped from truncated
+// results are not counted in it.
+//
+// The specified results are not modified.
+func AggregateResults(results []starboardv1alpha1.VulnerabilityScanResult) ClusterVulnerabilityReport {
+	return AggregateResultsWithWeights(results, starboard.ConfigData{}.GetRiskScoreWeights())
+}
+
+// AggregateResultsWithWeights is like AggregateResults but it computes the
+// total summary with the specified risk score weights, e.g. the ones returned
+// by Config.GetRiskScoreWeights.
+func AggregateResultsWithWeights(results []starboardv1alpha1.VulnerabilityScanResult, weights map[starboardv1alpha1.Severity]int) ClusterVulnerabilityReport {
+	images := make([]ImageVulnerabilitySummary, 0, len(results))
+	var vulnerabilities []starboardv1alpha1.Vulnerability
+	seen := make(map[vulnerabilityKey]bool)
+	for _, result := range results {
+		images = append(images, ImageVulnerabilitySummary{
+			Image:        result.ImageRef(),
+			WorkloadKind: result.WorkloadKind,
+			WorkloadName: result.WorkloadName,
+			Namespace:    result.Namespace,
+			Summary:      result.Summary,
+		})
+		for _, v := range result.Vulnerabilities {
+			key := keyOf(v)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			vulnerabilities = append(vulnerabilities, v)
+		}
+	}
+	return ClusterVulnerabilityReport{
+		Summary: toSummary(vulnerabilities, weights),
+		Images:  images,
+	}
+}
@@ -0,0 +1,88 @@
+package trivy_test
+
+import (
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateResults(t *testing.T) {
+	t.Run("Should return empty report when there are no results", func(t *testing.T) {
+		report := trivy.AggregateResults(nil)
+		assert.Equal(t, 0, report.Summary.Total())
+		assert.Empty(t, report.Images)
+	})
+
+	t.Run("Should count vulnerabilities of shared base image once", func(t *testing.T) {
+		base := []starboardv1alpha1.Vulnerability{
+			{VulnerabilityID: "CVE-2022-0778", Resource: "openssl", InstalledVersion: "1.1.1k-1+deb11u1", FixedVersion: "1.1.1k-1+deb11u2", Severity: starboardv1alpha1.SeverityHigh},
+			{VulnerabilityID: "CVE-2021-33560", Resource: "libgcrypt20", InstalledVersion: "1.8.7-6", Severity: starboardv1alpha1.SeverityLow},
+		}
+		frontend := starboardv1alpha1.VulnerabilityScanResult{
+			Registry:     starboardv1alpha1.Registry{Server: "example.com"},
+			Artifact:     starboardv1alpha1.Artifact{Repository: "shop/frontend", Tag: "1.0"},
+			WorkloadKind: "Deployment",
+			WorkloadName: "frontend",
+			Namespace:    "shop",
+			Summary:      starboardv1alpha1.VulnerabilitySummary{CriticalCount: 1, HighCount: 1, LowCount: 1},
+			Vulnerabilities: append([]starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2021-23337", Resource: "lodash", InstalledVersion: "4.17.20", FixedVersion: "4.17.21", Severity: starboardv1alpha1.SeverityCritical},
+			}, base...),
+		}
+		backend := starboardv1alpha1.VulnerabilityScanResult{
+			Registry:     starboardv1alpha1.Registry{Server: "example.com"},
+			Artifact:     starboardv1alpha1.Artifact{Repository: "shop/backend", Tag: "2.3"},
+			WorkloadKind: "Deployment",
+			WorkloadName: "backend",
+			Namespace:    "shop",
+			Summary:      starboardv1alpha1.VulnerabilitySummary{HighCount: 1, MediumCount: 1, LowCount: 1},
+			Vulnerabilities: append([]starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2022-23491", Resource: "certifi", InstalledVersion: "2021.10.8", Severity: starboardv1alpha1.SeverityMedium},
+			}, base...),
+		}
+
+		report := trivy.AggregateResults([]starboardv1alpha1.VulnerabilityScanResult{frontend, backend})
+
+		assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+			CriticalCount: 1,
+			HighCount:     1,
+			MediumCount:   1,
+			LowCount:      1,
+			RiskScore:     18,
+			Fixable: &starboardv1alpha1.FixableSummary{
+				CriticalCount: 1,
+				HighCount:     1,
+			},
+		}, report.Summary)
+		assert.Equal(t, []trivy.ImageVulnerabilitySummary{
+			{
+				Image:        "example.com/shop/frontend:1.0",
+				WorkloadKind: "Deployment",
+				WorkloadName: "frontend",
+				Namespace:    "shop",
+				Summary:      frontend.Summary,
+			},
+			{
+				Image:        "example.com/shop/backend:2.3",
+				WorkloadKind: "Deployment",
+				WorkloadName: "backend",
+				Namespace:    "shop",
+				Summary:      backend.Summary,
+			},
+		}, report.Images)
+		assert.Equal(t, 3, report.Images[0].Summary.Total())
+		assert.Equal(t, 3, report.Images[1].Summary.Total())
+		assert.Equal(t, 4, report.Summary.Total())
+	})
+
+	t.Run("Should compute total summary with specified weights", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.riskScoreWeights": "HIGH=8"}
+		report := trivy.AggregateResultsWithWeights([]starboardv1alpha1.VulnerabilityScanResult{
+			{Vulnerabilities: []starboardv1alpha1.Vulnerability{{VulnerabilityID: "CVE-2022-0778", Severity: starboardv1alpha1.SeverityHigh}}},
+		}, config.GetRiskScoreWeights())
+		assert.Equal(t, 8, report.Summary.RiskScore)
+	})
+}
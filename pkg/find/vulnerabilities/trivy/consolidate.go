@@ -0,0 +1,73 @@
+package trivy
+
+import (
+	"sort"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+)
+
+// ConsolidateByCVE returns a copy of the specified VulnerabilityScanResult
+// where vulnerabilities with the same identifier, e.g. a CVE that affects many
+// packages, are collapsed into a single entry. The entry is the most severe of
+// the collapsed vulnerabilities, and its AffectedPackages lists the sorted
+// names of all affected packages. The summary and the ecosystem summary of the
+// returned result are recomputed from the consolidated vulnerabilities with the
+// default risk score weights. If the result is truncated, its summaries also
+// count the dropped vulnerabilities, so the collapsed vulnerabilities are
+// discounted from them and the consolidated ones are counted instead.
+//
+// The specified result is not modified.
+func ConsolidateByCVE(result starboardv1alpha1.VulnerabilityScanResult) starboardv1alpha1.VulnerabilityScanResult {
+	return ConsolidateByCVEWithWeights(result, starboard.ConfigData{}.GetRiskScoreWeights())
+}
+
+// ConsolidateByCVEWithWeights is like ConsolidateByCVE but it recomputes the
+// summaries with the specified risk score weights, e.g. the ones returned by
+// Config.GetRiskScoreWeights.
+func ConsolidateByCVEWithWeights(result starboardv1alpha1.VulnerabilityScanResult, weights map[starboardv1alpha1.Severity]int) starboardv1alpha1.VulnerabilityScanResult {
+	if len(result.Vulnerabilities) == 0 {
+		return result
+	}
+	indexes := make(map[string]int)
+	packages := make(map[string]map[string]bool)
+	vulnerabilities := make([]starboardv1alpha1.Vulnerability, 0, len(result.Vulnerabilities))
+	for _, v := range result.Vulnerabilities {
+		if packages[v.VulnerabilityID] == nil {
+			packages[v.VulnerabilityID] = make(map[string]bool)
+		}
+		packages[v.VulnerabilityID][v.Resource] = true
+		i, ok := indexes[v.VulnerabilityID]
+		if !ok {
+			indexes[v.VulnerabilityID] = len(vulnerabilities)
+			vulnerabilities = append(vulnerabilities, v)
+			continue
+		}
+		if v.Severity.Compare(vulnerabilities[i].Severity) > 0 {
+			vulnerabilities[i] = v
+		}
+	}
+	for i, v := range vulnerabilities {
+		affected := make([]string, 0, len(packages[v.VulnerabilityID]))
+		for name := range packages[v.VulnerabilityID] {
+			affected = append(affected, name)
+		}
+		sort.Strings(affected)
+		vulnerabilities[i].AffectedPackages = affected
+	}
+	sortVulnerabilities(vulnerabilities)
+	if result.Truncated {
+		result.Summary = adjustSummary(result.Summary, vulnerabilities, result.Vulnerabilities, weights)
+		if result.EcosystemSummary != nil {
+			result.EcosystemSummary = adjustEcosystemSummary(result.EcosystemSummary, vulnerabilities, result.Vulnerabilities, weights)
+		}
+		result.Vulnerabilities = vulnerabilities
+		return result
+	}
+	result.Vulnerabilities = vulnerabilities
+	result.Summary = toSummary(vulnerabilities, weights)
+	if result.EcosystemSummary != nil {
+		result.EcosystemSummary = toEcosystemSummary(groupByEcosystem(vulnerabilities), weights)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package trivy_test
+
+import (
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidateByCVE(t *testing.T) {
+	t.Run("Should return result without vulnerabilities unchanged", func(t *testing.T) {
+		result := starboardv1alpha1.VulnerabilityScanResult{Vulnerabilities: []starboardv1alpha1.Vulnerability{}}
+		assert.Equal(t, result, trivy.ConsolidateByCVE(result))
+	})
+
+	t.Run("Should collapse vulnerabilities with the same identifier", func(t *testing.T) {
+		config := starboard.ConfigData{
+			"trivy.imageRef": "aquasec/trivy:0.9.1",
+		}
+		result, err := trivy.NewConverter(trivy.WithClock(fixedClock)).
+			ConvertFile(config, "example.com/app:3.0", "testdata/app-3.0-shared-cve.json")
+		require.NoError(t, err)
+		require.Len(t, result.Vulnerabilities, 6)
+		original := append([]starboardv1alpha1.Vulnerability(nil), result.Vulnerabilities...)
+
+		consolidated := trivy.ConsolidateByCVE(result)
+
+		require.Len(t, consolidated.Vulnerabilities, 2)
+		cve := consolidated.Vulnerabilities[0]
+		assert.Equal(t, "CVE-2022-0778", cve.VulnerabilityID)
+		assert.Equal(t, starboardv1alpha1.SeverityHigh, cve.Severity)
+		assert.Equal(t, "cryptography", cve.Resource)
+		assert.Equal(t, []string{"cryptography", "libssl-dev", "libssl1.1", "openssl", "openssl-src"}, cve.AffectedPackages)
+		other := consolidated.Vulnerabilities[1]
+		assert.Equal(t, "CVE-2021-33560", other.VulnerabilityID)
+		assert.Equal(t, starboardv1alpha1.SeverityLow, other.Severity)
+		assert.Equal(t, []string{"libgcrypt20"}, other.AffectedPackages)
+		assert.Equal(t, 1, consolidated.Summary.HighCount)
+		assert.Equal(t, 1, consolidated.Summary.LowCount)
+		assert.Equal(t, 0, consolidated.Summary.MediumCount)
+		var total int
+		for _, summary := range consolidated.EcosystemSummary {
+			total += summary.Total()
+		}
+		assert.Equal(t, consolidated.Summary.Total(), total, "ecosystem summary should match consolidated vulnerabilities")
+
+		assert.Equal(t, original, result.Vulnerabilities)
+		for _, v := range result.Vulnerabilities {
+			assert.Nil(t, v.AffectedPackages)
+		}
+	})
+
+	t.Run("Should recompute summary with specified weights", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.riskScoreWeights": "HIGH=8"}
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2022-0778", Resource: "openssl", Severity: starboardv1alpha1.SeverityHigh},
+				{VulnerabilityID: "CVE-2022-0778", Resource: "libssl1.1", Severity: starboardv1alpha1.SeverityHigh},
+			},
+		}
+		consolidated := trivy.ConsolidateByCVEWithWeights(result, config.GetRiskScoreWeights())
+		assert.Equal(t, 8, consolidated.Summary.RiskScore)
+	})
+
+	t.Run("Should adjust summary of truncated result", func(t *testing.T) {
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2022-0778", Resource: "openssl", FixedVersion: "1.1.1n-0+deb11u1", Severity: starboardv1alpha1.SeverityHigh},
+				{VulnerabilityID: "CVE-2022-0778", Resource: "libssl1.1", Severity: starboardv1alpha1.SeverityHigh},
+			},
+			Summary: starboardv1alpha1.VulnerabilitySummary{
+				HighCount: 4,
+				LowCount:  1,
+				RiskScore: 21,
+				Fixable:   &starboardv1alpha1.FixableSummary{HighCount: 1},
+			},
+			Truncated:    true,
+			DroppedCount: 3,
+		}
+
+		consolidated := trivy.ConsolidateByCVE(result)
+		require.Len(t, consolidated.Vulnerabilities, 1)
+		assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+			HighCount: 3,
+			LowCount:  1,
+			RiskScore: 16,
+			Fixable:   &starboardv1alpha1.FixableSummary{HighCount: 1},
+		}, consolidated.Summary)
+		assert.True(t, consolidated.Truncated)
+		assert.Equal(t, 3, consolidated.DroppedCount)
+	})
+}
@@ -1,140 +1,2322 @@
 package trivy
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/aquasecurity/starboard/pkg/ext"
+	"github.com/aquasecurity/starboard/pkg/kube"
 	"github.com/aquasecurity/starboard/pkg/starboard"
 
 	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/go-logr/logr"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// Converter is the interface that wraps the Convert method.
+// Converter is the interface that wraps methods converting the output of Trivy.
 //
 // Convert converts the vulnerabilities model used by Trivy
 // to a generic model defined by the Custom Security Resource Specification.
+// LimitExceededError is returned if the output is larger than
+// Config.GetMaxScanOutputSize or nested too deep to be the output of Trivy.
+//
+// ConvertWithContext is like Convert but it stops the conversion and returns
+// the context's error as soon as the specified context is done.
+//
+// ConvertAll converts the outputs of Trivy keyed by image references and
+// returns the results keyed by the same references. A failure to convert one
+// output does not prevent converting the others. The results that were
+// converted successfully are returned along with an aggregate of errors.
+//
+// ConvertStream converts the newline-delimited outputs of Trivy, possibly
+// gzip-compressed, where each line is the output of a scan of a different
+// image. The reference of each image is returned by refFor for the decoded
+// output. A malformed line does not prevent converting the others. The
+// results of lines that were converted successfully are returned in the order
+// of lines along with an aggregate of errors.
+//
+// ConvertBytes is like Convert but it reads the output of Trivy from the
+// specified byte slice without copying it.
+//
+// ConvertFile is like Convert but it reads the output of Trivy from the file
+// with the specified path, which is closed before ConvertFile returns.
+//
+// ConvertFilesystem converts the output of a scan of a filesystem, e.g. by
+// trivy fs or trivy rootfs. The result has no registry, and the scanned path
+// or repository is reported as the repository of the artifact.
+//
+// ConvertAndGate is like Convert but it also returns ErrThresholdExceeded with
+// the converted result if any vulnerability of the result is at least as
+// severe as the severity returned by Config.GetFailOnSeverity.
+//
+// ConvertResponse is like Convert but it reads the output of Trivy from the
+// body of the specified HTTP response, e.g. a response of Trivy in server
+// mode, which is closed before ConvertResponse returns. ResponseStatusError is
+// returned if the status code of the response is not 2xx.
+//
+// ConvertEach is like Convert but it passes each converted vulnerability to fn
+// as soon as it's decoded, and returns only the summary of the vulnerabilities
+// passed to fn. The vulnerabilities are passed in the order reported by Trivy,
+// and they are not truncated to Config.GetMaxVulnerabilities. Duplicates are
+// still omitted, but unlike Convert their links and aliases are not merged
+// into the vulnerability, which was already passed to fn. An error returned by
+// fn stops the conversion and is returned as is.
 type Converter interface {
 	Convert(config Config, imageRef string, reader io.Reader) (starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertWithContext(ctx context.Context, config Config, imageRef string, reader io.Reader) (starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertAll(config Config, refs map[string]io.Reader) (map[string]starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertStream(config Config, reader io.Reader, refFor func(report Report) string) ([]starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertBytes(config Config, imageRef string, data []byte) (starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertFile(config Config, imageRef, path string) (starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertFilesystem(config Config, target string, reader io.Reader) (starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertAndGate(config Config, imageRef string, reader io.Reader) (starboardv1alpha1.VulnerabilityScanResult, error)
+	ConvertEach(config Config, imageRef string, reader io.Reader, fn func(v starboardv1alpha1.Vulnerability) error) (starboardv1alpha1.VulnerabilitySummary, error)
+	ConvertResponse(config Config, imageRef string, resp *http.Response) (starboardv1alpha1.VulnerabilityScanResult, error)
+}
+
+// Option configures the Converter returned by NewConverter.
+type Option func(*converter)
+
+// WithLogger sets the logger used to report conversion issues that are not
+// errors, e.g. unrecognized severities. Details of the conversion, such as the
+// number of decoded scan reports, are logged at the debug level V(1). By
+// default nothing is logged.
+func WithLogger(logger logr.Logger) Option {
+	return func(c *converter) {
+		c.logger = logger
+	}
+}
+
+// WithStrictSeverity makes the Converter return an error rather than log
+// a warning when it encounters an unrecognized severity.
+func WithStrictSeverity() Option {
+	return func(c *converter) {
+		c.strictSeverity = true
+	}
+}
+
+// WithClock sets the clock used to timestamp results of scans that do not
+// carry their own completion time. By default the system clock is used.
+func WithClock(clock ext.Clock) Option {
+	return func(c *converter) {
+		c.clock = clock
+	}
+}
+
+// VersionResolver returns the version of the scanner described by the
+// specified Config.
+type VersionResolver func(config Config) (string, error)
+
+// WithVersionResolver sets the resolver of the scanner version reported in
+// results. By default the version is the tag or the digest of the Trivy image
+// reference returned by Config.
+func WithVersionResolver(resolver VersionResolver) Option {
+	return func(c *converter) {
+		c.versionResolver = resolver
+	}
+}
+
+// SeverityClassifier is the interface that wraps the Classify method.
+//
+// Classify returns the severity of the specified vulnerability, which allows
+// overlaying a custom severity policy on ratings reported by Trivy. The
+// Severity of the vulnerability is already normalized to one of the known
+// severities. The returned one is normalized too; an unrecognized one is
+// reported as UNKNOWN along with a warning, or is an error if the severity is
+// strict.
+type SeverityClassifier interface {
+	Classify(v Vulnerability) starboardv1alpha1.Severity
+}
+
+// SeverityClassifierFunc is an adapter to allow the use of an ordinary function
+// as a SeverityClassifier.
+type SeverityClassifierFunc func(v Vulnerability) starboardv1alpha1.Severity
+
+// Classify calls f(v).
+func (f SeverityClassifierFunc) Classify(v Vulnerability) starboardv1alpha1.Severity {
+	return f(v)
+}
+
+// passThroughClassifier is the SeverityClassifier that returns the severity
+// reported by Trivy.
+var passThroughClassifier = SeverityClassifierFunc(func(v Vulnerability) starboardv1alpha1.Severity {
+	return v.Severity
+})
+
+// WithSeverityClassifier sets the classifier of severities of vulnerabilities.
+// Severities are classified before they're compared with the severity
+// threshold and counted in the summary. By default the severity reported by
+// Trivy is used. Config.GetSeverityOverrides takes precedence over the
+// classifier.
+func WithSeverityClassifier(classifier SeverityClassifier) Option {
+	return func(c *converter) {
+		c.classifier = classifier
+	}
+}
+
+// WithRawReport makes the Converter retain the JSON output of Trivy, without
+// the noisy output that precedes it, in the RawReport of results. By default
+// the output is not retained.
+func WithRawReport() Option {
+	return func(c *converter) {
+		c.rawReport = true
+	}
+}
+
+// WithDisallowUnknownFields makes the Converter return an error rather than
+// ignore fields of the output of Trivy that it does not recognize, which
+// allows detecting changes of the output of a new Trivy release. By default
+// unknown fields are ignored for forward compatibility.
+func WithDisallowUnknownFields() Option {
+	return func(c *converter) {
+		c.disallowUnknownFields = true
+	}
+}
+
+// WithScannerVersionStamp makes the Converter set the ScannerVersion of each
+// vulnerability to the version of the scanner, so that it's retained when
+// vulnerabilities are stored apart from the result. By default only the
+// Scanner of the result carries the version.
+func WithScannerVersionStamp() Option {
+	return func(c *converter) {
+		c.scannerVersionStamp = true
+	}
+}
+
+// WithConvertTimeout limits the duration of a conversion of the output of
+// Trivy by Convert, ConvertWithContext and ConvertBytes. When the timeout
+// elapses an error wrapping context.DeadlineExceeded is returned. By default
+// the duration is not limited.
+func WithConvertTimeout(timeout time.Duration) Option {
+	return func(c *converter) {
+		c.convertTimeout = timeout
+	}
+}
+
+// WithExpiredIgnoreWarnings makes the Converter add a warning to the result
+// for each expired rule of the ignore policy returned by Config that would
+// omit a vulnerability of the result otherwise. By default expired rules are
+// silently disregarded.
+func WithExpiredIgnoreWarnings() Option {
+	return func(c *converter) {
+		c.expiredIgnoreWarnings = true
+	}
+}
+
+// WithRejectInvalidImageRefs makes the Converter return InvalidImageRefError
+// when the reference of the scanned image cannot be parsed. By default the
+// raw reference is reported as the repository along with a warning.
+func WithRejectInvalidImageRefs() Option {
+	return func(c *converter) {
+		c.rejectInvalidImageRefs = true
+	}
+}
+
+// WithRejectUnknownVersions makes the Converter return VersionResolutionError
+// when the VersionResolver fails. By default an unknown version is reported
+// along with a warning.
+func WithRejectUnknownVersions() Option {
+	return func(c *converter) {
+		c.rejectUnknownVersions = true
+	}
+}
+
+// PackageNameNormalizer returns the canonical name of the package with the
+// specified name, e.g. openssl for libssl1.1.
+type PackageNameNormalizer func(name string) string
+
+// PackageNameMap returns the PackageNameNormalizer that rewrites package names
+// that are keys of the specified map to the corresponding values. Other names
+// are returned unchanged.
+func PackageNameMap(names map[string]string) PackageNameNormalizer {
+	return func(name string) string {
+		if canonical, ok := names[name]; ok {
+			return canonical
+		}
+		return name
+	}
+}
+
+// identityPackageNameNormalizer is the PackageNameNormalizer that returns the
+// package name reported by Trivy.
+func identityPackageNameNormalizer(name string) string {
+	return name
+}
+
+// WithPackageNameNormalizer sets the normalizer of names of packages affected
+// by vulnerabilities. Names are normalized before vulnerabilities are
+// classified and deduplicated, so vulnerabilities reported for aliases of the
+// same package are reported once. By default the name reported by Trivy is
+// used.
+func WithPackageNameNormalizer(normalizer PackageNameNormalizer) Option {
+	return func(c *converter) {
+		c.packageNameNormalizer = normalizer
+	}
+}
+
+// imageRefWarningPrefix prefixes the warning about a malformed image reference.
+const imageRefWarningPrefix = "parsing image reference"
+
+// unknownVersion is the scanner version reported if the VersionResolver fails.
+const unknownVersion = "unknown"
+
+type converter struct {
+	logger                 logr.Logger
+	strictSeverity         bool
+	clock                  ext.Clock
+	versionResolver        VersionResolver
+	registerer             prometheus.Registerer
+	metrics                *metrics
+	openFile               func(path string) (io.ReadCloser, error)
+	classifier             SeverityClassifier
+	rawReport              bool
+	packageNameNormalizer  PackageNameNormalizer
+	disallowUnknownFields  bool
+	scannerVersionStamp    bool
+	convertTimeout         time.Duration
+	expiredIgnoreWarnings  bool
+	rejectInvalidImageRefs bool
+	rejectUnknownVersions  bool
+}
+
+func openFile(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func imageRefVersionResolver(config Config) (string, error) {
+	return starboard.GetVersionFromImageRef(config.GetTrivyImageRef())
+}
+
+var DefaultConverter = NewConverter()
+
+// NewConverter constructs a new Converter with the specified options.
+func NewConverter(opts ...Option) Converter {
+	c := &converter{
+		logger:                log.NullLogger{},
+		clock:                 ext.NewSystemClock(),
+		versionResolver:       imageRefVersionResolver,
+		openFile:              openFile,
+		classifier:            passThroughClassifier,
+		packageNameNormalizer: identityPackageNameNormalizer,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.registerer != nil {
+		m, err := newMetrics(c.registerer)
+		if err != nil {
+			c.logger.Error(err, "Cannot register metrics, metrics are disabled")
+		}
+		c.metrics = m
+	}
+	return c
+}
+
+func (c *converter) Convert(config Config, imageRef string, reader io.Reader) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	return c.ConvertWithContext(context.Background(), config, imageRef, reader)
+}
+
+func (c *converter) ConvertWithContext(ctx context.Context, config Config, imageRef string, reader io.Reader) (report starboardv1alpha1.VulnerabilityScanResult, err error) {
+	if c.metrics != nil {
+		defer c.observe(c.clock.Now(), &report, &err)
+	}
+	return c.withTimeout(ctx, func(ctx context.Context) (starboardv1alpha1.VulnerabilityScanResult, error) {
+		scanReport, err := c.decode(ctx, config, reader)
+		if err != nil {
+			return starboardv1alpha1.VulnerabilityScanResult{}, withImageRef(err, imageRef)
+		}
+		return c.convert(ctx, config, imageRef, scanReport)
+	})
+}
+
+// withTimeout calls the specified conversion function in a goroutine and
+// returns its result, or an error wrapping context.DeadlineExceeded as soon
+// as the timeout set by WithConvertTimeout elapses. The context passed to the
+// function is done then, so that the goroutine stops at the next read of the
+// output or the next scan report. The function is called directly if there is
+// no timeout.
+func (c *converter) withTimeout(ctx context.Context, fn func(ctx context.Context) (starboardv1alpha1.VulnerabilityScanResult, error)) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	if c.convertTimeout <= 0 {
+		return fn(ctx)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, c.convertTimeout)
+	defer cancel()
+
+	type outcome struct {
+		result starboardv1alpha1.VulnerabilityScanResult
+		err    error
+	}
+	// The channel is buffered, so that the goroutine does not block and
+	// leak if the timeout elapses first.
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := fn(timeoutCtx)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-timeoutCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return starboardv1alpha1.VulnerabilityScanResult{}, err
+		}
+		return starboardv1alpha1.VulnerabilityScanResult{}, fmt.Errorf("conversion timed out after %s: %w", c.convertTimeout, timeoutCtx.Err())
+	}
+}
+
+func (c *converter) ConvertFilesystem(config Config, target string, reader io.Reader) (report starboardv1alpha1.VulnerabilityScanResult, err error) {
+	if c.metrics != nil {
+		defer c.observe(c.clock.Now(), &report, &err)
+	}
+	ctx := context.Background()
+	scanReport, err := c.decode(ctx, config, reader)
+	if err != nil {
+		return
+	}
+	return c.convertReport(ctx, config, scanReport, starboardv1alpha1.Registry{}, starboardv1alpha1.Artifact{Repository: target}, nil)
+}
+
+func (c *converter) ConvertAndGate(config Config, imageRef string, reader io.Reader) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	failOn := config.GetFailOnSeverity()
+	threshold, err := starboardv1alpha1.ParseSeverity(failOn)
+	if failOn != "" && err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, fmt.Errorf("unrecognized fail-on severity: %s", failOn)
+	}
+	report, err := c.Convert(config, imageRef, reader)
+	if err != nil || failOn == "" {
+		return report, err
+	}
+	exceeding := 0
+	for _, v := range report.Vulnerabilities {
+		if v.Severity.Compare(threshold) >= 0 {
+			exceeding++
+		}
+	}
+	if exceeding > 0 {
+		return report, fmt.Errorf("%w: %d vulnerabilities of severity %s or higher", ErrThresholdExceeded, exceeding, failOn)
+	}
+	return report, nil
+}
+
+// decode decodes the output of Trivy read from the specified reader, which may
+// be compressed and preceded by noisy output.
+func (c *converter) decode(ctx context.Context, config Config, reader io.Reader) (Report, error) {
+	skipReader, err := c.jsonReader(ctx, config, reader)
+	if err != nil {
+		return Report{}, err
+	}
+	var raw []byte
+	if c.rawReport {
+		if raw, err = ioutil.ReadAll(skipReader); err != nil {
+			return Report{}, err
+		}
+		skipReader = bytes.NewReader(raw)
+	}
+	scanReport, err := c.decodeScanReports(ctx, skipReader, nil)
+	if err != nil {
+		return Report{}, err
+	}
+	c.logger.V(1).Info("Decoded scan reports", "count", len(scanReport.Results))
+	scanReport.raw = raw
+	return scanReport, nil
+}
+
+// jsonReader returns the reader of the JSON output of Trivy read from the
+// specified reader, which may be compressed and preceded by noisy output. The
+// size of the output after decompression is limited to
+// Config.GetMaxScanOutputSize.
+func (c *converter) jsonReader(ctx context.Context, config Config, reader io.Reader) (io.Reader, error) {
+	plainReader, err := c.decompressingReader(&contextReader{ctx: ctx, reader: reader})
+	if err != nil {
+		return nil, err
+	}
+	return c.skippingNoisyOutputReader(&sizeLimitingReader{reader: plainReader, max: config.GetMaxScanOutputSize()})
+}
+
+// ConvertEach decodes the scan reports one at a time, so that neither the
+// scan reports nor the converted vulnerabilities are retained. Hence the
+// references of duplicates cannot be merged into vulnerabilities passed to fn.
+func (c *converter) ConvertEach(config Config, imageRef string, reader io.Reader, fn func(v starboardv1alpha1.Vulnerability) error) (starboardv1alpha1.VulnerabilitySummary, error) {
+	vc, err := c.newVulnerabilityConverter(config)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilitySummary{}, err
+	}
+	version := ""
+	if c.scannerVersionStamp {
+		if version, err = c.versionResolver(config); err != nil {
+			if c.rejectUnknownVersions {
+				return starboardv1alpha1.VulnerabilitySummary{}, &VersionResolutionError{Err: err}
+			}
+			c.logger.Info("Stamping unknown scanner version", "error", err.Error())
+			version = unknownVersion
+		}
+	}
+	weights := config.GetRiskScoreWeights()
+	summary := starboardv1alpha1.VulnerabilitySummary{Fixable: &starboardv1alpha1.FixableSummary{}}
+	// The error returned by fn is kept apart, so that it's returned as is
+	// rather than as an error of decoding the output.
+	var fnErr error
+	visit := func(report ScanReport) error {
+		if report.Class == ClassSecret || report.Class == ClassConfig {
+			return nil
+		}
+		for _, sr := range report.Vulnerabilities {
+			v, ok, err := vc.convert(report, sr)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			v.ScannerVersion = version
+			addToSummary(&summary, v, weights)
+			if fnErr = fn(v); fnErr != nil {
+				return fnErr
+			}
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	jsonReader, err := c.jsonReader(ctx, config, reader)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilitySummary{}, err
+	}
+	_, err = c.decodeScanReports(ctx, jsonReader, visit)
+	if fnErr != nil {
+		return starboardv1alpha1.VulnerabilitySummary{}, fnErr
+	}
+	if err != nil {
+		return starboardv1alpha1.VulnerabilitySummary{}, withImageRef(err, imageRef)
+	}
+	c.logger.V(1).Info("Converted vulnerabilities one at a time", "detected", vc.detected)
+	return summary, nil
+}
+
+func (c *converter) ConvertBytes(config Config, imageRef string, data []byte) (report starboardv1alpha1.VulnerabilityScanResult, err error) {
+	if bytes.HasPrefix(data, gzipMagic) {
+		return c.Convert(config, imageRef, bytes.NewReader(data))
+	}
+	if c.metrics != nil {
+		defer c.observe(c.clock.Now(), &report, &err)
+	}
+	return c.withTimeout(context.Background(), func(ctx context.Context) (starboardv1alpha1.VulnerabilityScanResult, error) {
+		return c.convertBytes(ctx, config, imageRef, data)
+	})
+}
+
+// convertBytes converts the output of Trivy that is already in memory and is
+// not compressed.
+func (c *converter) convertBytes(ctx context.Context, config Config, imageRef string, data []byte) (report starboardv1alpha1.VulnerabilityScanResult, err error) {
+	if err = c.checkSize(config, data); err != nil {
+		return
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	offset := c.jsonOffset(data)
+	noise := data
+	if offset < 0 {
+		c.logger.V(1).Info("Beginning of JSON output not found, decoding whole output")
+		offset = 0
+	} else {
+		c.logSkipped(offset)
+		noise = data[:offset]
+	}
+	if err = toScanError(noise); err != nil {
+		return
+	}
+	scanReport, err := c.decodeScanReports(ctx, bytes.NewReader(data[offset:]), nil)
+	if err != nil {
+		err = withImageRef(err, imageRef)
+		return
+	}
+	c.logger.V(1).Info("Decoded scan reports", "count", len(scanReport.Results))
+	if c.rawReport {
+		// Copy the output, so that the result does not retain the whole data.
+		scanReport.raw = append([]byte(nil), data[offset:]...)
+	}
+	return c.convert(ctx, config, imageRef, scanReport)
+}
+
+// jsonOffset returns the offset of the line that begins the JSON output in the
+// specified data, or -1 if there is no such line. It's the counterpart of
+// skippingNoisyOutputReader for data that is already in memory.
+func (c *converter) jsonOffset(data []byte) int {
+	for start := 0; start < len(data); {
+		line := data[start:]
+		if n := leadingNoiseLen(line); n > 0 {
+			start += n
+			continue
+		}
+		if bytes.HasPrefix(line, nullLiteral) {
+			return start
+		}
+		if bytes.HasPrefix(line, []byte("[")) || bytes.HasPrefix(line, []byte("{")) {
+			window := line
+			if len(window) > jsonStartWindowSize {
+				window = window[:jsonStartWindowSize]
+			}
+			if c.isJSONArrayStart(window) || c.isReportStart(window) {
+				return start
+			}
+		}
+		end := bytes.IndexAny(line, "\r\n")
+		if end < 0 {
+			return -1
+		}
+		start += end + 1
+	}
+	return -1
+}
+
+// jsonStartWindowSize is the number of bytes inspected to check whether a line
+// begins the JSON output.
+const jsonStartWindowSize = 4096
+
+// gzipMagic is the header that identifies gzip-compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func (c *converter) ConvertFile(config Config, imageRef, path string) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	file, err := c.openFile(path)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, fmt.Errorf("opening report file %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	result, err := c.Convert(config, imageRef, file)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, fmt.Errorf("converting report file %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// maxResponseSnippet is the maximum length of the beginning of the body of an
+// unsuccessful response reported by ResponseStatusError.
+const maxResponseSnippet = 512
+
+// responseMediaTypes are the media types of responses that may carry the
+// output of Trivy. Media types with the +json suffix and responses without a
+// content type are accepted too.
+var responseMediaTypes = map[string]bool{
+	"application/json":         true,
+	"application/gzip":         true,
+	"application/x-gzip":       true,
+	"application/octet-stream": true,
+	"text/plain":               true,
+}
+
+func (c *converter) ConvertResponse(config Config, imageRef string, resp *http.Response) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+		return starboardv1alpha1.VulnerabilityScanResult{}, &ResponseStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       strings.TrimSpace(string(snippet)),
+		}
+	}
+	if err := c.checkContentType(resp.Header.Get("Content-Type")); err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, err
+	}
+	result, err := c.Convert(config, imageRef, resp.Body)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, fmt.Errorf("converting trivy server response: %w", err)
+	}
+	return result, nil
+}
+
+// checkContentType checks whether the specified content type of a response
+// is one of the responseMediaTypes, so that e.g. an HTML error page of a proxy
+// is not mistaken for noisy output.
+func (c *converter) checkContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("parsing content type of trivy server response: %w", err)
+	}
+	if responseMediaTypes[mediaType] || strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+	return fmt.Errorf("unsupported content type of trivy server response: %s", mediaType)
+}
+
+func (c *converter) ConvertAll(config Config, refs map[string]io.Reader) (map[string]starboardv1alpha1.VulnerabilityScanResult, error) {
+	imageRefs := make([]string, 0, len(refs))
+	for imageRef := range refs {
+		imageRefs = append(imageRefs, imageRef)
+	}
+	// Sort image references so that aggregated errors are reported in a
+	// deterministic order.
+	sort.Strings(imageRefs)
+
+	results := make(map[string]starboardv1alpha1.VulnerabilityScanResult, len(refs))
+	var errs []error
+	for _, imageRef := range imageRefs {
+		result, err := c.Convert(config, imageRef, refs[imageRef])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting report of image %s: %w", imageRef, err))
+			continue
+		}
+		results[imageRef] = result
+	}
+	return results, utilerrors.NewAggregate(errs)
+}
+
+func (c *converter) ConvertStream(config Config, reader io.Reader, refFor func(report Report) string) ([]starboardv1alpha1.VulnerabilityScanResult, error) {
+	plainReader, err := c.decompressingReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	lineReader := bufio.NewReader(plainReader)
+
+	results := make([]starboardv1alpha1.VulnerabilityScanResult, 0)
+	var errs []error
+	for number := 1; ; number++ {
+		line, err := lineReader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			errs = append(errs, fmt.Errorf("reading line %d: %w", number, err))
+			break
+		}
+		if number == 1 {
+			line = bytes.TrimPrefix(line, utf8BOM)
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			result, convertErr := c.convertLine(config, line, refFor)
+			if convertErr != nil {
+				errs = append(errs, fmt.Errorf("converting line %d: %w", number, convertErr))
+			} else {
+				results = append(results, result)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return results, utilerrors.NewAggregate(errs)
+}
+
+// convertLine converts the output of Trivy on the specified line of the
+// stream converted by ConvertStream.
+func (c *converter) convertLine(config Config, line []byte, refFor func(report Report) string) (report starboardv1alpha1.VulnerabilityScanResult, err error) {
+	if c.metrics != nil {
+		defer c.observe(c.clock.Now(), &report, &err)
+	}
+	if err = c.checkSize(config, line); err != nil {
+		return
+	}
+	ctx := context.Background()
+	scanReport, err := c.decodeScanReports(ctx, bytes.NewReader(line), nil)
+	if err != nil {
+		return
+	}
+	imageRef := refFor(scanReport)
+	return c.convert(ctx, config, imageRef, scanReport)
+}
+
+// checkSize checks whether the specified output of Trivy that is already in
+// memory is within Config.GetMaxScanOutputSize.
+func (c *converter) checkSize(config Config, data []byte) error {
+	if max := config.GetMaxScanOutputSize(); int64(len(data)) > max {
+		return &LimitExceededError{Limit: limitSize, Max: max}
+	}
+	return nil
+}
+
+// decompressingReader transparently decompresses the specified reader if it
+// starts with the gzip header. Otherwise the data is returned unchanged.
+func (c *converter) decompressingReader(reader io.Reader) (io.Reader, error) {
+	bufReader := bufio.NewReader(reader)
+	header, err := bufReader.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.Equal(header, gzipMagic) {
+		return bufReader, nil
+	}
+	gzipReader, err := gzip.NewReader(bufReader)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing gzip scan output: %w", err)
+	}
+	return gzipReader, nil
+}
+
+// decodeScanReports decodes the JSON array of scan reports one element at a
+// time, checking in between whether the specified context is done.
+//
+// Both the legacy output, which is a bare array of scan reports, and the
+// schema-versioned Report, which holds the array in the Results field, are
+// supported. The legacy output is returned as a Report with the Results
+// field only.
+//
+// If visit is not nil, each decoded scan report is passed to visit rather than
+// returned in the Results field, so that scan reports are not retained.
+//
+// Decoding fails with LimitExceededError as soon as JSON arrays and objects
+// are nested deeper than maxNestingDepth.
+func (c *converter) decodeScanReports(ctx context.Context, reader io.Reader, visit func(ScanReport) error) (Report, error) {
+	counter := &countingReader{reader: &depthLimitingReader{reader: reader, max: maxNestingDepth}}
+	decoder := json.NewDecoder(counter)
+	if c.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	report, err := c.decodeTopLevel(ctx, decoder, visit)
+	if err != nil {
+		return Report{}, toMalformedOutputError(err, counter.count)
+	}
+	return report, nil
+}
+
+// decodeTopLevel decodes the top-level JSON value of the output of Trivy.
+func (c *converter) decodeTopLevel(ctx context.Context, decoder *json.Decoder, visit func(ScanReport) error) (Report, error) {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return Report{}, ErrEmptyScanOutput
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	switch token {
+	// Trivy outputs the null literal if it does not detect any OS packages.
+	case nil:
+		return Report{}, nil
+	case json.Delim('['):
+		results, err := c.decodeScanReportArray(ctx, decoder, visit)
+		return Report{Results: results}, err
+	case json.Delim('{'):
+		return c.decodeReport(ctx, decoder, visit)
+	default:
+		return Report{}, &MalformedOutputError{Err: fmt.Errorf("expected JSON array or object of scan reports but got: %v", token)}
+	}
+}
+
+// decodeReport decodes the schema-versioned Report whose opening brace has
+// already been consumed. The Results field is decoded one element at a time,
+// and fields other than those of the Report are skipped unless unknown fields
+// are disallowed.
+func (c *converter) decodeReport(ctx context.Context, decoder *json.Decoder, visit func(ScanReport) error) (Report, error) {
+	var report Report
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return Report{}, err
+		}
+		switch token {
+		case "Results":
+			report.Results, err = c.decodeReportResults(ctx, decoder, visit)
+		case "SchemaVersion":
+			err = decoder.Decode(&report.SchemaVersion)
+		case "ArtifactName":
+			err = decoder.Decode(&report.ArtifactName)
+		case "ArtifactType":
+			err = decoder.Decode(&report.ArtifactType)
+		case "Metadata":
+			err = decoder.Decode(&report.Metadata)
+		default:
+			if c.disallowUnknownFields {
+				return Report{}, &MalformedOutputError{Err: fmt.Errorf("json: unknown field %q", token)}
+			}
+			var skipped json.RawMessage
+			err = decoder.Decode(&skipped)
+		}
+		if err != nil {
+			return Report{}, err
+		}
+	}
+	_, err := decoder.Token()
+	if err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// decodeReportResults decodes the value of the Results field of the
+// schema-versioned Report, which is either the JSON array of scan reports or
+// the null literal.
+func (c *converter) decodeReportResults(ctx context.Context, decoder *json.Decoder, visit func(ScanReport) error) ([]ScanReport, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case nil:
+		return nil, nil
+	case json.Delim('['):
+		return c.decodeScanReportArray(ctx, decoder, visit)
+	default:
+		return nil, &MalformedOutputError{Err: fmt.Errorf("expected JSON array of results but got: %v", token)}
+	}
+}
+
+// decodeScanReportArray decodes elements of the JSON array of scan reports
+// whose opening bracket has already been consumed, including the closing
+// bracket. Each element is passed to visit instead of being returned if visit
+// is not nil.
+func (c *converter) decodeScanReportArray(ctx context.Context, decoder *json.Decoder, visit func(ScanReport) error) ([]ScanReport, error) {
+	var reports []ScanReport
+	var err error
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var report ScanReport
+		err = decoder.Decode(&report)
+		if err != nil {
+			return nil, err
+		}
+		if visit != nil {
+			if err := visit(report); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		reports = append(reports, report)
+	}
+	_, err = decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// countingReader is an io.Reader that counts the bytes read.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// contextReader is an io.Reader that fails with the context's error
+// once the context is done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// TODO Normally I'd use Trivy with the --quiet flag, but in case of errors it does suppress the error message.
+// TODO Therefore, as a workaround I do sanitize the input reader before we start parsing the JSON output.
+//
+// The input is scanned line by line until a line that begins with the JSON
+// array, the JSON object of the schema-versioned Report, or the null literal
+// is found. The returned reader is positioned at the
+// beginning of that line, so the JSON output is never loaded into memory as a
+// whole. Only the skipped lines are buffered, so that the whole input can be
+// returned if the beginning of the JSON output is never found.
+//
+// Lines end with a line feed or a carriage return, because progress bars, e.g.
+// "Downloading DB... 50%", are redrawn after a carriage return and the last
+// one may be directly followed by the JSON output.
+//
+// A line that begins with a square bracket, e.g. a log message such as
+// "[1/2] Downloading DB", is only considered the beginning of the JSON output
+// if it's followed by a JSON object or the end of the array. Similarly, a line
+// that begins with a curly brace is only considered the beginning of the JSON
+// output if the first field is one of the reportFields.
+//
+// Spaces, tabs, and ANSI escape sequences at the beginning of a line, e.g.
+// color codes written when Trivy is run without the --no-color flag, are
+// discarded, so that they do not hide the beginning of the JSON output.
+//
+// A UTF-8 byte order mark at the beginning of the input, e.g. written by
+// pipelines run on Windows, is discarded as well.
+//
+// ScanError is returned if the skipped lines report that the scan failed.
+func (c *converter) skippingNoisyOutputReader(input io.Reader) (io.Reader, error) {
+	reader := bufio.NewReaderSize(input, jsonStartWindowSize)
+	if err := c.discardBOM(reader); err != nil {
+		return nil, err
+	}
+	var skipped bytes.Buffer
+	for {
+		prefix, err := reader.Peek(maxANSIEscapeSize)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if n := leadingNoiseLen(prefix); n > 0 {
+			skipped.Write(prefix[:n])
+			if _, err := reader.Discard(n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if bytes.HasPrefix(prefix, nullLiteral) {
+			c.logSkipped(skipped.Len())
+			return reader, toScanError(skipped.Bytes())
+		}
+		if bytes.HasPrefix(prefix, []byte("[")) || bytes.HasPrefix(prefix, []byte("{")) {
+			window, err := reader.Peek(reader.Size())
+			if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+				return nil, err
+			}
+			if c.isJSONArrayStart(window) || c.isReportStart(window) {
+				c.logSkipped(skipped.Len())
+				return reader, toScanError(skipped.Bytes())
+			}
+		}
+		err = c.skipLine(reader, &skipped)
+		if err == io.EOF {
+			c.logger.V(1).Info("Beginning of JSON output not found, decoding whole output", "size", skipped.Len())
+			return &skipped, toScanError(skipped.Bytes())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// utf8BOM is the byte order mark of UTF-8 encoded text.
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// discardBOM discards the UTF-8 byte order mark that begins the specified
+// reader, if any.
+func (c *converter) discardBOM(reader *bufio.Reader) error {
+	prefix, err := reader.Peek(len(utf8BOM))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if !bytes.Equal(prefix, utf8BOM) {
+		return nil
+	}
+	_, err = reader.Discard(len(utf8BOM))
+	return err
+}
+
+// skipLine reads the rest of the current line from the specified reader,
+// including the line feed or the carriage return that ends it, and writes it
+// to the given buffer. It returns io.EOF if the input ends before the end of
+// the line.
+func (c *converter) skipLine(reader *bufio.Reader, skipped *bytes.Buffer) error {
+	for {
+		window, err := reader.Peek(reader.Size())
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return err
+		}
+		n := len(window)
+		end := bytes.IndexAny(window, "\r\n")
+		if end >= 0 {
+			n = end + 1
+		}
+		skipped.Write(window[:n])
+		if _, err := reader.Discard(n); err != nil {
+			return err
+		}
+		if end >= 0 {
+			return nil
+		}
+		if err == io.EOF {
+			return io.EOF
+		}
+	}
+}
+
+var nullLiteral = []byte("null")
+
+// maxANSIEscapeSize is the maximum size of an ANSI escape sequence recognized
+// by ansiEscapeLen. It's also large enough to peek the null literal.
+const maxANSIEscapeSize = 32
+
+// leadingNoiseLen returns the length of the spaces and tabs, or of the ANSI
+// escape sequence, that begin the specified data.
+func leadingNoiseLen(data []byte) int {
+	if n := ansiEscapeLen(data); n > 0 {
+		return n
+	}
+	n := 0
+	for n < len(data) && (data[n] == ' ' || data[n] == '\t') {
+		n++
+	}
+	return n
+}
+
+// ansiEscapeLen returns the length of the ANSI control sequence, such as
+// "\x1b[1;34m", that begins the specified data, or 0 if the data does not
+// begin with a complete control sequence.
+func ansiEscapeLen(data []byte) int {
+	if len(data) < 2 || data[0] != 0x1b || data[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(data) && i < maxANSIEscapeSize; i++ {
+		switch b := data[i]; {
+		case b >= 0x20 && b <= 0x3f:
+			// Parameter or intermediate byte.
+		case b >= 0x40 && b <= 0x7e:
+			return i + 1
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// logSkipped logs the offset of the JSON output if any noisy output preceded it.
+func (c *converter) logSkipped(offset int) {
+	if offset > 0 {
+		c.logger.V(1).Info("Skipped noisy output", "offset", offset)
+	}
+}
+
+// isJSONArrayStart checks whether the specified data begins a JSON array of
+// objects. The data may be truncated, therefore running out of data before the
+// first element of the array is not considered an error.
+func (c *converter) isJSONArrayStart(data []byte) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return false
+	}
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return true
+	}
+	return err == nil && (token == json.Delim('{') || token == json.Delim(']'))
+}
+
+// reportFields are the names of fields of the schema-versioned Report, one of
+// which is expected to begin the JSON object output by Trivy.
+var reportFields = map[string]bool{
+	"SchemaVersion": true,
+	"ArtifactName":  true,
+	"ArtifactType":  true,
+	"Metadata":      true,
+	"Results":       true,
+}
+
+// isReportStart checks whether the specified data begins a JSON object of the
+// schema-versioned Report. Checking the first field name prevents structured
+// log messages from being mistaken for the report.
+func (c *converter) isReportStart(data []byte) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return false
+	}
+	token, err := decoder.Token()
+	if err != nil {
+		return false
+	}
+	field, ok := token.(string)
+	return ok && reportFields[field]
+}
+
+// vulnerabilityKey identifies the same vulnerability reported more than once,
+// e.g. for different targets of a multi-layer image.
+type vulnerabilityKey struct {
+	VulnerabilityID  string
+	PkgName          string
+	InstalledVersion string
+}
+
+// convert converts the specified Report of a scan of the image with the given
+// reference.
+func (c *converter) convert(ctx context.Context, config Config, imageRef string, scanReport Report) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	registry, artifact, warnings, err := c.resolveImage(config, imageRef)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, err
+	}
+	return c.convertReport(ctx, config, scanReport, registry, artifact, warnings)
+}
+
+// resolveImage returns the registry and the artifact of the image with the
+// specified reference. The findings of a scan are still useful if the image
+// reference is malformed, hence the raw reference is reported as the
+// repository along with a warning, unless invalid image references are
+// rejected, in which case InvalidImageRefError is returned.
+func (c *converter) resolveImage(config Config, imageRef string) (starboardv1alpha1.Registry, starboardv1alpha1.Artifact, []string, error) {
+	registry, artifact, err := c.parseImageRef(config, imageRef)
+	if err != nil {
+		refErr := &InvalidImageRefError{ImageRef: imageRef, Err: err}
+		if c.rejectInvalidImageRefs {
+			return starboardv1alpha1.Registry{}, starboardv1alpha1.Artifact{}, nil, refErr
+		}
+		return starboardv1alpha1.Registry{}, starboardv1alpha1.Artifact{Repository: imageRef}, []string{refErr.Error()}, nil
+	}
+	return registry, artifact, nil, nil
+}
+
+// convertReport converts the specified Report of a scan of the given artifact.
+// The warnings are the ones encountered while the artifact was resolved.
+func (c *converter) convertReport(ctx context.Context, config Config, scanReport Report,
+	registry starboardv1alpha1.Registry, artifact starboardv1alpha1.Artifact, warnings []string) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	vc, err := c.newVulnerabilityConverter(config)
+	if err != nil {
+		return starboardv1alpha1.VulnerabilityScanResult{}, err
+	}
+
+	vulnerabilities := make([]starboardv1alpha1.Vulnerability, 0)
+	var secrets []starboardv1alpha1.SecretFinding
+	var targets []string
+	ecosystems := make(map[string][]starboardv1alpha1.Vulnerability)
+
+	for _, report := range scanReport.Results {
+		if err := ctx.Err(); err != nil {
+			return starboardv1alpha1.VulnerabilityScanResult{}, err
+		}
+		targets = append(targets, report.Target)
+		for _, secret := range report.Secrets {
+			secrets = append(secrets, c.toSecretFinding(report.Target, secret))
+		}
+		if report.Class == ClassSecret || report.Class == ClassConfig {
+			continue
+		}
+		for _, sr := range report.Vulnerabilities {
+			v, ok, err := vc.convert(report, sr)
+			if err != nil {
+				return starboardv1alpha1.VulnerabilityScanResult{}, err
+			}
+			if !ok {
+				continue
+			}
+			vulnerabilities = append(vulnerabilities, v)
+			ecosystem := toEcosystem(report.Type)
+			ecosystems[ecosystem] = append(ecosystems[ecosystem], v)
+		}
+	}
+	vc.mergeDuplicateReferences(vulnerabilities)
+	c.logger.V(1).Info("Filtered vulnerabilities", "detected", vc.detected, "kept", len(vulnerabilities),
+		"belowThreshold", vc.stats.BelowThreshold, "belowMinScore", vc.stats.BelowMinScore,
+		"ignored", vc.stats.Ignored, "unfixed", vc.stats.Unfixed, "deduplicated", vc.stats.Deduplicated,
+		"excludedPackages", vc.stats.ExcludedPackages)
+
+	artifact.Architecture = scanReport.Metadata.ImageConfig.Architecture
+	imageType := c.toImageType(scanReport)
+	c.logger.V(1).Info("Classified image", "imageType", imageType)
+	if c.expiredIgnoreWarnings {
+		warnings = append(warnings, c.toExpiredIgnoreWarnings(vc.expiredIgnores)...)
+	}
+	warnings = append(warnings, c.toClassificationWarnings(vc.unrecognizedClassifications)...)
+
+	version, err := c.versionResolver(config)
+	if err != nil {
+		versionErr := &VersionResolutionError{Err: err}
+		if c.rejectUnknownVersions {
+			return starboardv1alpha1.VulnerabilityScanResult{}, versionErr
+		}
+		c.logger.Info("Reporting unknown scanner version", "error", err.Error())
+		warnings = append(warnings, versionErr.Error())
+		version = unknownVersion
+	}
+	if c.scannerVersionStamp {
+		for i := range vulnerabilities {
+			vulnerabilities[i].ScannerVersion = version
+		}
+	}
+
+	updateTimestamp, scanDuration := c.toScanTimes(config)
+	workload := c.toWorkload(config)
+
+	sortVulnerabilities(vulnerabilities)
+
+	// The summary is computed before truncation so that it reflects all
+	// detected vulnerabilities.
+	summary := toSummary(vulnerabilities, config.GetRiskScoreWeights())
+	ecosystemSummary := toEcosystemSummary(ecosystems, config.GetRiskScoreWeights())
+	vulnerabilities, dropped := c.truncate(vulnerabilities, config.GetMaxVulnerabilities())
+	if dropped > 0 {
+		c.logger.V(1).Info("Truncated vulnerabilities", "kept", len(vulnerabilities), "dropped", dropped)
+	}
+
+	return starboardv1alpha1.VulnerabilityScanResult{
+		Scanner: starboardv1alpha1.Scanner{
+			Name:    config.GetScannerName(),
+			Vendor:  config.GetScannerVendor(),
+			Version: version,
+		},
+		Registry:         registry,
+		Artifact:         artifact,
+		OSFamily:         scanReport.Metadata.OS.GetFamily(),
+		OSVersion:        scanReport.Metadata.OS.GetName(),
+		ImageType:        imageType,
+		Summary:          summary,
+		Vulnerabilities:  vulnerabilities,
+		UpdateTimestamp:  updateTimestamp,
+		ScanDuration:     scanDuration,
+		Truncated:        dropped > 0,
+		DroppedCount:     dropped,
+		Warnings:         warnings,
+		WorkloadKind:     string(workload.Kind),
+		WorkloadName:     workload.Name,
+		Namespace:        workload.Namespace,
+		Secrets:          secrets,
+		ScannedTargets:   targets,
+		RawReport:        scanReport.raw,
+		EcosystemSummary: ecosystemSummary,
+		FilterStats:      vc.filterStats(),
+	}, nil
+}
+
+// toImageType classifies the scanned image by the targets of the specified
+// Report. An image without a target of OS packages is not exceptional, it's
+// classified as distroless if it has a target of language packages and as
+// scratch otherwise. An empty ImageType is returned for artifacts other than
+// images.
+func (c *converter) toImageType(scanReport Report) starboardv1alpha1.ImageType {
+	if scanReport.ArtifactType != "" && scanReport.ArtifactType != ArtifactTypeContainerImage {
+		return ""
+	}
+	if scanReport.Metadata.OS != nil {
+		return starboardv1alpha1.ImageTypeFull
+	}
+	imageType := starboardv1alpha1.ImageTypeScratch
+	for _, report := range scanReport.Results {
+		switch {
+		case report.Class == ClassSecret || report.Class == ClassConfig:
+			continue
+		case c.isOSTarget(report):
+			return starboardv1alpha1.ImageTypeFull
+		default:
+			imageType = starboardv1alpha1.ImageTypeDistroless
+		}
+	}
+	return imageType
+}
+
+// isOSTarget checks whether the specified report is a report of OS packages.
+// Reports of older Trivy releases that have neither a Class nor a Type are
+// considered reports of OS packages, because the kind of their targets is
+// unknown.
+func (c *converter) isOSTarget(report ScanReport) bool {
+	if report.Class != "" {
+		return report.Class == ClassOSPackages
+	}
+	return report.Type == "" || osTypes[report.Type]
+}
+
+// omitExcludedFields clears the optional fields of the specified vulnerability
+// that are not in the specified set of included fields. All fields are kept if
+// the set is nil.
+func (c *converter) omitExcludedFields(v *starboardv1alpha1.Vulnerability, included map[string]bool) {
+	if included == nil {
+		return
+	}
+	if !included["title"] {
+		v.Title = ""
+	}
+	if !included["description"] {
+		v.Description = ""
+	}
+	if !included["links"] {
+		v.Links = []string{}
+		v.CategorizedLinks = nil
+	}
+	if !included["primaryURL"] {
+		v.PrimaryURL = ""
+	}
+	if !included["cweIDs"] {
+		v.CweIDs = []string{}
+	}
 }
 
-type converter struct {
+// vulnerabilityConverter converts the vulnerabilities of a single output of
+// Trivy one at a time. Vulnerabilities that are filtered out by the Config, or
+// that were already converted, are omitted.
+type vulnerabilityConverter struct {
+	*converter
+	threshold      int
+	minScore       float64
+	dropUnscored   bool
+	ignoreUnfixed  bool
+	ignorePolicy   starboard.IgnorePolicy
+	overrides      starboard.SeverityOverrides
+	includedFields map[string]bool
+	// includePackages and excludePackages are the glob patterns of names of
+	// included and excluded packages.
+	includePackages []string
+	excludePackages []string
+	maxLinks        int
+	now             time.Time
+	// seen are the references of converted vulnerabilities merged with the
+	// references of their duplicates.
+	seen map[vulnerabilityKey]*references
+	// detected is the number of vulnerabilities passed to convert.
+	detected int
+	// stats are the numbers of vulnerabilities omitted by convert.
+	stats starboardv1alpha1.FilterStats
+	// expiredIgnores are the expired rules of the ignore policy that matched
+	// converted vulnerabilities.
+	expiredIgnores []starboard.IgnoreRule
+	// unrecognizedClassifications are the distinct unrecognized severities
+	// returned by the SeverityClassifier.
+	unrecognizedClassifications []string
 }
 
-var DefaultConverter = NewConverter()
+func (c *converter) newVulnerabilityConverter(config Config) (*vulnerabilityConverter, error) {
+	threshold, err := c.severityThreshold(config)
+	if err != nil {
+		return nil, err
+	}
+	return &vulnerabilityConverter{
+		converter:       c,
+		threshold:       threshold,
+		minScore:        config.GetMinScore(),
+		dropUnscored:    config.GetDropUnscored(),
+		ignoreUnfixed:   config.GetIgnoreUnfixed(),
+		ignorePolicy:    config.GetIgnorePolicy(),
+		overrides:       config.GetSeverityOverrides(),
+		includedFields:  config.GetIncludedFields(),
+		includePackages: config.GetIncludePackages(),
+		excludePackages: config.GetExcludePackages(),
+		maxLinks:        config.GetMaxLinks(),
+		now:             c.clock.Now(),
+		seen:            make(map[vulnerabilityKey]*references),
+	}, nil
+}
 
-func NewConverter() Converter {
-	return &converter{}
+// references are the references and aliases of a vulnerability reported by
+// Trivy, and of its duplicates if it has any.
+type references struct {
+	links   []string
+	aliases []string
+	// merged indicates whether references of duplicates were merged.
+	merged bool
 }
 
-func (c *converter) Convert(config Config, imageRef string, reader io.Reader) (report starboardv1alpha1.VulnerabilityScanResult, err error) {
-	var scanReports []ScanReport
-	skipReader, err := c.skippingNoisyOutputReader(reader)
+// convert converts the specified vulnerability detected in the target of the
+// given scan report. It returns false if the vulnerability is omitted.
+func (vc *vulnerabilityConverter) convert(report ScanReport, sr Vulnerability) (starboardv1alpha1.Vulnerability, bool, error) {
+	vc.detected++
+	severity, err := vc.toSeverity(sr)
 	if err != nil {
-		return
+		return starboardv1alpha1.Vulnerability{}, false, err
 	}
-	err = json.NewDecoder(skipReader).Decode(&scanReports)
-	if err != nil {
-		return
+	sr.Severity = severity
+	sr.PkgName = vc.packageNameNormalizer(sr.PkgName)
+	if !vc.isPackageIncluded(sr.PkgName) {
+		vc.stats.ExcludedPackages++
+		return starboardv1alpha1.Vulnerability{}, false, nil
+	}
+	var aliases []string
+	sr.VulnerabilityID, aliases = vc.toCanonicalID(sr)
+	ids := append([]string{sr.VulnerabilityID}, aliases...)
+	if rule, ok := vc.ignorePolicy.Find(ids...); ok {
+		if !rule.IsExpired(vc.now) {
+			vc.stats.Ignored++
+			return starboardv1alpha1.Vulnerability{}, false, nil
+		}
+		vc.expiredIgnores = append(vc.expiredIgnores, rule)
+	}
+	if severity, err = vc.classify(sr); err != nil {
+		return starboardv1alpha1.Vulnerability{}, false, err
+	}
+	if override, ok := vc.overrides.Find(sr.PkgName, ids...); ok {
+		severity = override.Severity
+	}
+	if severity.Rank() < vc.threshold {
+		vc.stats.BelowThreshold++
+		return starboardv1alpha1.Vulnerability{}, false, nil
+	}
+	score, vector := vc.toCVSS(sr.CVSS)
+	if !vc.hasMinScore(score) {
+		vc.stats.BelowMinScore++
+		return starboardv1alpha1.Vulnerability{}, false, nil
 	}
-	return c.convert(config, imageRef, scanReports)
+	if vc.ignoreUnfixed && len(toFixedVersions(sr.FixedVersion)) == 0 {
+		vc.stats.Unfixed++
+		return starboardv1alpha1.Vulnerability{}, false, nil
+	}
+	key := vulnerabilityKey{
+		VulnerabilityID:  sr.VulnerabilityID,
+		PkgName:          sr.PkgName,
+		InstalledVersion: sr.InstalledVersion,
+	}
+	if refs, ok := vc.seen[key]; ok {
+		vc.stats.Deduplicated++
+		refs.links = unionStrings(refs.links, sr.References)
+		refs.aliases = unionStrings(refs.aliases, aliases)
+		refs.merged = true
+		return starboardv1alpha1.Vulnerability{}, false, nil
+	}
+	vc.seen[key] = &references{links: sr.References, aliases: aliases}
+	links, categorizedLinks := vc.toLinks(sr.References, vc.maxLinks)
+	v := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  sr.VulnerabilityID,
+		Resource:         sr.PkgName,
+		PkgPath:          sr.PkgPath,
+		PkgIdentifier:    vc.toPkgIdentifier(report.Type, sr),
+		InstalledVersion: sr.InstalledVersion,
+		FixedVersion:     sr.FixedVersion,
+		FixedVersions:    toFixedVersions(sr.FixedVersion),
+		Severity:         severity,
+		VendorSeverity:   vc.toVendorSeverity(sr.VendorSeverity),
+		Status:           vc.toStatus(sr),
+		Title:            sr.Title,
+		Description:      sr.Description,
+		Links:            links,
+		PrimaryURL:       vc.toPrimaryURL(sr.PrimaryURL, sr.References),
+		Score:            score,
+		CVSSVector:       vector,
+		Target:           report.Target,
+		Layer:            vc.toLayer(sr.Layer),
+		CweIDs:           vc.toCweIDs(sr.CweIDs),
+		Aliases:          aliases,
+		PublishedDate:    vc.toDate(sr.VulnerabilityID, "PublishedDate", sr.PublishedDate),
+		LastModifiedDate: vc.toDate(sr.VulnerabilityID, "LastModifiedDate", sr.LastModifiedDate),
+		DataSource:       vc.toDataSource(sr.DataSource),
+		CategorizedLinks: categorizedLinks,
+		Ecosystem:        toEcosystem(report.Type),
+	}
+	vc.omitExcludedFields(&v, vc.includedFields)
+	return v, true, nil
 }
 
-// TODO Normally I'd use Trivy with the --quiet flag, but in case of errors it does suppress the error message.
-// TODO Therefore, as a workaround I do sanitize the input reader before we start parsing the JSON output.
-func (c *converter) skippingNoisyOutputReader(input io.Reader) (io.Reader, error) {
-	inputAsBytes, err := ioutil.ReadAll(input)
-	if err != nil {
-		return nil, err
+// mergeDuplicateReferences replaces the links and aliases of the specified
+// vulnerabilities that had duplicates with the union of the references and
+// aliases of the vulnerability and all its duplicates, so that deduplication
+// does not lose references reported only for a duplicate.
+func (vc *vulnerabilityConverter) mergeDuplicateReferences(vulnerabilities []starboardv1alpha1.Vulnerability) {
+	for i := range vulnerabilities {
+		v := &vulnerabilities[i]
+		refs, ok := vc.seen[keyOf(*v)]
+		if !ok || !refs.merged {
+			continue
+		}
+		v.Links, v.CategorizedLinks = vc.toLinks(refs.links, vc.maxLinks)
+		v.Aliases = refs.aliases
+		vc.omitExcludedFields(v, vc.includedFields)
 	}
-	inputAsString := string(inputAsBytes)
+}
 
-	index := strings.Index(inputAsString, "\n[")
-	if index > 0 {
-		return strings.NewReader(inputAsString[index:]), nil
+// unionStrings returns the distinct strings of the specified slices in the
+// order they first appear. It returns nil if both slices are nil.
+func unionStrings(a, b []string) []string {
+	if a == nil && b == nil {
+		return nil
 	}
-	index = strings.LastIndex(inputAsString, "null")
-	if index > 0 {
-		return strings.NewReader(inputAsString[index:]), nil
+	union := make([]string, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, values := range [][]string{a, b} {
+		for _, value := range values {
+			if seen[value] {
+				continue
+			}
+			seen[value] = true
+			union = append(union, value)
+		}
 	}
-	return strings.NewReader(inputAsString), nil
+	return union
 }
 
-func (c *converter) convert(config Config, imageRef string, reports []ScanReport) (starboardv1alpha1.VulnerabilityScanResult, error) {
-	vulnerabilities := make([]starboardv1alpha1.Vulnerability, 0)
+// filterStats returns the numbers of vulnerabilities omitted by convert, or
+// nil if none were omitted.
+func (vc *vulnerabilityConverter) filterStats() *starboardv1alpha1.FilterStats {
+	if vc.stats.Total() == 0 {
+		return nil
+	}
+	stats := vc.stats
+	return &stats
+}
 
-	for _, report := range reports {
-		for _, sr := range report.Vulnerabilities {
-			vulnerabilities = append(vulnerabilities, starboardv1alpha1.Vulnerability{
-				VulnerabilityID:  sr.VulnerabilityID,
-				Resource:         sr.PkgName,
-				InstalledVersion: sr.InstalledVersion,
-				FixedVersion:     sr.FixedVersion,
-				Severity:         sr.Severity,
-				Title:            sr.Title,
-				Description:      sr.Description,
-				Links:            c.toLinks(sr.References),
-			})
+// isPackageIncluded checks whether vulnerabilities of the package with the
+// specified name are kept. A package matching any of the excludePackages
+// patterns is not included. Otherwise it's included if it matches any of the
+// includePackages patterns, or if there are no such patterns.
+func (vc *vulnerabilityConverter) isPackageIncluded(pkgName string) bool {
+	if matchesAny(pkgName, vc.excludePackages) {
+		return false
+	}
+	return len(vc.includePackages) == 0 || matchesAny(pkgName, vc.includePackages)
+}
+
+// hasMinScore checks whether the specified CVSS score is at least the minimum
+// score. A missing score is accepted unless unscored vulnerabilities are
+// dropped. Any score is accepted if the minimum score is not set.
+func (vc *vulnerabilityConverter) hasMinScore(score *float64) bool {
+	if vc.minScore <= 0 {
+		return true
+	}
+	if score == nil {
+		return !vc.dropUnscored
+	}
+	return *score >= vc.minScore
+}
+
+// sortVulnerabilities sorts the specified vulnerabilities by severity, the most
+// severe first, then by vulnerability identifier, package name and installed
+// version, so that the order does not depend on the order in which Trivy
+// reported them.
+func sortVulnerabilities(vulnerabilities []starboardv1alpha1.Vulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		vi, vj := vulnerabilities[i], vulnerabilities[j]
+		if cmp := vi.Severity.Compare(vj.Severity); cmp != 0 {
+			return cmp > 0
+		}
+		if vi.VulnerabilityID != vj.VulnerabilityID {
+			return vi.VulnerabilityID < vj.VulnerabilityID
 		}
+		if vi.Resource != vj.Resource {
+			return vi.Resource < vj.Resource
+		}
+		return vi.InstalledVersion < vj.InstalledVersion
+	})
+}
+
+// truncate keeps up to max vulnerabilities with the highest severity and
+// returns the number of dropped vulnerabilities. The vulnerabilities must be
+// sorted by sortVulnerabilities. The vulnerabilities are not truncated if max
+// is zero.
+func (c *converter) truncate(vulnerabilities []starboardv1alpha1.Vulnerability, max int) ([]starboardv1alpha1.Vulnerability, int) {
+	if max <= 0 || len(vulnerabilities) <= max {
+		return vulnerabilities, 0
 	}
+	return vulnerabilities[:max], len(vulnerabilities) - max
+}
 
-	registry, artifact, err := c.parseImageRef(imageRef)
-	if err != nil {
-		return starboardv1alpha1.VulnerabilityScanResult{}, err
+// toScanTimes returns the update timestamp and the duration of the scan
+// described by the specified Config. If the Config does not carry the
+// completion time of the scan, the current time and zero duration are
+// returned.
+func (c *converter) toScanTimes(config Config) (metav1.Time, metav1.Duration) {
+	sc, ok := config.(*scanConfig)
+	if !ok || sc.completionTime.IsZero() {
+		return metav1.NewTime(c.clock.Now()), metav1.Duration{}
 	}
+	var duration time.Duration
+	if !sc.startTime.IsZero() {
+		duration = sc.completionTime.Sub(sc.startTime)
+	}
+	return metav1.NewTime(sc.completionTime), metav1.Duration{Duration: duration}
+}
 
-	version, err := starboard.GetVersionFromImageRef(config.GetTrivyImageRef())
-	if err != nil {
-		return starboardv1alpha1.VulnerabilityScanResult{}, err
+func (c *converter) toSecretFinding(target string, secret Secret) starboardv1alpha1.SecretFinding {
+	severity, err := starboardv1alpha1.ParseSeverity(string(secret.Severity))
+	if err != nil || !isTrivySeverity(severity) {
+		severity = starboardv1alpha1.SeverityUnknown
 	}
+	return starboardv1alpha1.SecretFinding{
+		RuleID:    secret.RuleID,
+		Category:  secret.Category,
+		Severity:  severity,
+		Title:     secret.Title,
+		Target:    target,
+		StartLine: secret.StartLine,
+		EndLine:   secret.EndLine,
+	}
+}
 
-	return starboardv1alpha1.VulnerabilityScanResult{
-		Scanner: starboardv1alpha1.Scanner{
-			Name:    "Trivy",
-			Vendor:  "Aqua Security",
-			Version: version,
-		},
-		Registry:        registry,
-		Artifact:        artifact,
-		Summary:         c.toSummary(vulnerabilities),
-		Vulnerabilities: vulnerabilities,
-	}, nil
+// toWorkload returns the workload described by the specified Config, or the
+// zero Object if the Config does not carry the workload.
+func (c *converter) toWorkload(config Config) kube.Object {
+	if sc, ok := config.(*scanConfig); ok {
+		return sc.workload
+	}
+	return kube.Object{}
+}
+
+// vulnerabilityStatuses are the vulnerability statuses reported by Trivy.
+var vulnerabilityStatuses = map[starboardv1alpha1.VulnerabilityStatus]bool{
+	starboardv1alpha1.VulnerabilityStatusUnknown:            true,
+	starboardv1alpha1.VulnerabilityStatusNotAffected:        true,
+	starboardv1alpha1.VulnerabilityStatusAffected:           true,
+	starboardv1alpha1.VulnerabilityStatusFixed:              true,
+	starboardv1alpha1.VulnerabilityStatusUnderInvestigation: true,
+	starboardv1alpha1.VulnerabilityStatusWillNotFix:         true,
+	starboardv1alpha1.VulnerabilityStatusFixDeferred:        true,
+	starboardv1alpha1.VulnerabilityStatusEndOfLife:          true,
+}
+
+// toStatus returns the status of the specified vulnerability normalized to the
+// canonical lowercase form. An empty status is mapped to the affected status,
+// which is what Trivy reported before it reported statuses at all. A status
+// that is not recognized is logged and mapped to the unknown status.
+func (c *converter) toStatus(v Vulnerability) starboardv1alpha1.VulnerabilityStatus {
+	status := starboardv1alpha1.VulnerabilityStatus(strings.ToLower(strings.TrimSpace(v.Status)))
+	if status == "" {
+		return starboardv1alpha1.VulnerabilityStatusAffected
+	}
+	if !vulnerabilityStatuses[status] {
+		c.logger.Info("Mapping unrecognized status to unknown", "vulnerabilityID", v.VulnerabilityID, "status", v.Status)
+		return starboardv1alpha1.VulnerabilityStatusUnknown
+	}
+	return status
+}
+
+// toSeverity returns the severity of the specified vulnerability normalized to
+// the canonical uppercase form. An empty severity is mapped to the unknown
+// severity. A severity that is not recognized is logged and mapped to the
+// unknown severity, unless the Converter is in strict mode, in which case an
+// error is returned.
+func (c *converter) toSeverity(v Vulnerability) (starboardv1alpha1.Severity, error) {
+	if strings.TrimSpace(string(v.Severity)) == "" {
+		return starboardv1alpha1.SeverityUnknown, nil
+	}
+	if severity, err := starboardv1alpha1.ParseSeverity(string(v.Severity)); err == nil && isTrivySeverity(severity) {
+		return severity, nil
+	}
+	if c.strictSeverity {
+		return "", fmt.Errorf("unrecognized severity of vulnerability %s: %q", v.VulnerabilityID, v.Severity)
+	}
+	c.logger.Info("Mapping unrecognized severity to UNKNOWN", "vulnerabilityID", v.VulnerabilityID, "severity", v.Severity)
+	return starboardv1alpha1.SeverityUnknown, nil
+}
+
+// classify returns the severity of the specified vulnerability returned by the
+// SeverityClassifier in its canonical form. An unrecognized severity, or one
+// that Trivy does not report such as NONE, is mapped to UNKNOWN unless the
+// severity is strict, so that the vulnerability is still reported and counted
+// in the summary.
+func (vc *vulnerabilityConverter) classify(v Vulnerability) (starboardv1alpha1.Severity, error) {
+	classified := vc.classifier.Classify(v)
+	if severity, err := starboardv1alpha1.ParseSeverity(string(classified)); err == nil && isTrivySeverity(severity) {
+		return severity, nil
+	}
+	if vc.strictSeverity {
+		return "", fmt.Errorf("unrecognized severity of vulnerability %s returned by classifier: %q", v.VulnerabilityID, classified)
+	}
+	vc.logger.Info("Mapping unrecognized classified severity to UNKNOWN", "vulnerabilityID", v.VulnerabilityID, "severity", classified)
+	vc.unrecognizedClassifications = unionStrings(vc.unrecognizedClassifications, []string{string(classified)})
+	return starboardv1alpha1.SeverityUnknown, nil
+}
+
+// toClassificationWarnings returns the warnings about the specified
+// unrecognized severities returned by the SeverityClassifier.
+func (c *converter) toClassificationWarnings(severities []string) []string {
+	var warnings []string
+	for _, severity := range severities {
+		warnings = append(warnings, fmt.Sprintf("severity classifier returned unrecognized severity %q, reported as UNKNOWN", severity))
+	}
+	return warnings
+}
+
+// severityThreshold returns the rank of the minimum severity configured for
+// vulnerability reports, or zero, the rank of the least severe severity, if
+// the threshold is not set.
+func (c *converter) severityThreshold(config Config) (int, error) {
+	value := config.GetSeverityThreshold()
+	if value == "" {
+		return 0, nil
+	}
+	severity, err := starboardv1alpha1.ParseSeverity(value)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognized severity threshold: %s", value)
+	}
+	return severity.Rank(), nil
 }
 
-func (c *converter) toLinks(references []string) []string {
+// toLinks returns the specified references limited to the maximum number of
+// links, and the same links categorized by type. If references exceed the
+// limit, NVD links are kept first, then other HTTPS links, and then the
+// remaining ones, each in the order they're reported. A limit of zero keeps
+// all references.
+func (c *converter) toLinks(references []string, max int) ([]string, *starboardv1alpha1.CategorizedLinks) {
 	if references == nil {
-		return []string{}
+		return []string{}, nil
 	}
-	return references
+	links := references
+	if max > 0 && len(references) > max {
+		links = append([]string{}, references...)
+		sort.SliceStable(links, func(i, j int) bool {
+			return linkRank(links[i]) < linkRank(links[j])
+		})
+		links = links[:max]
+	}
+	return links, toCategorizedLinks(links)
 }
 
-func (c *converter) toSummary(vulnerabilities []starboardv1alpha1.Vulnerability) (vs starboardv1alpha1.VulnerabilitySummary) {
-	for _, v := range vulnerabilities {
-		switch v.Severity {
-		case starboardv1alpha1.SeverityCritical:
-			vs.CriticalCount++
-		case starboardv1alpha1.SeverityHigh:
-			vs.HighCount++
-		case starboardv1alpha1.SeverityMedium:
-			vs.MediumCount++
-		case starboardv1alpha1.SeverityLow:
-			vs.LowCount++
+// toCategorizedLinks groups the specified links by type guessed from the
+// domain and the path of each link. Links to NVD, Red Hat, and other security
+// trackers are advisories, links to GitHub or GitLab commits are patches, and
+// links to Exploit Database or Packet Storm are exploits. A link of unknown
+// type is categorized as other. It returns nil if there are no links.
+func toCategorizedLinks(links []string) *starboardv1alpha1.CategorizedLinks {
+	if len(links) == 0 {
+		return nil
+	}
+	categorized := &starboardv1alpha1.CategorizedLinks{}
+	for _, link := range links {
+		switch linkCategory(link) {
+		case linkCategoryAdvisory:
+			categorized.Advisory = append(categorized.Advisory, link)
+		case linkCategoryPatch:
+			categorized.Patch = append(categorized.Patch, link)
+		case linkCategoryExploit:
+			categorized.Exploit = append(categorized.Exploit, link)
 		default:
-			vs.UnknownCount++
+			categorized.Other = append(categorized.Other, link)
+		}
+	}
+	return categorized
+}
+
+const (
+	linkCategoryAdvisory = "advisory"
+	linkCategoryPatch    = "patch"
+	linkCategoryExploit  = "exploit"
+	linkCategoryOther    = "other"
+)
+
+var (
+	advisoryDomains = []string{
+		"nvd.nist.gov",
+		"cve.mitre.org",
+		"access.redhat.com",
+		"bugzilla.redhat.com",
+		"security-tracker.debian.org",
+		"ubuntu.com",
+		"security.alpinelinux.org",
+	}
+	exploitDomains = []string{
+		"exploit-db.com",
+		"packetstormsecurity.com",
+	}
+	patchDomains = []string{
+		"github.com",
+		"gitlab.com",
+	}
+)
+
+// linkCategory returns the category of the specified link.
+func linkCategory(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Host == "" {
+		return linkCategoryOther
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case hasDomain(host, exploitDomains):
+		return linkCategoryExploit
+	case hasDomain(host, advisoryDomains):
+		return linkCategoryAdvisory
+	case hasDomain(host, patchDomains) && strings.Contains(u.Path, "/commit/"):
+		return linkCategoryPatch
+	case host == "github.com" && strings.HasPrefix(u.Path, "/advisories/"):
+		return linkCategoryAdvisory
+	default:
+		return linkCategoryOther
+	}
+}
+
+// hasDomain checks whether the specified host is one of the given domains or
+// a subdomain of any of them.
+func hasDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkRank ranks the specified link by preference, the lower the better.
+func linkRank(link string) int {
+	switch {
+	case strings.HasPrefix(link, "https://nvd.nist.gov"):
+		return 0
+	case strings.HasPrefix(link, "https://"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+var (
+	cveIDPattern  = regexp.MustCompile(`(?i)\bCVE-[0-9]{4}-[0-9]{4,}\b`)
+	ghsaIDPattern = regexp.MustCompile(`(?i)\bGHSA(-[0-9a-z]{4}){3}\b`)
+)
+
+// toCanonicalID returns the canonical identifier of the specified
+// vulnerability and its aliases, which are GHSA identifiers found in the
+// references. A GHSA identifier is replaced with the CVE identifier found in
+// the references, unless there are several of them. Identifiers are
+// normalized to the CVE-2021-23337 and GHSA-35jh-r3h4-6jhm forms.
+func (c *converter) toCanonicalID(v Vulnerability) (string, []string) {
+	id := c.toCanonicalForm(strings.TrimSpace(v.VulnerabilityID))
+	isCVE := cveIDPattern.FindString(id) == id
+	isGHSA := ghsaIDPattern.FindString(id) == id
+	if !isCVE && !isGHSA {
+		return id, nil
+	}
+
+	var cves, ghsas []string
+	seen := map[string]bool{id: true}
+	for _, reference := range append([]string{v.PrimaryURL}, v.References...) {
+		for _, match := range cveIDPattern.FindAllString(reference, -1) {
+			if match = c.toCanonicalForm(match); !seen[match] {
+				seen[match] = true
+				cves = append(cves, match)
+			}
+		}
+		for _, match := range ghsaIDPattern.FindAllString(reference, -1) {
+			if match = c.toCanonicalForm(match); !seen[match] {
+				seen[match] = true
+				ghsas = append(ghsas, match)
+			}
+		}
+	}
+	if isGHSA && len(cves) == 1 {
+		return cves[0], append([]string{id}, ghsas...)
+	}
+	return id, ghsas
+}
+
+// toCanonicalForm returns the specified CVE or GHSA identifier with the prefix
+// in uppercase and, for GHSA identifiers, the rest in lowercase.
+func (c *converter) toCanonicalForm(id string) string {
+	upper := strings.ToUpper(id)
+	if strings.HasPrefix(upper, "GHSA-") {
+		return "GHSA-" + strings.ToLower(id[len("GHSA-"):])
+	}
+	if strings.HasPrefix(upper, "CVE-") {
+		return upper
+	}
+	return id
+}
+
+// javaTypes are the types of targets that contain Java packages.
+var javaTypes = map[string]bool{
+	"jar":    true,
+	"pom":    true,
+	"gradle": true,
+	"sbt":    true,
+}
+
+// toPkgIdentifier returns the identifiers of the specified vulnerable package
+// if it's a Java package, or nil otherwise. The Maven coordinates are parsed
+// from the Package URL reported by Trivy, e.g.
+// pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1, or, if there is no
+// Package URL, from the package name in the groupId:artifactId format and the
+// installed version. The Package URL is built from the coordinates in the
+// latter case.
+func (c *converter) toPkgIdentifier(targetType string, sr Vulnerability) *starboardv1alpha1.PkgIdentifier {
+	if !javaTypes[targetType] {
+		return nil
+	}
+	var identifier starboardv1alpha1.PkgIdentifier
+	if sr.PkgIdentifier != nil && sr.PkgIdentifier.PURL != "" {
+		identifier.PURL = sr.PkgIdentifier.PURL
+		identifier.GroupID, identifier.ArtifactID, identifier.Version = c.parseMavenPURL(identifier.PURL)
+	} else if parts := strings.SplitN(sr.PkgName, ":", 2); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		identifier.GroupID, identifier.ArtifactID, identifier.Version = parts[0], parts[1], sr.InstalledVersion
+		identifier.PURL = fmt.Sprintf("pkg:maven/%s/%s", url.PathEscape(parts[0]), url.PathEscape(parts[1]))
+		if sr.InstalledVersion != "" {
+			identifier.PURL += "@" + url.PathEscape(sr.InstalledVersion)
+		}
+	}
+	if identifier == (starboardv1alpha1.PkgIdentifier{}) {
+		return nil
+	}
+	return &identifier
+}
+
+// parseMavenPURL returns the groupId, the artifactId and the version of the
+// specified Package URL of the maven type, or empty strings if it's not such a
+// Package URL. Qualifiers and subpath are ignored.
+func (c *converter) parseMavenPURL(purl string) (groupID, artifactID, version string) {
+	const prefix = "pkg:maven/"
+	if !strings.HasPrefix(purl, prefix) {
+		return "", "", ""
+	}
+	path := strings.TrimPrefix(purl, prefix)
+	if index := strings.IndexAny(path, "?#"); index >= 0 {
+		path = path[:index]
+	}
+	if index := strings.LastIndex(path, "@"); index >= 0 {
+		path, version = path[:index], path[index+1:]
+	}
+	index := strings.LastIndex(path, "/")
+	if index < 0 {
+		return "", "", ""
+	}
+	groupID, artifactID = path[:index], path[index+1:]
+	unescape := func(s string) string {
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			return unescaped
+		}
+		return s
+	}
+	return unescape(groupID), unescape(artifactID), unescape(version)
+}
+
+// toVendorSeverity returns the names of the severities rated by vendors keyed
+// by the name of the vendor, or nil if no vendor rated the vulnerability.
+func (c *converter) toVendorSeverity(vendorSeverity VendorSeverity) map[string]string {
+	if len(vendorSeverity) == 0 {
+		return nil
+	}
+	severities := make(map[string]string, len(vendorSeverity))
+	for vendor, severity := range vendorSeverity {
+		severities[vendor] = string(severity)
+	}
+	return severities
+}
+
+func (c *converter) toCweIDs(cweIDs []string) []string {
+	if cweIDs == nil {
+		return []string{}
+	}
+	return cweIDs
+}
+
+// toFixedVersions splits the fixed version reported by Trivy, e.g.
+// "1.2.3, 2.0.1", into individual versions.
+func toFixedVersions(fixedVersion string) []string {
+	versions := []string{}
+	for _, version := range strings.Split(fixedVersion, ",") {
+		if version = strings.TrimSpace(version); version != "" {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// toPrimaryURL returns the URL of the authoritative advisory. The primary URL
+// reported by Trivy is preferred, then the first NVD reference, and finally
+// the first reference of any kind.
+func (c *converter) toPrimaryURL(primaryURL string, references []string) string {
+	if primaryURL != "" {
+		return primaryURL
+	}
+	for _, reference := range references {
+		if strings.HasPrefix(reference, "https://nvd.nist.gov") {
+			return reference
+		}
+	}
+	if len(references) > 0 {
+		return references[0]
+	}
+	return ""
+}
+
+// toCVSS returns the CVSS base score and vector reported by the NVD, or by the
+// other vendors in lexical order. CVSS v3 data of any vendor takes precedence
+// over v2, which is only returned if no vendor reports v3 data. A nil score is
+// returned if there's no CVSS data at all.
+func (c *converter) toCVSS(cvss map[string]CVSS) (*float64, string) {
+	if len(cvss) == 0 {
+		return nil, ""
+	}
+	vendors := make([]string, 0, len(cvss))
+	for vendor := range cvss {
+		if vendor != "nvd" {
+			vendors = append(vendors, vendor)
+		}
+	}
+	sort.Strings(vendors)
+	if _, ok := cvss["nvd"]; ok {
+		vendors = append([]string{"nvd"}, vendors...)
+	}
+	for _, vendor := range vendors {
+		if data := cvss[vendor]; data.V3Score > 0 || data.V3Vector != "" {
+			score := data.V3Score
+			return &score, data.V3Vector
+		}
+	}
+	for _, vendor := range vendors {
+		if data := cvss[vendor]; data.V2Score > 0 || data.V2Vector != "" {
+			score := data.V2Score
+			return &score, data.V2Vector
+		}
+	}
+	return nil, ""
+}
+
+// toDate parses the specified RFC3339 timestamp. A nil time is returned if the
+// timestamp is empty or malformed, because a missing date is no reason to fail
+// the whole conversion.
+func (c *converter) toDate(vulnerabilityID, field, value string) *metav1.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.logger.Info("Ignoring malformed date", "vulnerabilityID", vulnerabilityID, "field", field, "error", err.Error())
+		return nil
+	}
+	return &metav1.Time{Time: date}
+}
+
+func (c *converter) toDataSource(dataSource *DataSource) starboardv1alpha1.DataSource {
+	if dataSource == nil {
+		return starboardv1alpha1.DataSource{}
+	}
+	return starboardv1alpha1.DataSource{
+		ID:   dataSource.ID,
+		Name: dataSource.Name,
+		URL:  dataSource.URL,
+	}
+}
+
+func (c *converter) toLayer(layer Layer) *starboardv1alpha1.Layer {
+	if layer.Digest == "" && layer.DiffID == "" {
+		return nil
+	}
+	return &starboardv1alpha1.Layer{
+		Digest: layer.Digest,
+		DiffID: layer.DiffID,
+	}
+}
+
+// toExpiredIgnoreWarnings returns the warnings about the specified expired
+// rules of the ignore policy. There's one warning per rule.
+func (c *converter) toExpiredIgnoreWarnings(rules []starboard.IgnoreRule) []string {
+	var warnings []string
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if seen[rule.ID] {
+			continue
 		}
+		seen[rule.ID] = true
+		warnings = append(warnings, fmt.Sprintf("ignore rule of %s expired on %s", rule.ID, rule.ExpiresAt.Format("2006-01-02")))
+	}
+	return warnings
+}
+
+// unknownEcosystem is the ecosystem of vulnerabilities detected in targets
+// without a type.
+const unknownEcosystem = "unknown"
+
+// toEcosystem returns the ecosystem of vulnerabilities detected in the target
+// of the specified type.
+func toEcosystem(targetType string) string {
+	if targetType == "" {
+		return unknownEcosystem
+	}
+	return targetType
+}
+
+// toEcosystemSummary returns summaries of the specified vulnerabilities keyed
+// by ecosystem, or nil if there are no vulnerabilities.
+func toEcosystemSummary(ecosystems map[string][]starboardv1alpha1.Vulnerability, weights map[starboardv1alpha1.Severity]int) map[string]starboardv1alpha1.VulnerabilitySummary {
+	if len(ecosystems) == 0 {
+		return nil
+	}
+	summaries := make(map[string]starboardv1alpha1.VulnerabilitySummary, len(ecosystems))
+	for ecosystem, vulnerabilities := range ecosystems {
+		summaries[ecosystem] = toSummary(vulnerabilities, weights)
+	}
+	return summaries
+}
+
+// groupByEcosystem returns the specified vulnerabilities keyed by their
+// Ecosystem.
+func groupByEcosystem(vulnerabilities []starboardv1alpha1.Vulnerability) map[string][]starboardv1alpha1.Vulnerability {
+	ecosystems := make(map[string][]starboardv1alpha1.Vulnerability)
+	for _, v := range vulnerabilities {
+		ecosystem := toEcosystem(v.Ecosystem)
+		ecosystems[ecosystem] = append(ecosystems[ecosystem], v)
+	}
+	return ecosystems
+}
+
+// toSummary returns the summary of the specified vulnerabilities, whose risk
+// score is computed with the given weights of severities.
+func toSummary(vulnerabilities []starboardv1alpha1.Vulnerability, weights map[starboardv1alpha1.Severity]int) (vs starboardv1alpha1.VulnerabilitySummary) {
+	vs.Fixable = &starboardv1alpha1.FixableSummary{}
+	for _, v := range vulnerabilities {
+		addToSummary(&vs, v, weights)
 	}
 	return
 }
 
-func (c *converter) parseImageRef(imageRef string) (starboardv1alpha1.Registry, starboardv1alpha1.Artifact, error) {
-	ref, err := name.ParseReference(imageRef)
+// addToSummary counts the specified vulnerability in the given summary, whose
+// Fixable summary must not be nil.
+func addToSummary(vs *starboardv1alpha1.VulnerabilitySummary, v starboardv1alpha1.Vulnerability, weights map[starboardv1alpha1.Severity]int) {
+	vs.RiskScore += weights[v.Severity]
+	fixable := len(toFixedVersions(v.FixedVersion)) > 0
+	switch v.Severity {
+	case starboardv1alpha1.SeverityCritical:
+		vs.CriticalCount++
+		if fixable {
+			vs.Fixable.CriticalCount++
+		}
+	case starboardv1alpha1.SeverityHigh:
+		vs.HighCount++
+		if fixable {
+			vs.Fixable.HighCount++
+		}
+	case starboardv1alpha1.SeverityMedium:
+		vs.MediumCount++
+		if fixable {
+			vs.Fixable.MediumCount++
+		}
+	case starboardv1alpha1.SeverityLow:
+		vs.LowCount++
+		if fixable {
+			vs.Fixable.LowCount++
+		}
+	case starboardv1alpha1.SeverityUnknown:
+		vs.UnknownCount++
+		if fixable {
+			vs.Fixable.UnknownCount++
+		}
+	case starboardv1alpha1.SeverityNone:
+		vs.NoneCount++
+	}
+}
+
+// adjustSummary returns the specified summary of a truncated result, which
+// also counts the dropped vulnerabilities and thus cannot be recomputed, with
+// the given added vulnerabilities counted and the removed ones discounted.
+func adjustSummary(vs starboardv1alpha1.VulnerabilitySummary, added, removed []starboardv1alpha1.Vulnerability, weights map[starboardv1alpha1.Severity]int) starboardv1alpha1.VulnerabilitySummary {
+	adjusted := subtractSummaries(addSummaries(vs, toSummary(added, weights)), toSummary(removed, weights))
+	if vs.Fixable == nil {
+		adjusted.Fixable = nil
+	}
+	return adjusted
+}
+
+// adjustEcosystemSummary is like adjustSummary but it adjusts the summaries of
+// ecosystems of a truncated result. Ecosystems without vulnerabilities are
+// omitted, and nil is returned if no ecosystem is left.
+func adjustEcosystemSummary(summaries map[string]starboardv1alpha1.VulnerabilitySummary, added, removed []starboardv1alpha1.Vulnerability, weights map[starboardv1alpha1.Severity]int) map[string]starboardv1alpha1.VulnerabilitySummary {
+	adjusted := make(map[string]starboardv1alpha1.VulnerabilitySummary, len(summaries))
+	for ecosystem, summary := range summaries {
+		adjusted[ecosystem] = summary
+	}
+	addedByEcosystem := groupByEcosystem(added)
+	removedByEcosystem := groupByEcosystem(removed)
+	for ecosystem := range removedByEcosystem {
+		if _, ok := addedByEcosystem[ecosystem]; !ok {
+			addedByEcosystem[ecosystem] = nil
+		}
+	}
+	for ecosystem := range addedByEcosystem {
+		summary, ok := adjusted[ecosystem]
+		if !ok {
+			summary.Fixable = &starboardv1alpha1.FixableSummary{}
+		}
+		summary = adjustSummary(summary, addedByEcosystem[ecosystem], removedByEcosystem[ecosystem], weights)
+		if summary.Total() <= 0 {
+			delete(adjusted, ecosystem)
+			continue
+		}
+		adjusted[ecosystem] = summary
+	}
+	if len(adjusted) == 0 {
+		return nil
+	}
+	return adjusted
+}
+
+// dockerHubRegistries lists hosts under which the Docker Hub registry is known.
+var dockerHubRegistries = map[string]bool{
+	"docker.io":               true,
+	"index.docker.io":         true,
+	"registry-1.docker.io":    true,
+	"registry.hub.docker.com": true,
+}
+
+// localhostRegistry is the host of a registry on the local machine, which
+// Docker tells apart from a Docker Hub namespace even without a port.
+const localhostRegistry = "localhost"
+
+// hasRegistry checks whether the specified image reference begins with the
+// registry, i.e. its first component contains a dot or a colon, or it's
+// localhost. Except for localhost, that's how name.ParseReference tells a
+// registry apart from a Docker Hub namespace.
+func (c *converter) hasRegistry(imageRef string) bool {
+	index := strings.IndexRune(imageRef, '/')
+	if index < 0 {
+		return false
+	}
+	return imageRef[:index] == localhostRegistry || strings.ContainsAny(imageRef[:index], ".:")
+}
+
+// isLocalhostWithoutPort checks whether the registry of the specified image
+// reference is localhost without a port, e.g. localhost/app:dev, which
+// name.ParseReference would take for the localhost/app Docker Hub repository.
+func (c *converter) isLocalhostWithoutPort(imageRef string) bool {
+	return strings.HasPrefix(imageRef, localhostRegistry+"/")
+}
+
+// lowercaseRepository returns the specified image reference with the path of
+// the repository in lowercase. Repository paths must be lowercase, but users
+// sometimes use mixed case, e.g. MyRegistry.io/MyApp:1.0. The registry host,
+// the tag and the digest are left unchanged, because the host is validated by
+// name.ParseReference as is, and tags are case-sensitive.
+func (c *converter) lowercaseRepository(imageRef string) string {
+	end := len(imageRef)
+	if index := strings.IndexRune(imageRef, '@'); index >= 0 {
+		end = index
+	}
+	if index := strings.LastIndex(imageRef[:end], ":"); index > strings.LastIndex(imageRef[:end], "/") {
+		end = index
+	}
+	start := 0
+	if c.hasRegistry(imageRef) {
+		start = strings.IndexRune(imageRef, '/') + 1
+	}
+	if start > end {
+		return imageRef
+	}
+	return imageRef[:start] + strings.ToLower(imageRef[start:end]) + imageRef[end:]
+}
+
+// parseImageRef parses the specified image reference into the registry and the
+// artifact. The path of the repository is lowercased first, see
+// lowercaseRepository.
+//
+// Registries on the local machine are recognized with or without a port, e.g.
+// localhost/app:dev, localhost:5000/app:dev, or 127.0.0.1:5000/app:dev.
+//
+// Parsing is purely syntactic and never reaches the registry. By default the
+// reference is parsed with name.WeakValidation, which defaults the registry to
+// Docker Hub, the repository path to library and the tag to latest. If
+// Config.GetStrictImageRefValidation is true, name.StrictValidation is used
+// instead, which rejects references that rely on any of those defaults.
+func (c *converter) parseImageRef(config Config, imageRef string) (starboardv1alpha1.Registry, starboardv1alpha1.Artifact, error) {
+	imageRef = c.lowercaseRepository(imageRef)
+	if index := strings.LastIndex(imageRef, "@"); index >= 0 {
+		if err := c.validateDigest(imageRef[index+1:]); err != nil {
+			return starboardv1alpha1.Registry{}, starboardv1alpha1.Artifact{}, err
+		}
+	}
+	validation := name.WeakValidation
+	if config.GetStrictImageRefValidation() {
+		validation = name.StrictValidation
+	}
+	parsedRef := imageRef
+	if c.isLocalhostWithoutPort(imageRef) {
+		// Make the registry explicit with the default port of plain HTTP,
+		// which is then dropped from the server.
+		parsedRef = localhostRegistry + ":80" + imageRef[len(localhostRegistry):]
+	}
+	ref, err := name.ParseReference(parsedRef, validation)
+	if err != nil && parsedRef != imageRef {
+		err = name.NewErrBadName("could not parse reference: %s", imageRef)
+	}
 	if err != nil {
 		return starboardv1alpha1.Registry{}, starboardv1alpha1.Artifact{}, err
 	}
 	registry := starboardv1alpha1.Registry{
-		Server: ref.Context().RegistryStr(),
+		Server:            ref.Context().RegistryStr(),
+		IsDefaultRegistry: !c.hasRegistry(imageRef),
+	}
+	if parsedRef != imageRef {
+		registry.Server = localhostRegistry
+	}
+	if dockerHubRegistries[registry.Server] {
+		registry.Server = config.GetDockerHubRegistry()
 	}
 	artifact := starboardv1alpha1.Artifact{
 		Repository: ref.Context().RepositoryStr(),
@@ -144,7 +2326,47 @@ func (c *converter) parseImageRef(imageRef string) (starboardv1alpha1.Registry,
 		artifact.Tag = t.TagStr()
 	case name.Digest:
 		artifact.Digest = t.DigestStr()
+		artifact.Tag = c.tagOfDigestRef(imageRef)
 	}
 
 	return registry, artifact, nil
 }
+
+// validateDigest checks whether the specified digest consists of the sha256
+// algorithm and 64 lowercase hex digits, e.g. sha256:d20aa6d1...dd9c.
+func (c *converter) validateDigest(digest string) error {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("malformed digest %q: expected algorithm:hex", digest)
+	}
+	algorithm, hex := parts[0], parts[1]
+	if algorithm != "sha256" {
+		return fmt.Errorf("unsupported digest algorithm %q in digest %q", algorithm, digest)
+	}
+	if len(hex) != 64 {
+		return fmt.Errorf("malformed sha256 digest %q: expected 64 hex digits but got %d", digest, len(hex))
+	}
+	for _, r := range hex {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f') {
+			return fmt.Errorf("malformed sha256 digest %q: unexpected character %q", digest, r)
+		}
+	}
+	return nil
+}
+
+// tagOfDigestRef returns the tag of an image reference that is pinned by
+// digest, e.g. registry/repository:tag@sha256:digest, or an empty string if
+// the reference does not have a tag. The name.Digest type drops the tag, hence
+// the tag is extracted from the raw reference. A colon that precedes the last
+// slash separates a port number in the registry host rather than a tag.
+func (c *converter) tagOfDigestRef(imageRef string) string {
+	base := imageRef
+	if index := strings.Index(base, "@"); index >= 0 {
+		base = base[:index]
+	}
+	colon := strings.LastIndex(base, ":")
+	if colon < 0 || colon < strings.LastIndex(base, "/") {
+		return ""
+	}
+	return base[colon+1:]
+}
@@ -0,0 +1,73 @@
+package trivy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aquasecurity/starboard/pkg/starboard"
+)
+
+// BenchmarkSkippingNoisyOutputReader shows that memory allocated to skip the
+// noisy output does not depend on the size of the JSON report.
+func BenchmarkSkippingNoisyOutputReader(b *testing.B) {
+	preamble := "2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\n"
+	vulnerability := `{"VulnerabilityID":"CVE-2019-1549","PkgName":"openssl","InstalledVersion":"1.1.1c-r0","Severity":"MEDIUM"}`
+
+	for _, size := range []int{100, 10000, 100000} {
+		input := []byte(preamble + "[" + strings.TrimSuffix(strings.Repeat(vulnerability+",", size), ",") + "]")
+
+		b.Run(fmt.Sprintf("%d vulnerabilities", size), func(b *testing.B) {
+			c := NewConverter().(*converter)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				reader, err := c.skippingNoisyOutputReader(bytes.NewReader(input))
+				if err != nil {
+					b.Fatal(err)
+				}
+				_, err = io.Copy(ioutil.Discard, reader)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConvertBytes compares allocations of converting the output of Trivy
+// read from a stream with converting the same output held in memory.
+func BenchmarkConvertBytes(b *testing.B) {
+	preamble := "2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\n"
+	vulnerability := `{"VulnerabilityID":"CVE-2019-1549","PkgName":"openssl","InstalledVersion":"1.1.1c-r0","Severity":"MEDIUM"}`
+	vulnerabilities := strings.TrimSuffix(strings.Repeat(vulnerability+",", 1000), ",")
+	input := []byte(preamble + `[{"Target":"alpine:3.10.2 (alpine 3.10.2)","Vulnerabilities":[` + vulnerabilities + `]}]`)
+	config := starboard.ConfigData{}
+
+	b.Run("Convert", func(b *testing.B) {
+		c := NewConverter()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := c.Convert(config, "alpine:3.10.2", bytes.NewReader(input))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("ConvertBytes", func(b *testing.B) {
+		c := NewConverter()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := c.ConvertBytes(config, "alpine:3.10.2", input)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
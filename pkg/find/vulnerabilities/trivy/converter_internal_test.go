@@ -0,0 +1,76 @@
+package trivy
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackingReadCloser is an io.ReadCloser that records whether it was closed.
+type trackingReadCloser struct {
+	io.Reader
+	closed bool
+}
+
+func (r *trackingReadCloser) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestConverter_ConvertFile_Close(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError bool
+	}{
+		{
+			name:    "Should close file when report is valid",
+			content: `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": []}]`,
+		},
+		{
+			name:          "Should close file when report is invalid",
+			content:       `[{"Target": 5}]`,
+			expectedError: true,
+		},
+		{
+			name:          "Should close file when scan failed",
+			content:       "2020-06-17T23:37:46.102+0200	FATAL	failed to download vulnerability DB\n[]",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := &trackingReadCloser{Reader: strings.NewReader(tc.content)}
+			c := NewConverter().(*converter)
+			c.openFile = func(path string) (io.ReadCloser, error) {
+				return file, nil
+			}
+			_, err := c.ConvertFile(config, "alpine:3.10.2", "report.json")
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.True(t, file.closed)
+		})
+	}
+
+	t.Run("Should not close file that cannot be opened", func(t *testing.T) {
+		c := NewConverter().(*converter)
+		c.openFile = func(path string) (io.ReadCloser, error) {
+			return nil, os.ErrNotExist
+		}
+		_, err := c.ConvertFile(config, "alpine:3.10.2", "report.json")
+		assert.EqualError(t, err, "opening report file report.json: file does not exist")
+	})
+}
@@ -1,18 +1,40 @@
 package trivy_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/aquasecurity/starboard/pkg/ext"
+	"github.com/aquasecurity/starboard/pkg/kube"
 	"github.com/aquasecurity/starboard/pkg/starboard"
 
 	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
 
 	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var (
+	fixedTime  = time.Date(2020, 10, 14, 9, 30, 0, 0, time.UTC)
+	fixedClock = ext.NewFixedClock(fixedTime)
 )
 
 var (
@@ -54,18 +76,25 @@ var (
 			Version: "0.9.1",
 		},
 		Registry: starboardv1alpha1.Registry{
-			Server: "index.docker.io",
+			Server:            "index.docker.io",
+			IsDefaultRegistry: true,
 		},
 		Artifact: starboardv1alpha1.Artifact{
 			Repository: "library/alpine",
 			Tag:        "3.10.2",
 		},
+		ImageType: starboardv1alpha1.ImageTypeFull,
 		Summary: starboardv1alpha1.VulnerabilitySummary{
 			CriticalCount: 0,
 			MediumCount:   1,
 			LowCount:      1,
 			NoneCount:     0,
 			UnknownCount:  0,
+			RiskScore:     3,
+			Fixable: &starboardv1alpha1.FixableSummary{
+				MediumCount: 1,
+				LowCount:    1,
+			},
 		},
 		Vulnerabilities: []starboardv1alpha1.Vulnerability{
 			{
@@ -73,22 +102,53 @@ var (
 				Resource:         "openssl",
 				InstalledVersion: "1.1.1c-r0",
 				FixedVersion:     "1.1.1d-r0",
+				FixedVersions:    []string{"1.1.1d-r0"},
+				Status:           starboardv1alpha1.VulnerabilityStatusAffected,
 				Severity:         starboardv1alpha1.SeverityMedium,
 				Title:            "openssl: information disclosure in fork()",
 				Links: []string{
 					"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
 				},
+				CategorizedLinks: &starboardv1alpha1.CategorizedLinks{
+					Advisory: []string{"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549"},
+				},
+				PrimaryURL: "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+				Target:     "alpine:3.10.2 (alpine 3.10.2)",
+				CweIDs:     []string{},
+				Ecosystem:  "alpine",
 			},
 			{
 				VulnerabilityID:  "CVE-2019-1547",
 				Resource:         "openssl",
 				InstalledVersion: "1.1.1c-r0",
 				FixedVersion:     "1.1.1d-r0",
+				FixedVersions:    []string{"1.1.1d-r0"},
+				Status:           starboardv1alpha1.VulnerabilityStatusAffected,
 				Severity:         starboardv1alpha1.SeverityLow,
 				Title:            "openssl: side-channel weak encryption vulnerability",
 				Links: []string{
 					"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1547",
 				},
+				CategorizedLinks: &starboardv1alpha1.CategorizedLinks{
+					Advisory: []string{"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1547"},
+				},
+				PrimaryURL: "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1547",
+				Target:     "alpine:3.10.2 (alpine 3.10.2)",
+				CweIDs:     []string{},
+				Ecosystem:  "alpine",
+			},
+		},
+		UpdateTimestamp: metav1.NewTime(fixedTime),
+		ScannedTargets:  []string{"alpine:3.10.2 (alpine 3.10.2)"},
+		EcosystemSummary: map[string]starboardv1alpha1.VulnerabilitySummary{
+			"alpine": {
+				MediumCount: 1,
+				LowCount:    1,
+				RiskScore:   3,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					MediumCount: 1,
+					LowCount:    1,
+				},
 			},
 		},
 	}
@@ -107,9 +167,9 @@ func TestConverter_Convert(t *testing.T) {
 		expectedReport starboardv1alpha1.VulnerabilityScanResult
 	}{
 		{
-			name:     "Should convert vulnerability report in JSON format when input is noisy",
-			imageRef: "alpine:3.10.2",
-			input: fmt.Sprintf("2020-06-17T23:37:45.320+0200	[34mINFO[0m	Detecting Alpine vulnerabilities...\n%s", sampleReportAsString),
+			name:           "Should convert vulnerability report in JSON format when input is noisy",
+			imageRef:       "alpine:3.10.2",
+			input:          fmt.Sprintf("2020-06-17T23:37:45.320+0200	[34mINFO[0m	Detecting Alpine vulnerabilities...\n%s", sampleReportAsString),
 			expectedError:  nil,
 			expectedReport: sampleReport,
 		},
@@ -139,6 +199,7 @@ null`,
 					Repository: "library/nginx",
 					Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
 				},
+				ImageType: starboardv1alpha1.ImageTypeScratch,
 				Summary: starboardv1alpha1.VulnerabilitySummary{
 					CriticalCount: 0,
 					HighCount:     0,
@@ -146,21 +207,17 @@ null`,
 					LowCount:      0,
 					NoneCount:     0,
 					UnknownCount:  0,
+					Fixable:       &starboardv1alpha1.FixableSummary{},
 				},
 				Vulnerabilities: []starboardv1alpha1.Vulnerability{},
+				UpdateTimestamp: metav1.NewTime(fixedTime),
 			},
 		},
-		{
-			name:          "Should return error when image reference cannot be parsed",
-			imageRef:      ":",
-			input:         "null",
-			expectedError: errors.New("could not parse reference: :"),
-		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			report, err := trivy.NewConverter().Convert(config, tc.imageRef, strings.NewReader(tc.input))
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(config, tc.imageRef, strings.NewReader(tc.input))
 			switch {
 			case tc.expectedError == nil:
 				require.NoError(t, err)
@@ -172,3 +229,4599 @@ null`,
 	}
 
 }
+
+func TestConverter_Convert_CVSS(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name           string
+		cvss           string
+		expectedScore  *float64
+		expectedVector string
+	}{
+		{
+			name: "Should prefer NVD CVSS v3 data",
+			cvss: `{
+				"redhat": {"V3Vector": "CVSS:3.0/AV:N/AC:H/PR:N/UI:N/S:U/C:L/I:N/A:N", "V3Score": 3.7},
+				"nvd": {"V2Vector": "AV:N/AC:M/Au:N/C:P/I:N/A:N", "V3Vector": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", "V2Score": 4.3, "V3Score": 5.9}
+			}`,
+			expectedScore:  pointer.Float64Ptr(5.9),
+			expectedVector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N",
+		},
+		{
+			name: "Should fall back to CVSS v2 data when v3 is absent",
+			cvss: `{
+				"nvd": {"V2Vector": "AV:N/AC:M/Au:N/C:P/I:N/A:N", "V2Score": 4.3}
+			}`,
+			expectedScore:  pointer.Float64Ptr(4.3),
+			expectedVector: "AV:N/AC:M/Au:N/C:P/I:N/A:N",
+		},
+		{
+			name: "Should prefer CVSS v3 data of other vendor to NVD CVSS v2 data",
+			cvss: `{
+				"nvd": {"V2Vector": "AV:N/AC:M/Au:N/C:P/I:N/A:N", "V2Score": 4.3},
+				"redhat": {"V3Vector": "CVSS:3.0/AV:N/AC:H/PR:N/UI:N/S:U/C:L/I:N/A:N", "V3Score": 3.7}
+			}`,
+			expectedScore:  pointer.Float64Ptr(3.7),
+			expectedVector: "CVSS:3.0/AV:N/AC:H/PR:N/UI:N/S:U/C:L/I:N/A:N",
+		},
+		{
+			name: "Should fall back to CVSS v2 data of first vendor in lexical order when v3 is absent",
+			cvss: `{
+				"redhat": {"V2Vector": "AV:N/AC:L/Au:N/C:P/I:N/A:N", "V2Score": 5.0},
+				"ghsa": {"V2Vector": "AV:N/AC:M/Au:N/C:P/I:N/A:N", "V2Score": 4.3}
+			}`,
+			expectedScore:  pointer.Float64Ptr(4.3),
+			expectedVector: "AV:N/AC:M/Au:N/C:P/I:N/A:N",
+		},
+		{
+			name: "Should use first vendor in lexical order when NVD is absent",
+			cvss: `{
+				"redhat": {"V3Vector": "CVSS:3.0/AV:N/AC:H/PR:N/UI:N/S:U/C:L/I:N/A:N", "V3Score": 3.7},
+				"ghsa": {"V3Vector": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "V3Score": 9.8}
+			}`,
+			expectedScore:  pointer.Float64Ptr(9.8),
+			expectedVector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		},
+		{
+			name:           "Should leave score nil when CVSS data is absent",
+			cvss:           `{}`,
+			expectedScore:  nil,
+			expectedVector: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := fmt.Sprintf(`[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Severity": "MEDIUM",
+			"CVSS": %s
+		}
+	]
+	}
+]`, tc.cvss)
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.Equal(t, tc.expectedScore, report.Vulnerabilities[0].Score)
+			assert.Equal(t, tc.expectedVector, report.Vulnerabilities[0].CVSSVector)
+		})
+	}
+}
+
+func TestConverter_Convert_SeverityThreshold(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		threshold               string
+		expectedError           error
+		expectedVulnerabilities []string
+		expectedSummary         starboardv1alpha1.VulnerabilitySummary
+	}{
+		{
+			name:                    "Should include all vulnerabilities when threshold is not set",
+			threshold:               "",
+			expectedVulnerabilities: []string{"CVE-2019-1549", "CVE-2019-1547"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				LowCount:    1,
+				RiskScore:   3,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					MediumCount: 1,
+					LowCount:    1,
+				},
+			},
+		},
+		{
+			name:                    "Should skip vulnerabilities below threshold",
+			threshold:               "MEDIUM",
+			expectedVulnerabilities: []string{"CVE-2019-1549"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				RiskScore:   2,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					MediumCount: 1,
+				},
+			},
+		},
+		{
+			name:                    "Should parse threshold regardless of case",
+			threshold:               "medium",
+			expectedVulnerabilities: []string{"CVE-2019-1549"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				RiskScore:   2,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					MediumCount: 1,
+				},
+			},
+		},
+		{
+			name:                    "Should skip all vulnerabilities below threshold",
+			threshold:               "CRITICAL",
+			expectedVulnerabilities: []string{},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				Fixable: &starboardv1alpha1.FixableSummary{},
+			},
+		},
+		{
+			name:          "Should return error when threshold is not recognized",
+			threshold:     "SEVERE",
+			expectedError: errors.New("unrecognized severity threshold: SEVERE"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef":          "aquasec/trivy:0.9.1",
+				"trivy.severityThreshold": tc.threshold,
+			}
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]string, 0)
+			for _, v := range report.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedVulnerabilities, ids)
+			assert.Equal(t, tc.expectedSummary, report.Summary)
+		})
+	}
+}
+
+func TestConverter_Convert_Deduplication(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Title": "openssl: information disclosure in fork()",
+			"Severity": "MEDIUM"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1547",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Severity": "LOW"
+		}
+	]
+	},
+	{
+		"Target": "usr/lib/app",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Title": "duplicate",
+			"Severity": "MEDIUM"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1b-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Severity": "MEDIUM"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1563",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Severity": "LOW"
+		}
+	]
+	}
+]`
+
+	report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+	require.NoError(t, err)
+
+	type entry struct {
+		id, version, title string
+	}
+	var entries []entry
+	for _, v := range report.Vulnerabilities {
+		entries = append(entries, entry{v.VulnerabilityID, v.InstalledVersion, v.Title})
+	}
+	assert.Equal(t, []entry{
+		{"CVE-2019-1549", "1.1.1b-r0", ""},
+		{"CVE-2019-1549", "1.1.1c-r0", "openssl: information disclosure in fork()"},
+		{"CVE-2019-1547", "1.1.1c-r0", ""},
+		{"CVE-2019-1563", "1.1.1c-r0", ""},
+	}, entries)
+	assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+		MediumCount: 2,
+		LowCount:    2,
+		RiskScore:   6,
+		Fixable: &starboardv1alpha1.FixableSummary{
+			MediumCount: 2,
+			LowCount:    2,
+		},
+	}, report.Summary)
+}
+
+func TestConverter_Convert_TargetAndLayer(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "nginx:1.16 (debian 10.4)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-18276",
+			"PkgName": "bash",
+			"InstalledVersion": "5.0-4",
+			"Severity": "LOW",
+			"Layer": {
+				"Digest": "sha256:bf5952930446728ddb3fc7fb2c4d8d0a4c8c8be06e1cfd6f2fc8acbdcd0344c0",
+				"DiffID": "sha256:d0f104dc0a1f9c744b65b23b3fd4d4d3236b4656e67f776fe13f8ad8423b955c"
+			}
+		}
+	]
+	},
+	{
+		"Target": "usr/local/bin/app",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2020-14040",
+			"PkgName": "golang.org/x/text",
+			"InstalledVersion": "v0.3.2",
+			"Severity": "HIGH"
+		}
+	]
+	}
+]`
+
+	report, err := trivy.NewConverter().Convert(config, "nginx:1.16", strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, report.Vulnerabilities, 2)
+
+	assert.Equal(t, "usr/local/bin/app", report.Vulnerabilities[0].Target)
+	assert.Nil(t, report.Vulnerabilities[0].Layer)
+
+	assert.Equal(t, "nginx:1.16 (debian 10.4)", report.Vulnerabilities[1].Target)
+	assert.Equal(t, &starboardv1alpha1.Layer{
+		Digest: "sha256:bf5952930446728ddb3fc7fb2c4d8d0a4c8c8be06e1cfd6f2fc8acbdcd0344c0",
+		DiffID: "sha256:d0f104dc0a1f9c744b65b23b3fd4d4d3236b4656e67f776fe13f8ad8423b955c",
+	}, report.Vulnerabilities[1].Layer)
+}
+
+func TestConverter_Convert_ImageRef(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name             string
+		imageRef         string
+		expectedRegistry starboardv1alpha1.Registry
+		expectedArtifact starboardv1alpha1.Artifact
+	}{
+		{
+			name:     "Should parse tag reference",
+			imageRef: "core.harbor.domain/library/nginx:1.16",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "core.harbor.domain",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+			},
+		},
+		{
+			name:     "Should parse digest reference",
+			imageRef: "core.harbor.domain/library/nginx@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "core.harbor.domain",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse reference with tag and digest",
+			imageRef: "core.harbor.domain/library/nginx:1.16@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "core.harbor.domain",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse digest reference with port in registry host",
+			imageRef: "core.harbor.domain:8443/library/nginx@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "core.harbor.domain:8443",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse reference with tag and digest and port in registry host",
+			imageRef: "core.harbor.domain:8443/library/nginx:1.16@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "core.harbor.domain:8443",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should flag default registry of short name",
+			imageRef: "nginx:1.16",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server:            "index.docker.io",
+				IsDefaultRegistry: true,
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+			},
+		},
+		{
+			name:     "Should flag default registry of short name with namespace",
+			imageRef: "aquasec/trivy:0.9.1",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server:            "index.docker.io",
+				IsDefaultRegistry: true,
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "aquasec/trivy",
+				Tag:        "0.9.1",
+			},
+		},
+		{
+			name:     "Should not flag explicit Docker Hub registry",
+			imageRef: "docker.io/library/nginx:1.16",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "index.docker.io",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+			},
+		},
+		{
+			name:     "Should not flag localhost registry with port",
+			imageRef: "localhost:5000/nginx:1.16",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "nginx",
+				Tag:        "1.16",
+			},
+		},
+		{
+			name:     "Should lowercase mixed-case repository path and keep registry host",
+			imageRef: "MyRegistry.io/MyTeam/MyApp:Release-1.0",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "MyRegistry.io",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "myteam/myapp",
+				Tag:        "Release-1.0",
+			},
+		},
+		{
+			name:     "Should lowercase mixed-case repository path of digest reference with port in registry host",
+			imageRef: "MyRegistry.io:8443/MyApp@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "MyRegistry.io:8443",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "myapp",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse MCR reference of Windows image",
+			imageRef: "mcr.microsoft.com/windows/nanoserver:ltsc2022",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "mcr.microsoft.com",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "windows/nanoserver",
+				Tag:        "ltsc2022",
+			},
+		},
+		{
+			name:     "Should parse MCR reference with multi-segment repository and dotted tag",
+			imageRef: "mcr.microsoft.com/dotnet/framework/aspnet:4.8-windowsservercore-ltsc2019",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "mcr.microsoft.com",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "dotnet/framework/aspnet",
+				Tag:        "4.8-windowsservercore-ltsc2019",
+			},
+		},
+		{
+			name:     "Should parse MCR reference with build number tag and digest",
+			imageRef: "mcr.microsoft.com/windows/servercore:10.0.17763.2565-amd64@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "mcr.microsoft.com",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "windows/servercore",
+				Tag:        "10.0.17763.2565-amd64",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should lowercase mixed-case short name",
+			imageRef: "Library/Nginx:1.16",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server:            "index.docker.io",
+				IsDefaultRegistry: true,
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter().Convert(config, tc.imageRef, strings.NewReader("null"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRegistry, report.Registry)
+			assert.Equal(t, tc.expectedArtifact, report.Artifact)
+		})
+	}
+}
+
+func TestConverter_Convert_LocalhostImageRef(t *testing.T) {
+	testCases := []struct {
+		name             string
+		imageRef         string
+		expectedRegistry starboardv1alpha1.Registry
+		expectedArtifact starboardv1alpha1.Artifact
+	}{
+		{
+			name:     "Should parse tag reference to localhost with port",
+			imageRef: "localhost:5000/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+			},
+		},
+		{
+			name:     "Should parse digest reference to localhost with port",
+			imageRef: "localhost:5000/app@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse reference with tag and digest to localhost with port",
+			imageRef: "localhost:5000/app:dev@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse reference without tag to localhost with port",
+			imageRef: "localhost:5000/app",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "latest",
+			},
+		},
+		{
+			name:     "Should parse nested repository on localhost with port",
+			imageRef: "localhost:5000/team/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "team/app",
+				Tag:        "dev",
+			},
+		},
+		{
+			name:     "Should parse numeric tag on localhost with port",
+			imageRef: "localhost:5000/app:5000",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "5000",
+			},
+		},
+		{
+			name:     "Should lowercase repository on localhost with port",
+			imageRef: "localhost:5000/Team/App:Dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "team/app",
+				Tag:        "Dev",
+			},
+		},
+		{
+			name:     "Should parse tag reference to localhost without port",
+			imageRef: "localhost/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+			},
+		},
+		{
+			name:     "Should parse digest reference to localhost without port",
+			imageRef: "localhost/app@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse nested repository on localhost without port",
+			imageRef: "localhost/team/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "localhost",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "team/app",
+				Tag:        "dev",
+			},
+		},
+		{
+			name:     "Should parse tag reference to loopback address with port",
+			imageRef: "127.0.0.1:5000/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "127.0.0.1:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+			},
+		},
+		{
+			name:     "Should parse digest reference to loopback address with port",
+			imageRef: "127.0.0.1:5000/app@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "127.0.0.1:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse reference with tag and digest to loopback address with port",
+			imageRef: "127.0.0.1:5000/app:dev@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "127.0.0.1:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should parse tag reference to loopback address without port",
+			imageRef: "127.0.0.1/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "127.0.0.1",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+			},
+		},
+		{
+			name:     "Should parse tag reference to IPv6 loopback address with port",
+			imageRef: "[::1]:5000/app:dev",
+			expectedRegistry: starboardv1alpha1.Registry{
+				Server: "[::1]:5000",
+			},
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "app",
+				Tag:        "dev",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, strict := range []string{"false", "true"} {
+			t.Run(tc.name+" with strict validation "+strict, func(t *testing.T) {
+				if strict == "true" && tc.expectedArtifact.Tag == "latest" {
+					t.Skip("strict validation requires an explicit tag")
+				}
+				report, err := trivy.NewConverter().Convert(starboard.ConfigData{
+					"trivy.imageRef":                 "aquasec/trivy:0.9.1",
+					"trivy.strictImageRefValidation": strict,
+				}, tc.imageRef, strings.NewReader("null"))
+				require.NoError(t, err)
+				assert.Empty(t, report.Warnings)
+				assert.Equal(t, tc.expectedRegistry, report.Registry)
+				assert.Equal(t, tc.expectedArtifact, report.Artifact)
+			})
+		}
+	}
+
+	t.Run("Should not leak rewritten localhost reference into warnings", func(t *testing.T) {
+		report, err := trivy.NewConverter().Convert(starboard.ConfigData{
+			"trivy.imageRef":                 "aquasec/trivy:0.9.1",
+			"trivy.strictImageRefValidation": "true",
+		}, "localhost/app", strings.NewReader("null"))
+		require.NoError(t, err)
+		require.Len(t, report.Warnings, 1)
+		assert.Equal(t, `parsing image reference "localhost/app": could not parse reference: localhost/app`, report.Warnings[0])
+	})
+}
+
+func TestConverter_Convert_StrictImageRefValidation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		imageRef         string
+		expectedArtifact starboardv1alpha1.Artifact
+		expectedWarning  string
+	}{
+		{
+			name:             "Should accept fully specified reference",
+			imageRef:         "docker.io/library/nginx:1.16",
+			expectedArtifact: starboardv1alpha1.Artifact{Repository: "library/nginx", Tag: "1.16"},
+		},
+		{
+			name:             "Should accept fully specified digest reference",
+			imageRef:         "core.harbor.domain/library/nginx@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedArtifact: starboardv1alpha1.Artifact{Repository: "library/nginx", Digest: "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c"},
+		},
+		{
+			name:             "Should reject reference without registry",
+			imageRef:         "nginx:1.16",
+			expectedArtifact: starboardv1alpha1.Artifact{Repository: "nginx:1.16"},
+			expectedWarning:  `parsing image reference "nginx:1.16": `,
+		},
+		{
+			name:             "Should reject reference without library namespace",
+			imageRef:         "docker.io/nginx:1.16",
+			expectedArtifact: starboardv1alpha1.Artifact{Repository: "docker.io/nginx:1.16"},
+			expectedWarning:  `parsing image reference "docker.io/nginx:1.16": `,
+		},
+		{
+			name:             "Should reject reference without tag",
+			imageRef:         "core.harbor.domain/library/nginx",
+			expectedArtifact: starboardv1alpha1.Artifact{Repository: "core.harbor.domain/library/nginx"},
+			expectedWarning:  `parsing image reference "core.harbor.domain/library/nginx": `,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			weak, err := trivy.NewConverter().Convert(starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			}, tc.imageRef, strings.NewReader("null"))
+			require.NoError(t, err)
+			assert.Empty(t, weak.Warnings)
+			assert.NotEmpty(t, weak.Registry.Server)
+
+			strict, err := trivy.NewConverter().Convert(starboard.ConfigData{
+				"trivy.imageRef":                 "aquasec/trivy:0.9.1",
+				"trivy.strictImageRefValidation": "true",
+			}, tc.imageRef, strings.NewReader("null"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArtifact, strict.Artifact)
+			if tc.expectedWarning == "" {
+				assert.Empty(t, strict.Warnings)
+				assert.Equal(t, weak.Artifact, strict.Artifact)
+				return
+			}
+			require.Len(t, strict.Warnings, 1)
+			assert.Contains(t, strict.Warnings[0], tc.expectedWarning)
+			assert.Equal(t, starboardv1alpha1.Registry{}, strict.Registry)
+		})
+	}
+}
+
+func TestConverter_Convert_DockerHubRegistry(t *testing.T) {
+	testCases := []struct {
+		name             string
+		configData       starboard.ConfigData
+		imageRef         string
+		expectedRegistry string
+	}{
+		{
+			name:             "Should default registry of short image name",
+			configData:       starboard.ConfigData{},
+			imageRef:         "nginx:1.19",
+			expectedRegistry: "index.docker.io",
+		},
+		{
+			name:             "Should default registry of image name with namespace",
+			configData:       starboard.ConfigData{},
+			imageRef:         "library/nginx:1.19",
+			expectedRegistry: "index.docker.io",
+		},
+		{
+			name:             "Should normalize Docker Hub registry alias",
+			configData:       starboard.ConfigData{},
+			imageRef:         "registry-1.docker.io/library/nginx:1.19",
+			expectedRegistry: "index.docker.io",
+		},
+		{
+			name: "Should use canonical Docker Hub registry from config",
+			configData: starboard.ConfigData{
+				"trivy.dockerHubRegistry": "docker.io",
+			},
+			imageRef:         "nginx:1.19",
+			expectedRegistry: "docker.io",
+		},
+		{
+			name: "Should not normalize registry other than Docker Hub",
+			configData: starboard.ConfigData{
+				"trivy.dockerHubRegistry": "docker.io",
+			},
+			imageRef:         "gcr.io/google-containers/pause:3.2",
+			expectedRegistry: "gcr.io",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.configData["trivy.imageRef"] = "aquasec/trivy:0.9.1"
+			report, err := trivy.NewConverter().Convert(tc.configData, tc.imageRef, strings.NewReader("null"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRegistry, report.Registry.Server)
+		})
+	}
+}
+
+func TestConverter_Convert_PrimaryURL(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name               string
+		primaryURL         string
+		references         []string
+		expectedPrimaryURL string
+	}{
+		{
+			name:       "Should prefer primary URL reported by Trivy",
+			primaryURL: "https://avd.aquasec.com/nvd/cve-2019-1549",
+			references: []string{
+				"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+			},
+			expectedPrimaryURL: "https://avd.aquasec.com/nvd/cve-2019-1549",
+		},
+		{
+			name: "Should fall back to first NVD reference",
+			references: []string{
+				"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+			},
+			expectedPrimaryURL: "https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+		},
+		{
+			name: "Should fall back to first reference",
+			references: []string{
+				"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+				"https://www.openssl.org/news/secadv/20190910.txt",
+			},
+			expectedPrimaryURL: "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+		},
+		{
+			name:               "Should return empty primary URL without references",
+			references:         []string{},
+			expectedPrimaryURL: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := json.Marshal([]trivy.ScanReport{
+				{
+					Target: "alpine:3.10.2 (alpine 3.10.2)",
+					Vulnerabilities: []trivy.Vulnerability{
+						{
+							VulnerabilityID:  "CVE-2019-1549",
+							PkgName:          "openssl",
+							InstalledVersion: "1.1.1c-r0",
+							Severity:         starboardv1alpha1.SeverityMedium,
+							PrimaryURL:       tc.primaryURL,
+							References:       tc.references,
+							CVSS:             map[string]trivy.CVSS{},
+						},
+					},
+				},
+			})
+			require.NoError(t, err)
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", bytes.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.Equal(t, tc.expectedPrimaryURL, report.Vulnerabilities[0].PrimaryURL)
+			assert.Equal(t, len(tc.references), len(report.Vulnerabilities[0].Links))
+		})
+	}
+}
+
+func TestConverter_Convert_NoisyOutput(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	report := `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Title": "openssl: information disclosure in fork()",
+			"Description": "Example:\n[\n  \"fork\"\n]\nnull",
+			"Severity": "MEDIUM",
+			"References": null
+		}
+	]
+	}
+]`
+
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Should convert quiet output with null values",
+			input: report,
+		},
+		{
+			name:  "Should convert noisy output with square brackets in preamble",
+			input: "[1/2] Downloading DB...\n[2/2] Detecting Alpine vulnerabilities...\n" + report,
+		},
+		{
+			name:  "Should convert colored output with escape sequences before the JSON output",
+			input: "\x1b[34mINFO\x1b[0m\tDetecting Alpine vulnerabilities...\n\x1b[0m\x1b[1;37m" + report,
+		},
+		{
+			name:  "Should convert output with nested array at the beginning of a line",
+			input: strings.Replace(report, `"References": null`, "\"References\":\n[\n\"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549\"\n]", 1),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+			require.NoError(t, err)
+			require.Len(t, result.Vulnerabilities, 1)
+			assert.Equal(t, "Example:\n[\n  \"fork\"\n]\nnull", result.Vulnerabilities[0].Description)
+		})
+	}
+}
+
+// countingReader counts bytes read from the underlying io.Reader.
+type countingReader struct {
+	reader io.Reader
+	count  int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += n
+	return n, err
+}
+
+func TestConverter_ConvertWithContext(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	t.Run("Should convert report when context is not done", func(t *testing.T) {
+		report, err := trivy.NewConverter().ConvertWithContext(context.Background(), config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Len(t, report.Vulnerabilities, 2)
+	})
+
+	t.Run("Should return early when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		reader := &countingReader{reader: strings.NewReader(sampleReportAsString)}
+		_, err := trivy.NewConverter().ConvertWithContext(ctx, config, "alpine:3.10.2", reader)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Less(t, reader.count, len(sampleReportAsString))
+	})
+}
+
+// testLogger is a logr.Logger that records messages logged at levels up to
+// the verbosity of the logger.
+type testLogger struct {
+	messages  *[]string
+	level     int
+	verbosity int
+}
+
+func newTestLogger() testLogger {
+	return testLogger{messages: &[]string{}}
+}
+
+func newVerboseTestLogger(verbosity int) testLogger {
+	return testLogger{messages: &[]string{}, verbosity: verbosity}
+}
+
+func (l testLogger) Info(msg string, keysAndValues ...interface{}) {
+	if !l.Enabled() {
+		return
+	}
+	*l.messages = append(*l.messages, fmt.Sprint(append([]interface{}{msg}, keysAndValues...)...))
+}
+
+func (l testLogger) Enabled() bool {
+	return l.level <= l.verbosity
+}
+
+func (l testLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.Info(msg, append(keysAndValues, "error", err)...)
+}
+
+func (l testLogger) V(level int) logr.InfoLogger {
+	l.level = level
+	return l
+}
+
+func (l testLogger) WithName(_ string) logr.Logger {
+	return l
+}
+
+func (l testLogger) WithValues(_ ...interface{}) logr.Logger {
+	return l
+}
+
+func TestConverter_Convert_UnrecognizedSeverity(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "UNKNOWN"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1547",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "SEVERE"
+		}
+	]
+	}
+]`
+
+	t.Run("Should map unrecognized severity to unknown and log warning", func(t *testing.T) {
+		logger := newTestLogger()
+		report, err := trivy.NewConverter(trivy.WithLogger(logger)).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 2)
+		assert.Equal(t, starboardv1alpha1.SeverityUnknown, report.Vulnerabilities[0].Severity)
+		assert.Equal(t, starboardv1alpha1.SeverityUnknown, report.Vulnerabilities[1].Severity)
+		assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+			UnknownCount: 2,
+			RiskScore:    2,
+			Fixable:      &starboardv1alpha1.FixableSummary{},
+		}, report.Summary)
+		assert.Equal(t, []string{
+			fmt.Sprint("Mapping unrecognized severity to UNKNOWN", "vulnerabilityID", "CVE-2019-1547", "severity", starboardv1alpha1.Severity("SEVERE")),
+		}, *logger.messages)
+	})
+
+	t.Run("Should return error for unrecognized severity in strict mode", func(t *testing.T) {
+		_, err := trivy.NewConverter(trivy.WithStrictSeverity()).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		assert.EqualError(t, err, `unrecognized severity of vulnerability CVE-2019-1547: "SEVERE"`)
+	})
+}
+
+func TestConverter_Convert_SeverityCasing(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2020-0001", "PkgName": "musl", "Severity": "Critical"},
+		{"VulnerabilityID": "CVE-2020-0002", "PkgName": "musl", "Severity": "high"},
+		{"VulnerabilityID": "CVE-2020-0003", "PkgName": "musl", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2020-0004", "PkgName": "musl", "Severity": "Low"},
+		{"VulnerabilityID": "CVE-2020-0005", "PkgName": "musl", "Severity": "unknown"},
+		{"VulnerabilityID": "CVE-2020-0006", "PkgName": "musl", "Severity": ""}
+	]
+	}
+]`
+
+	report, err := trivy.NewConverter(trivy.WithStrictSeverity()).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+	require.NoError(t, err)
+
+	var severities []starboardv1alpha1.Severity
+	for _, v := range report.Vulnerabilities {
+		severities = append(severities, v.Severity)
+	}
+	assert.Equal(t, []starboardv1alpha1.Severity{
+		starboardv1alpha1.SeverityCritical,
+		starboardv1alpha1.SeverityHigh,
+		starboardv1alpha1.SeverityMedium,
+		starboardv1alpha1.SeverityLow,
+		starboardv1alpha1.SeverityUnknown,
+		starboardv1alpha1.SeverityUnknown,
+	}, severities)
+	assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+		CriticalCount: 1,
+		HighCount:     1,
+		MediumCount:   1,
+		LowCount:      1,
+		UnknownCount:  2,
+		RiskScore:     20,
+		Fixable:       &starboardv1alpha1.FixableSummary{},
+	}, report.Summary)
+}
+
+func TestConverter_Convert_ScanTimes(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should default update timestamp to current time", func(t *testing.T) {
+		report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, metav1.NewTime(fixedTime), report.UpdateTimestamp)
+		assert.Equal(t, metav1.Duration{}, report.ScanDuration)
+	})
+
+	t.Run("Should set update timestamp and duration from scan times", func(t *testing.T) {
+		startTime := time.Date(2020, 10, 14, 8, 0, 0, 0, time.UTC)
+		completionTime := time.Date(2020, 10, 14, 8, 1, 30, 0, time.UTC)
+
+		report, err := converter.Convert(trivy.WithScanTimes(config, startTime, completionTime),
+			"alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, metav1.NewTime(completionTime), report.UpdateTimestamp)
+		assert.Equal(t, metav1.Duration{Duration: 90 * time.Second}, report.ScanDuration)
+	})
+
+	t.Run("Should read current time from clock at conversion", func(t *testing.T) {
+		clock := &settableClock{now: fixedTime}
+		converter := trivy.NewConverter(trivy.WithClock(clock))
+
+		first, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		clock.now = fixedTime.Add(time.Hour)
+		second, err := converter.ConvertBytes(config, "alpine:3.10.2", []byte(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, metav1.NewTime(fixedTime), first.UpdateTimestamp)
+		assert.Equal(t, metav1.NewTime(fixedTime.Add(time.Hour)), second.UpdateTimestamp)
+	})
+}
+
+// settableClock is an ext.Clock whose current time is set by a test.
+type settableClock struct {
+	now time.Time
+}
+
+func (c *settableClock) Now() time.Time {
+	return c.now
+}
+
+func TestConverter_Convert_Workload(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should leave workload empty by default", func(t *testing.T) {
+		report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Empty(t, report.WorkloadKind)
+		assert.Empty(t, report.WorkloadName)
+		assert.Empty(t, report.Namespace)
+	})
+
+	t.Run("Should copy workload from config", func(t *testing.T) {
+		startTime := time.Date(2020, 10, 14, 8, 0, 0, 0, time.UTC)
+		completionTime := time.Date(2020, 10, 14, 8, 1, 30, 0, time.UTC)
+		workloadConfig := trivy.WithWorkload(trivy.WithScanTimes(config, startTime, completionTime), kube.Object{
+			Kind:      kube.KindDeployment,
+			Name:      "nginx",
+			Namespace: "default",
+		})
+
+		report, err := converter.Convert(workloadConfig, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, "Deployment", report.WorkloadKind)
+		assert.Equal(t, "nginx", report.WorkloadName)
+		assert.Equal(t, "default", report.Namespace)
+		assert.Equal(t, metav1.NewTime(completionTime), report.UpdateTimestamp)
+	})
+}
+
+func TestConverter_Convert_CweIDs(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name           string
+		input          string
+		expectedCweIDs []string
+	}{
+		{
+			name: "Should parse multiple CWE identifiers",
+			input: `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [{
+				"VulnerabilityID": "CVE-2020-11022",
+				"PkgName": "jquery",
+				"InstalledVersion": "3.4.1",
+				"Severity": "MEDIUM",
+				"CweIDs": ["CWE-79", "CWE-89"]
+			}]}]`,
+			expectedCweIDs: []string{"CWE-79", "CWE-89"},
+		},
+		{
+			name: "Should return empty slice when CWE identifiers are missing",
+			input: `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [{
+				"VulnerabilityID": "CVE-2019-1549",
+				"PkgName": "openssl",
+				"InstalledVersion": "1.1.1c-r0",
+				"Severity": "MEDIUM"
+			}]}]`,
+			expectedCweIDs: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.NotNil(t, report.Vulnerabilities[0].CweIDs)
+			assert.Equal(t, tc.expectedCweIDs, report.Vulnerabilities[0].CweIDs)
+		})
+	}
+}
+
+func TestConverter_Convert_Gzip(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err := gzipWriter.Write([]byte(sampleReportAsString))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	plainReport, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+	require.NoError(t, err)
+
+	gzipReport, err := converter.Convert(config, "alpine:3.10.2", &compressed)
+	require.NoError(t, err)
+
+	assert.Equal(t, sampleReport, plainReport)
+	assert.Equal(t, plainReport, gzipReport)
+
+	t.Run("Should return error when gzip stream is corrupted", func(t *testing.T) {
+		_, err := converter.Convert(config, "alpine:3.10.2", bytes.NewReader([]byte{0x1f, 0x8b, 0x00}))
+		assert.Error(t, err)
+	})
+}
+
+func TestConverter_Convert_MaxVulnerabilities(t *testing.T) {
+	input := `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2020-0001", "PkgName": "musl", "InstalledVersion": "1.1.22-r3", "Severity": "LOW"},
+		{"VulnerabilityID": "CVE-2020-0002", "PkgName": "openssl", "InstalledVersion": "1.1.1c-r0", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2020-0003", "PkgName": "zlib", "InstalledVersion": "1.2.11-r1", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2020-0004", "PkgName": "busybox", "InstalledVersion": "1.30.1-r2", "Severity": "HIGH"}
+	]}]`
+	expectedSummary := starboardv1alpha1.VulnerabilitySummary{
+		CriticalCount: 1,
+		HighCount:     1,
+		MediumCount:   1,
+		LowCount:      1,
+		RiskScore:     18,
+		Fixable:       &starboardv1alpha1.FixableSummary{},
+	}
+
+	testCases := []struct {
+		name               string
+		maxVulnerabilities string
+		expectedIDs        []string
+		expectedTruncated  bool
+		expectedDropped    int
+	}{
+		{
+			name:               "Should keep all vulnerabilities when under limit",
+			maxVulnerabilities: "10",
+			expectedIDs:        []string{"CVE-2020-0002", "CVE-2020-0004", "CVE-2020-0003", "CVE-2020-0001"},
+		},
+		{
+			name:               "Should keep all vulnerabilities when at limit",
+			maxVulnerabilities: "4",
+			expectedIDs:        []string{"CVE-2020-0002", "CVE-2020-0004", "CVE-2020-0003", "CVE-2020-0001"},
+		},
+		{
+			name:               "Should keep most severe vulnerabilities when over limit",
+			maxVulnerabilities: "2",
+			expectedIDs:        []string{"CVE-2020-0002", "CVE-2020-0004"},
+			expectedTruncated:  true,
+			expectedDropped:    2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef":           "aquasec/trivy:0.9.1",
+				"trivy.maxVulnerabilities": tc.maxVulnerabilities,
+			}
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+
+			var ids []string
+			for _, v := range report.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedTruncated, report.Truncated)
+			assert.Equal(t, tc.expectedDropped, report.DroppedCount)
+			assert.Equal(t, expectedSummary, report.Summary)
+		})
+	}
+}
+
+func TestConverter_Convert_ScanError(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name            string
+		input           string
+		expectedMessage string
+	}{
+		{
+			name: "Should return error when vulnerability DB cannot be downloaded",
+			input: `2020-06-17T23:37:45.320+0200	INFO	Need to update DB
+2020-06-17T23:37:45.320+0200	INFO	Downloading DB...
+2020-06-17T23:37:46.102+0200	FATAL	failed to download vulnerability DB: failed to download vulnerability DB: GET https://api.github.com/repos/aquasecurity/trivy-db/releases: 403 API rate limit exceeded
+[]`,
+			expectedMessage: "2020-06-17T23:37:46.102+0200	FATAL	failed to download vulnerability DB: failed to download vulnerability DB: GET https://api.github.com/repos/aquasecurity/trivy-db/releases: 403 API rate limit exceeded",
+		},
+		{
+			name: "Should return error when image is not found",
+			input: `2020-06-21T23:10:15.162+0200	FATAL	unable to initialize a scanner: unable to initialize a docker scanner: 2 errors occurred:
+	* unable to inspect the image (no-such-image:1.0): Error: No such image: no-such-image:1.0
+	* GET https://index.docker.io/v2/library/no-such-image/manifests/1.0: MANIFEST_UNKNOWN: manifest unknown`,
+			expectedMessage: "2020-06-21T23:10:15.162+0200	FATAL	unable to initialize a scanner: unable to initialize a docker scanner: 2 errors occurred:",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+			var scanErr *trivy.ScanError
+			require.True(t, errors.As(err, &scanErr), "expected ScanError but got: %v", err)
+			assert.Equal(t, tc.expectedMessage, scanErr.Message)
+		})
+	}
+
+	t.Run("Should ignore error signatures in vulnerability descriptions", func(t *testing.T) {
+		input := `2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...
+[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "MEDIUM",
+			"Description": "The parent process is unable to reseed the random number generator."
+		}
+		]
+	}
+]`
+		report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Len(t, report.Vulnerabilities, 1)
+	})
+}
+
+func TestConverter_Convert_RiskScore(t *testing.T) {
+	toInput := func(severities ...string) string {
+		var vulnerabilities []string
+		for i, severity := range severities {
+			vulnerabilities = append(vulnerabilities, fmt.Sprintf(`{"VulnerabilityID": "CVE-2020-%04d", "PkgName": "openssl", "InstalledVersion": "1.1.1c-r0", "Severity": %q}`, i, severity))
+		}
+		return fmt.Sprintf(`[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [%s]}]`, strings.Join(vulnerabilities, ","))
+	}
+
+	testCases := []struct {
+		name              string
+		weights           string
+		severities        []string
+		expectedRiskScore int
+	}{
+		{
+			name:              "Should return zero when there are no vulnerabilities",
+			severities:        []string{},
+			expectedRiskScore: 0,
+		},
+		{
+			name:              "Should weight vulnerabilities with default weights",
+			severities:        []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"},
+			expectedRiskScore: 10 + 5 + 2 + 1 + 1,
+		},
+		{
+			name:              "Should weight many vulnerabilities of the same severity",
+			severities:        []string{"CRITICAL", "CRITICAL", "CRITICAL", "LOW"},
+			expectedRiskScore: 3*10 + 1,
+		},
+		{
+			name:              "Should weight vulnerabilities with custom weights",
+			weights:           "CRITICAL=100,HIGH=20,UNKNOWN=0",
+			severities:        []string{"CRITICAL", "HIGH", "HIGH", "MEDIUM", "UNKNOWN"},
+			expectedRiskScore: 100 + 2*20 + 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			}
+			if tc.weights != "" {
+				config["trivy.riskScoreWeights"] = tc.weights
+			}
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(toInput(tc.severities...)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRiskScore, report.Summary.RiskScore)
+		})
+	}
+}
+
+func TestConverter_ConvertAll(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should convert all reports", func(t *testing.T) {
+		results, err := converter.ConvertAll(config, map[string]io.Reader{
+			"alpine:3.10.2": strings.NewReader(sampleReportAsString),
+			"nginx:1.16":    strings.NewReader("null"),
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, sampleReport, results["alpine:3.10.2"])
+		assert.Equal(t, "library/nginx", results["nginx:1.16"].Artifact.Repository)
+		assert.Empty(t, results["nginx:1.16"].Vulnerabilities)
+	})
+
+	t.Run("Should return successful results and aggregate errors", func(t *testing.T) {
+		results, err := converter.ConvertAll(config, map[string]io.Reader{
+			"alpine:3.10.2": strings.NewReader(sampleReportAsString),
+			"nginx:1.16":    strings.NewReader("not a report"),
+			"redis:5":       strings.NewReader(`[{"Target": 5}]`),
+		})
+		require.Error(t, err)
+		assert.Equal(t, map[string]starboardv1alpha1.VulnerabilityScanResult{
+			"alpine:3.10.2": sampleReport,
+		}, results)
+		assert.Contains(t, err.Error(), "converting report of image nginx:1.16: ")
+		assert.Contains(t, err.Error(), "converting report of image redis:5: ")
+		assert.Contains(t, err.Error(), "json: cannot unmarshal number")
+	})
+
+	t.Run("Should return empty results when there are no references", func(t *testing.T) {
+		results, err := converter.ConvertAll(config, map[string]io.Reader{})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestConverter_Convert_Scanner(t *testing.T) {
+	testCases := []struct {
+		name            string
+		configData      starboard.ConfigData
+		expectedScanner starboardv1alpha1.Scanner
+	}{
+		{
+			name: "Should use default scanner name and vendor",
+			configData: starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			},
+			expectedScanner: starboardv1alpha1.Scanner{
+				Name:    "Trivy",
+				Vendor:  "Aqua Security",
+				Version: "0.9.1",
+			},
+		},
+		{
+			name: "Should use scanner name and vendor from config",
+			configData: starboard.ConfigData{
+				"trivy.imageRef":      "registry.acme.com/security/scanner:1.2.0",
+				"trivy.scannerName":   "Acme Scanner",
+				"trivy.scannerVendor": "Acme Corp",
+			},
+			expectedScanner: starboardv1alpha1.Scanner{
+				Name:    "Acme Scanner",
+				Vendor:  "Acme Corp",
+				Version: "1.2.0",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter().Convert(tc.configData, "alpine:3.10.2", strings.NewReader("null"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedScanner, report.Scanner)
+		})
+	}
+}
+
+func TestConverter_Convert_MalformedImageRef(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	report, err := trivy.NewConverter().Convert(config, "Alpine:3.10.2:latest", strings.NewReader(sampleReportAsString))
+	require.NoError(t, err)
+	assert.Equal(t, starboardv1alpha1.Registry{}, report.Registry)
+	assert.Equal(t, starboardv1alpha1.Artifact{Repository: "Alpine:3.10.2:latest"}, report.Artifact)
+	assert.Len(t, report.Vulnerabilities, 2)
+	assert.Equal(t, 2, report.Summary.Total())
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], `parsing image reference "Alpine:3.10.2:latest": `)
+}
+
+func TestConverter_Convert_PkgPath(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "tomcat:9.0 (debian 10.4)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "MEDIUM"
+		}
+		]
+	},
+	{
+		"Target": "Java",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2020-9546",
+			"PkgName": "com.fasterxml.jackson.core:jackson-databind",
+			"PkgPath": "usr/local/tomcat/webapps/app.jar",
+			"InstalledVersion": "2.9.10.3",
+			"Severity": "CRITICAL"
+		}
+		]
+	}
+]`
+
+	report, err := trivy.NewConverter().Convert(config, "tomcat:9.0", strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, report.Vulnerabilities, 2)
+
+	pkgPaths := make(map[string]string)
+	for _, v := range report.Vulnerabilities {
+		pkgPaths[v.Resource] = v.PkgPath
+	}
+	assert.Equal(t, map[string]string{
+		"openssl": "",
+		"com.fasterxml.jackson.core:jackson-databind": "usr/local/tomcat/webapps/app.jar",
+	}, pkgPaths)
+}
+
+func TestConverter_Convert_Sorting(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "debian:10.4 (debian 10.4)",
+		"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2020-1967", "PkgName": "openssl", "InstalledVersion": "1.1.1d-0", "Severity": "LOW"},
+		{"VulnerabilityID": "CVE-2019-18276", "PkgName": "bash", "InstalledVersion": "5.0-4", "Severity": "HIGH"},
+		{"VulnerabilityID": "CVE-2020-1971", "PkgName": "openssl", "InstalledVersion": "1.1.1d-0", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2020-1971", "PkgName": "libssl1.1", "InstalledVersion": "1.1.1d-0", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2019-12900", "PkgName": "bzip2", "InstalledVersion": "1.0.6-9", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2018-12886", "PkgName": "gcc-8-base", "InstalledVersion": "8.3.0-6", "Severity": "HIGH"},
+		{"VulnerabilityID": "CVE-2019-25013", "PkgName": "libc6", "InstalledVersion": "2.28-10", "Severity": "UNKNOWN"}
+		]
+	}
+]`
+
+	report, err := trivy.NewConverter().Convert(config, "debian:10.4", strings.NewReader(input))
+	require.NoError(t, err)
+
+	var keys []string
+	for _, v := range report.Vulnerabilities {
+		keys = append(keys, fmt.Sprintf("%s %s %s", v.Severity, v.VulnerabilityID, v.Resource))
+	}
+	assert.Equal(t, []string{
+		"CRITICAL CVE-2019-12900 bzip2",
+		"HIGH CVE-2018-12886 gcc-8-base",
+		"HIGH CVE-2019-18276 bash",
+		"MEDIUM CVE-2020-1971 libssl1.1",
+		"MEDIUM CVE-2020-1971 openssl",
+		"LOW CVE-2020-1967 openssl",
+		"UNKNOWN CVE-2019-25013 libc6",
+	}, keys)
+}
+
+func TestConverter_Convert_FixableSummary(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "debian:10.4 (debian 10.4)",
+		"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2019-12900", "PkgName": "bzip2", "InstalledVersion": "1.0.6-9", "FixedVersion": "1.0.6-9.1", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2019-18276", "PkgName": "bash", "InstalledVersion": "5.0-4", "Severity": "HIGH"},
+		{"VulnerabilityID": "CVE-2018-12886", "PkgName": "gcc-8-base", "InstalledVersion": "8.3.0-6", "Severity": "HIGH"},
+		{"VulnerabilityID": "CVE-2020-1971", "PkgName": "openssl", "InstalledVersion": "1.1.1d-0", "FixedVersion": "1.1.1d-0+deb10u4", "Severity": "HIGH"},
+		{"VulnerabilityID": "CVE-2020-1967", "PkgName": "openssl", "InstalledVersion": "1.1.1d-0", "FixedVersion": "1.1.1d-0+deb10u3", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2019-25013", "PkgName": "libc6", "InstalledVersion": "2.28-10", "Severity": "LOW"},
+		{"VulnerabilityID": "CVE-2020-27350", "PkgName": "apt", "InstalledVersion": "1.8.2", "FixedVersion": "1.8.2.2", "Severity": "UNKNOWN"}
+		]
+	}
+]`
+
+	report, err := trivy.NewConverter().Convert(config, "debian:10.4", strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Summary.CriticalCount)
+	assert.Equal(t, 3, report.Summary.HighCount)
+	assert.Equal(t, 1, report.Summary.MediumCount)
+	assert.Equal(t, 1, report.Summary.LowCount)
+	assert.Equal(t, 1, report.Summary.UnknownCount)
+	assert.Equal(t, &starboardv1alpha1.FixableSummary{
+		CriticalCount: 1,
+		HighCount:     1,
+		MediumCount:   1,
+		LowCount:      0,
+		UnknownCount:  1,
+	}, report.Summary.Fixable)
+}
+
+func TestConverter_Convert_SchemaVersionedReport(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+	schemaVersionedReport := fmt.Sprintf(`{
+	"SchemaVersion": 2,
+	"ArtifactName": "alpine:3.10.2",
+	"ArtifactType": "container_image",
+	"Metadata": {"OS": {"Family": "alpine", "Name": "3.10.2"}},
+	"Results": %s
+}`, sampleReportAsString)
+	sampleReportWithOS := sampleReport
+	sampleReportWithOS.OSFamily = "alpine"
+	sampleReportWithOS.OSVersion = "3.10.2"
+
+	testCases := []struct {
+		name           string
+		input          string
+		expectedReport starboardv1alpha1.VulnerabilityScanResult
+	}{
+		{
+			name:           "Should convert legacy array format",
+			input:          sampleReportAsString,
+			expectedReport: sampleReport,
+		},
+		{
+			name:           "Should convert schema-versioned format",
+			input:          schemaVersionedReport,
+			expectedReport: sampleReportWithOS,
+		},
+		{
+			name:           "Should convert schema-versioned format when input is noisy",
+			input:          "2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\n" + schemaVersionedReport,
+			expectedReport: sampleReportWithOS,
+		},
+		{
+			name:           "Should skip structured log messages before schema-versioned format",
+			input:          `{"level":"info","msg":"Detecting Alpine vulnerabilities..."}` + "\n" + schemaVersionedReport,
+			expectedReport: sampleReportWithOS,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedReport, report)
+		})
+	}
+
+	t.Run("Should return no vulnerabilities when results are absent", func(t *testing.T) {
+		for _, input := range []string{
+			`{"SchemaVersion": 2, "ArtifactName": "alpine:3.10.2"}`,
+			`{"SchemaVersion": 2, "Results": null}`,
+		} {
+			report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+			assert.Empty(t, report.Vulnerabilities)
+		}
+	})
+
+	t.Run("Should return error when results are not an array", func(t *testing.T) {
+		_, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(`{"SchemaVersion": 2, "Results": {}}`))
+		assert.EqualError(t, err, "decoding trivy scan output of image alpine:3.10.2 at offset 35: expected JSON array of results but got: {")
+		assert.True(t, errors.Is(err, trivy.ErrMalformedOutput))
+	})
+}
+
+func TestConverter_Convert_VersionResolver(t *testing.T) {
+	testCases := []struct {
+		name             string
+		trivyImageRef    string
+		options          []trivy.Option
+		expectedVersion  string
+		expectedWarnings []string
+	}{
+		{
+			name:            "Should resolve version from tag of Trivy image reference",
+			trivyImageRef:   "aquasec/trivy:0.9.1",
+			expectedVersion: "0.9.1",
+		},
+		{
+			name:            "Should resolve version from digest of Trivy image reference",
+			trivyImageRef:   "mirror.acme.com/aquasec/trivy@sha256:8a27f8c0196a5b8bdb36a2a5a2f43a9d6e580e3c8990ac5f3218ca4c8aa7d2f8",
+			expectedVersion: "sha256:8a27f8c0196a5b8bdb36a2a5a2f43a9d6e580e3c8990ac5f3218ca4c8aa7d2f8",
+		},
+		{
+			name:          "Should resolve version with custom resolver",
+			trivyImageRef: "mirror.acme.com/aquasec/trivy@sha256:8a27f8c0196a5b8bdb36a2a5a2f43a9d6e580e3c8990ac5f3218ca4c8aa7d2f8",
+			options: []trivy.Option{
+				trivy.WithVersionResolver(func(_ trivy.Config) (string, error) {
+					return "0.11.0", nil
+				}),
+			},
+			expectedVersion: "0.11.0",
+		},
+		{
+			name:             "Should fall back to unknown version when Trivy image reference cannot be parsed",
+			trivyImageRef:    "aquasec/trivy@latest",
+			expectedVersion:  "unknown",
+			expectedWarnings: []string{"resolving scanner version: parsing reference: could not parse reference: aquasec/trivy@latest"},
+		},
+		{
+			name:          "Should fall back to unknown version when custom resolver fails",
+			trivyImageRef: "aquasec/trivy:0.9.1",
+			options: []trivy.Option{
+				trivy.WithVersionResolver(func(_ trivy.Config) (string, error) {
+					return "", errors.New("TRIVY_VERSION is not set")
+				}),
+			},
+			expectedVersion:  "unknown",
+			expectedWarnings: []string{"resolving scanner version: TRIVY_VERSION is not set"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef": tc.trivyImageRef,
+			}
+			report, err := trivy.NewConverter(tc.options...).Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, report.Scanner.Version)
+			assert.Equal(t, tc.expectedWarnings, report.Warnings)
+			assert.Len(t, report.Vulnerabilities, 2)
+		})
+	}
+}
+
+func TestConverter_Convert_DebugLogs(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef":           "aquasec/trivy:0.9.1",
+		"trivy.severityThreshold":  "MEDIUM",
+		"trivy.maxVulnerabilities": "1",
+	}
+	preamble := "2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\n"
+	input := preamble + `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2019-14697", "PkgName": "musl", "InstalledVersion": "1.1.22-r2", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2019-1549", "PkgName": "openssl", "InstalledVersion": "1.1.1c-r0", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2019-1547", "PkgName": "openssl", "InstalledVersion": "1.1.1c-r0", "Severity": "LOW"}
+		]
+	},
+	{
+		"Target": "usr/local/bin/app",
+		"Vulnerabilities": null
+	}
+]`
+
+	t.Run("Should log debug messages", func(t *testing.T) {
+		logger := newVerboseTestLogger(1)
+		_, err := trivy.NewConverter(trivy.WithLogger(logger)).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			fmt.Sprint("Skipped noisy output", "offset", len(preamble)),
+			fmt.Sprint("Decoded scan reports", "count", 2),
+			fmt.Sprint("Filtered vulnerabilities", "detected", 3, "kept", 2,
+				"belowThreshold", 1, "belowMinScore", 0, "ignored", 0, "unfixed", 0, "deduplicated", 0,
+				"excludedPackages", 0),
+			fmt.Sprint("Classified image", "imageType", starboardv1alpha1.ImageTypeFull),
+			fmt.Sprint("Truncated vulnerabilities", "kept", 1, "dropped", 1),
+		}, *logger.messages)
+	})
+
+	t.Run("Should not log debug messages when verbosity is zero", func(t *testing.T) {
+		logger := newTestLogger()
+		_, err := trivy.NewConverter(trivy.WithLogger(logger)).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Empty(t, *logger.messages)
+	})
+}
+
+func TestConverter_Convert_Digest(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name             string
+		imageRef         string
+		expectedArtifact starboardv1alpha1.Artifact
+		expectedWarning  string
+	}{
+		{
+			name:     "Should accept valid sha256 digest",
+			imageRef: "nginx@sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Digest:     "sha256:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+		},
+		{
+			name:     "Should reject truncated sha256 digest",
+			imageRef: "nginx@sha256:xyz",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "nginx@sha256:xyz",
+			},
+			expectedWarning: `parsing image reference "nginx@sha256:xyz": malformed sha256 digest "sha256:xyz": expected 64 hex digits but got 3`,
+		},
+		{
+			name:     "Should reject sha256 digest with invalid characters",
+			imageRef: "nginx@sha256:D20AA6D1CAE56FD17CD458F4807E0DE462CAF2336F0B70B5EEB69FCAAF30DD9C",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "nginx@sha256:D20AA6D1CAE56FD17CD458F4807E0DE462CAF2336F0B70B5EEB69FCAAF30DD9C",
+			},
+			expectedWarning: `parsing image reference "nginx@sha256:D20AA6D1CAE56FD17CD458F4807E0DE462CAF2336F0B70B5EEB69FCAAF30DD9C": malformed sha256 digest "sha256:D20AA6D1CAE56FD17CD458F4807E0DE462CAF2336F0B70B5EEB69FCAAF30DD9C": unexpected character 'D'`,
+		},
+		{
+			name:     "Should reject unsupported digest algorithm",
+			imageRef: "nginx@sha512:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "nginx@sha512:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c",
+			},
+			expectedWarning: `parsing image reference "nginx@sha512:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c": unsupported digest algorithm "sha512" in digest "sha512:d20aa6d1cae56fd17cd458f4807e0de462caf2336f0b70b5eeb69fcaaf30dd9c"`,
+		},
+		{
+			name:     "Should not validate tag",
+			imageRef: "nginx:1.16",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository: "library/nginx",
+				Tag:        "1.16",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter().Convert(config, tc.imageRef, strings.NewReader("null"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArtifact, report.Artifact)
+			if tc.expectedWarning == "" {
+				assert.Empty(t, report.Warnings)
+			} else {
+				assert.Equal(t, []string{tc.expectedWarning}, report.Warnings)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertBytes(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err := gzipWriter.Write([]byte(sampleReportAsString))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	testCases := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "Should convert output without noise",
+			input: []byte(sampleReportAsString),
+		},
+		{
+			name: "Should convert output preceded by noise",
+			input: []byte(`2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...
+[1/2] Downloading DB
+` + sampleReportAsString),
+		},
+		{
+			name:  "Should convert gzip compressed output",
+			input: compressed.Bytes(),
+		},
+		{
+			name:  "Should convert null output",
+			input: []byte("2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\nnull"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := converter.Convert(config, "alpine:3.10.2", bytes.NewReader(tc.input))
+			require.NoError(t, err)
+
+			actual, err := converter.ConvertBytes(config, "alpine:3.10.2", tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	t.Run("Should return ScanError when scan failed", func(t *testing.T) {
+		input := []byte(`2020-06-17T23:37:45.320+0200	INFO	Need to update DB
+2020-06-17T23:37:46.102+0200	FATAL	failed to download vulnerability DB
+[]`)
+		_, err := converter.ConvertBytes(config, "alpine:3.10.2", input)
+		var scanErr *trivy.ScanError
+		require.True(t, errors.As(err, &scanErr), "expected ScanError but got: %v", err)
+		assert.Equal(t, "2020-06-17T23:37:46.102+0200	FATAL	failed to download vulnerability DB", scanErr.Message)
+	})
+
+	t.Run("Should return ScanError when JSON output is missing", func(t *testing.T) {
+		input := []byte("2020-06-21T23:10:15.162+0200	FATAL	unable to initialize a scanner")
+		_, err := converter.ConvertBytes(config, "alpine:3.10.2", input)
+		var scanErr *trivy.ScanError
+		require.True(t, errors.As(err, &scanErr), "expected ScanError but got: %v", err)
+	})
+}
+
+func TestConverter_Convert_FixedVersions(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name                  string
+		fixedVersion          string
+		expectedFixedVersions []string
+		expectedFixable       int
+	}{
+		{
+			name:                  "Should parse single fixed version",
+			fixedVersion:          "1.1.1d-r0",
+			expectedFixedVersions: []string{"1.1.1d-r0"},
+			expectedFixable:       1,
+		},
+		{
+			name:                  "Should parse multiple fixed versions",
+			fixedVersion:          "1.2.3, 2.0.1",
+			expectedFixedVersions: []string{"1.2.3", "2.0.1"},
+			expectedFixable:       1,
+		},
+		{
+			name:                  "Should return empty slice when fixed version is empty",
+			fixedVersion:          "",
+			expectedFixedVersions: []string{},
+			expectedFixable:       0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := fmt.Sprintf(`[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [{
+				"VulnerabilityID": "CVE-2019-1549",
+				"PkgName": "openssl",
+				"InstalledVersion": "1.1.1c-r0",
+				"FixedVersion": %q,
+				"Severity": "MEDIUM"
+			}]}]`, tc.fixedVersion)
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.Equal(t, tc.fixedVersion, report.Vulnerabilities[0].FixedVersion)
+			assert.Equal(t, tc.expectedFixedVersions, report.Vulnerabilities[0].FixedVersions)
+			assert.Equal(t, tc.expectedFixable, report.Summary.Fixable.MediumCount)
+		})
+	}
+}
+
+func TestConverter_Convert_Dates(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "MEDIUM",
+			"PublishedDate": "2019-09-10T17:15:00Z",
+			"LastModifiedDate": "2020-10-20T22:15:00+02:00"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1547",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "LOW"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1551",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "LOW",
+			"PublishedDate": "10 Sep 2019",
+			"LastModifiedDate": "2020-10-20T22:15:00Z"
+		}
+	]}]`
+
+	logger := newTestLogger()
+	report, err := trivy.NewConverter(trivy.WithLogger(logger)).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, report.Vulnerabilities, 3)
+
+	t.Run("Should parse valid dates", func(t *testing.T) {
+		v := report.Vulnerabilities[0]
+		require.NotNil(t, v.PublishedDate)
+		require.NotNil(t, v.LastModifiedDate)
+		assert.True(t, time.Date(2019, 9, 10, 17, 15, 0, 0, time.UTC).Equal(v.PublishedDate.Time))
+		assert.True(t, time.Date(2020, 10, 20, 20, 15, 0, 0, time.UTC).Equal(v.LastModifiedDate.Time))
+	})
+
+	t.Run("Should leave missing dates nil", func(t *testing.T) {
+		v := report.Vulnerabilities[1]
+		assert.Equal(t, "CVE-2019-1547", v.VulnerabilityID)
+		assert.Nil(t, v.PublishedDate)
+		assert.Nil(t, v.LastModifiedDate)
+	})
+
+	t.Run("Should leave malformed dates nil and log error", func(t *testing.T) {
+		v := report.Vulnerabilities[2]
+		assert.Equal(t, "CVE-2019-1551", v.VulnerabilityID)
+		assert.Nil(t, v.PublishedDate)
+		assert.NotNil(t, v.LastModifiedDate)
+		require.Len(t, *logger.messages, 1)
+		assert.Contains(t, (*logger.messages)[0], "Ignoring malformed date")
+		assert.Contains(t, (*logger.messages)[0], "CVE-2019-1551")
+		assert.Contains(t, (*logger.messages)[0], "PublishedDate")
+	})
+}
+
+func TestConverter_Convert_Secrets(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `{
+	"SchemaVersion": 2,
+	"ArtifactName": "alpine:3.10.2",
+	"ArtifactType": "container_image",
+	"Results": [
+		{
+			"Target": "alpine:3.10.2 (alpine 3.10.2)",
+			"Class": "os-pkgs",
+			"Type": "alpine",
+			"Vulnerabilities": [
+				{
+					"VulnerabilityID": "CVE-2019-1549",
+					"PkgName": "openssl",
+					"InstalledVersion": "1.1.1c-r0",
+					"Severity": "MEDIUM"
+				}
+			]
+		},
+		{
+			"Target": "/app/config.env",
+			"Class": "secret",
+			"Secrets": [
+				{
+					"RuleID": "aws-access-key-id",
+					"Category": "AWS",
+					"Severity": "critical",
+					"Title": "AWS Access Key ID",
+					"StartLine": 3,
+					"EndLine": 3,
+					"Match": "AWS_ACCESS_KEY_ID=********************"
+				}
+			]
+		},
+		{
+			"Target": "Dockerfile",
+			"Class": "config",
+			"Type": "dockerfile",
+			"Vulnerabilities": [
+				{
+					"VulnerabilityID": "DS002",
+					"PkgName": "Dockerfile",
+					"Severity": "HIGH"
+				}
+			]
+		}
+	]
+}`
+
+	report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(config, "alpine:3.10.2", strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []starboardv1alpha1.SecretFinding{
+		{
+			RuleID:    "aws-access-key-id",
+			Category:  "AWS",
+			Severity:  starboardv1alpha1.SeverityCritical,
+			Title:     "AWS Access Key ID",
+			Target:    "/app/config.env",
+			StartLine: 3,
+			EndLine:   3,
+		},
+	}, report.Secrets)
+	require.Len(t, report.Vulnerabilities, 1)
+	assert.Equal(t, "CVE-2019-1549", report.Vulnerabilities[0].VulnerabilityID)
+	assert.Equal(t, 1, report.Summary.Total())
+
+	t.Run("Should leave secrets empty when none are detected", func(t *testing.T) {
+		report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Empty(t, report.Secrets)
+	})
+}
+
+func TestConverter_Convert_OperatingSystem(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name              string
+		input             string
+		expectedOSFamily  string
+		expectedOSVersion string
+		expectedHasOS     bool
+	}{
+		{
+			name: "Should set operating system of Debian-based image",
+			input: `{
+	"SchemaVersion": 2,
+	"ArtifactName": "nginx:1.16",
+	"ArtifactType": "container_image",
+	"Metadata": {"OS": {"Family": "debian", "Name": "10.4"}},
+	"Results": [{"Target": "nginx:1.16 (debian 10.4)", "Class": "os-pkgs", "Type": "debian", "Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2019-18276", "PkgName": "bash", "InstalledVersion": "5.0-4", "Severity": "LOW"}
+	]}]
+}`,
+			expectedOSFamily:  "debian",
+			expectedOSVersion: "10.4",
+			expectedHasOS:     true,
+		},
+		{
+			name: "Should leave operating system empty for scratch image",
+			input: `{
+	"SchemaVersion": 2,
+	"ArtifactName": "app:1.0",
+	"ArtifactType": "container_image",
+	"Metadata": {"ImageID": "sha256:9b3d2a3a5b6a9f0f7fa1b7a0a1c4cb34b9f4a8c3e8e9e8b1f7d2a3b4c5d6e7f8"},
+	"Results": [{"Target": "usr/local/bin/app", "Class": "lang-pkgs", "Type": "gobinary", "Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2020-14040", "PkgName": "golang.org/x/text", "InstalledVersion": "v0.3.2", "Severity": "HIGH"}
+	]}]
+}`,
+			expectedHasOS: false,
+		},
+		{
+			name:          "Should leave operating system empty for legacy array format",
+			input:         sampleReportAsString,
+			expectedHasOS: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOSFamily, report.OSFamily)
+			assert.Equal(t, tc.expectedOSVersion, report.OSVersion)
+			assert.Equal(t, tc.expectedHasOS, report.HasOperatingSystem())
+		})
+	}
+}
+
+func TestConverter_Convert_MaxLinks(t *testing.T) {
+	input := `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [{
+		"VulnerabilityID": "CVE-2019-1549",
+		"PkgName": "openssl",
+		"InstalledVersion": "1.1.1c-r0",
+		"Severity": "MEDIUM",
+		"References": [
+			"http://lists.opensuse.org/opensuse-security-announce/2019-10/msg00054.html",
+			"https://access.redhat.com/errata/RHSA-2019:3700",
+			"http://www.openwall.com/lists/oss-security/2019/09/10/1",
+			"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+			"https://security.netapp.com/advisory/ntap-20190919-0002/"
+		]
+	}]}]`
+
+	testCases := []struct {
+		name          string
+		maxLinks      string
+		expectedLinks []string
+	}{
+		{
+			name: "Should keep all links by default",
+			expectedLinks: []string{
+				"http://lists.opensuse.org/opensuse-security-announce/2019-10/msg00054.html",
+				"https://access.redhat.com/errata/RHSA-2019:3700",
+				"http://www.openwall.com/lists/oss-security/2019/09/10/1",
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+				"https://security.netapp.com/advisory/ntap-20190919-0002/",
+			},
+		},
+		{
+			name:     "Should keep all links when they do not exceed limit",
+			maxLinks: "5",
+			expectedLinks: []string{
+				"http://lists.opensuse.org/opensuse-security-announce/2019-10/msg00054.html",
+				"https://access.redhat.com/errata/RHSA-2019:3700",
+				"http://www.openwall.com/lists/oss-security/2019/09/10/1",
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+				"https://security.netapp.com/advisory/ntap-20190919-0002/",
+			},
+		},
+		{
+			name:     "Should keep NVD and HTTPS links first when links exceed limit",
+			maxLinks: "3",
+			expectedLinks: []string{
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+				"https://access.redhat.com/errata/RHSA-2019:3700",
+				"https://security.netapp.com/advisory/ntap-20190919-0002/",
+			},
+		},
+		{
+			name:     "Should keep links in reported order within the same preference",
+			maxLinks: "4",
+			expectedLinks: []string{
+				"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+				"https://access.redhat.com/errata/RHSA-2019:3700",
+				"https://security.netapp.com/advisory/ntap-20190919-0002/",
+				"http://lists.opensuse.org/opensuse-security-announce/2019-10/msg00054.html",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			}
+			if tc.maxLinks != "" {
+				config["trivy.maxLinks"] = tc.maxLinks
+			}
+			report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.Equal(t, tc.expectedLinks, report.Vulnerabilities[0].Links)
+			assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2019-1549", report.Vulnerabilities[0].PrimaryURL)
+		})
+	}
+}
+
+func TestConverter_ConvertFile(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should convert report file", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "alpine:3.10.2", "testdata/alpine-3.10.2.json")
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report file with ANSI color codes", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "alpine:3.10.2", "testdata/alpine-3.10.2-color.log")
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report bytes with ANSI color codes", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/alpine-3.10.2-color.log")
+		require.NoError(t, err)
+		report, err := converter.ConvertBytes(config, "alpine:3.10.2", data)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report file with progress lines", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "alpine:3.10.2", "testdata/alpine-3.10.2-progress.log")
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report bytes with progress lines", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/alpine-3.10.2-progress.log")
+		require.NoError(t, err)
+		report, err := converter.ConvertBytes(config, "alpine:3.10.2", data)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report file with UTF-8 byte order mark", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "alpine:3.10.2", "testdata/alpine-3.10.2-bom.json")
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report bytes with UTF-8 byte order mark", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/alpine-3.10.2-bom.json")
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(data, []byte("\xef\xbb\xbf")))
+		report, err := converter.ConvertBytes(config, "alpine:3.10.2", data)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should convert report with UTF-8 byte order mark and noisy output", func(t *testing.T) {
+		input := "\xef\xbb\xbf2020-10-14T09:29:40.123Z\tINFO\tNeed to update DB\n" + sampleReportAsString
+		report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+		report, err = converter.ConvertBytes(config, "alpine:3.10.2", []byte(input))
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should return error when report file does not exist", func(t *testing.T) {
+		_, err := converter.ConvertFile(config, "alpine:3.10.2", "testdata/missing.json")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, os.ErrNotExist), "expected not exist error but got: %v", err)
+		assert.Contains(t, err.Error(), "testdata/missing.json")
+	})
+
+	t.Run("Should return error when report file is invalid", func(t *testing.T) {
+		_, err := converter.ConvertFile(config, "alpine:3.10.2", "testdata/invalid.json")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "expected unexpected EOF but got: %v", err)
+		assert.Contains(t, err.Error(), "testdata/invalid.json")
+	})
+}
+
+func TestConverter_ConvertFilesystem(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	file, err := os.Open("testdata/fs-app.json")
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	report, err := converter.ConvertFilesystem(config, "/app", file)
+	require.NoError(t, err)
+	assert.Equal(t, starboardv1alpha1.Registry{}, report.Registry)
+	assert.Equal(t, starboardv1alpha1.Artifact{Repository: "/app"}, report.Artifact)
+	assert.Empty(t, report.Warnings)
+	assert.Equal(t, starboardv1alpha1.Scanner{
+		Name:    "Trivy",
+		Vendor:  "Aqua Security",
+		Version: "0.9.1",
+	}, report.Scanner)
+	assert.Equal(t, []starboardv1alpha1.Vulnerability{
+		{
+			VulnerabilityID:  "CVE-2021-23337",
+			Resource:         "lodash",
+			InstalledVersion: "4.17.15",
+			FixedVersion:     "4.17.21",
+			FixedVersions:    []string{"4.17.21"},
+			Severity:         starboardv1alpha1.SeverityHigh,
+			Title:            "nodejs-lodash: command injection via template",
+			Description:      "Lodash versions prior to 4.17.21 are vulnerable to Command Injection via the template function.",
+			Links:            []string{"https://nvd.nist.gov/vuln/detail/CVE-2021-23337"},
+			CategorizedLinks: &starboardv1alpha1.CategorizedLinks{
+				Advisory: []string{"https://nvd.nist.gov/vuln/detail/CVE-2021-23337"},
+			},
+			PrimaryURL: "https://avd.aquasec.com/nvd/cve-2021-23337",
+			Target:     "package-lock.json",
+			CweIDs:     []string{},
+			Status:     starboardv1alpha1.VulnerabilityStatusAffected,
+			Ecosystem:  "npm",
+		},
+	}, report.Vulnerabilities)
+	assert.Equal(t, 1, report.Summary.HighCount)
+}
+
+func TestConverter_Convert_RawReport(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := "2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\n" + sampleReportAsString
+
+	t.Run("Should not retain raw report by default", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).
+			Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Nil(t, report.RawReport)
+	})
+
+	t.Run("Should retain raw report without noisy output when reading", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock), trivy.WithRawReport()).
+			Convert(config, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(sampleReportAsString), report.RawReport)
+
+		report.RawReport = nil
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should retain raw report without noisy output when converting bytes", func(t *testing.T) {
+		data := []byte(input)
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock), trivy.WithRawReport()).
+			ConvertBytes(config, "alpine:3.10.2", data)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(sampleReportAsString), report.RawReport)
+
+		data[len(data)-1] = 'x'
+		assert.Equal(t, []byte(sampleReportAsString), report.RawReport)
+	})
+}
+
+func TestConverter_Convert_Architecture(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	testCases := []struct {
+		name                    string
+		path                    string
+		expectedArtifact        starboardv1alpha1.Artifact
+		expectedVulnerabilities []string
+	}{
+		{
+			name: "Should capture amd64 architecture",
+			path: "testdata/debian-10-amd64.json",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository:   "library/debian",
+				Tag:          "10",
+				Architecture: "amd64",
+			},
+			expectedVulnerabilities: []string{"CVE-2021-3711", "CVE-2019-25013"},
+		},
+		{
+			name: "Should capture arm64 architecture",
+			path: "testdata/debian-10-arm64.json",
+			expectedArtifact: starboardv1alpha1.Artifact{
+				Repository:   "library/debian",
+				Tag:          "10",
+				Architecture: "arm64",
+			},
+			expectedVulnerabilities: []string{"CVE-2019-25013", "CVE-2021-33574"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := converter.ConvertFile(config, "debian:10", tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArtifact, report.Artifact)
+			var ids []string
+			for _, v := range report.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedVulnerabilities, ids)
+		})
+	}
+
+	t.Run("Should leave architecture empty when report lacks image config", func(t *testing.T) {
+		report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Empty(t, report.Artifact.Architecture)
+	})
+}
+
+func TestConverter_ConvertAndGate(t *testing.T) {
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	testCases := []struct {
+		name          string
+		failOn        string
+		expectedError error
+	}{
+		{
+			name:   "Should not fail when fail-on severity is not set",
+			failOn: "",
+		},
+		{
+			name:   "Should not fail when threshold is not met",
+			failOn: "CRITICAL",
+		},
+		{
+			name:          "Should fail when vulnerability has fail-on severity",
+			failOn:        "MEDIUM",
+			expectedError: trivy.ErrThresholdExceeded,
+		},
+		{
+			name:          "Should fail when vulnerability exceeds fail-on severity",
+			failOn:        "LOW",
+			expectedError: trivy.ErrThresholdExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef":       "aquasec/trivy:0.9.1",
+				"trivy.failOnSeverity": tc.failOn,
+			}
+			report, err := converter.ConvertAndGate(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v but got: %v", tc.expectedError, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, sampleReport, report)
+		})
+	}
+
+	t.Run("Should return error when fail-on severity is unrecognized", func(t *testing.T) {
+		config := starboard.ConfigData{
+			"trivy.imageRef":       "aquasec/trivy:0.9.1",
+			"trivy.failOnSeverity": "SEVERE",
+		}
+		_, err := converter.ConvertAndGate(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.EqualError(t, err, "unrecognized fail-on severity: SEVERE")
+	})
+}
+
+func TestConverter_Convert_MalformedOutput(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+	truncated := sampleReportAsString[:len(sampleReportAsString)/2]
+
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOffset int64
+		expectedError  string
+	}{
+		{
+			name:           "Should return error with length of truncated output",
+			input:          truncated,
+			expectedOffset: int64(len(truncated)),
+			expectedError: fmt.Sprintf("decoding trivy scan output of image alpine:3.10.2 at offset %d, the output may be truncated: unexpected EOF",
+				len(truncated)),
+		},
+		{
+			name:           "Should return error with offset of syntax error",
+			input:          `[{"Target": "alpine:3.10.2", "Vulnerabilities": x}]`,
+			expectedOffset: 49,
+			expectedError:  "decoding trivy scan output of image alpine:3.10.2 at offset 49: invalid character 'x' looking for beginning of value",
+		},
+		{
+			name:           "Should return offset relative to JSON output when input is noisy",
+			input:          "2020-06-17T23:37:45.320+0200	INFO	Detecting Alpine vulnerabilities...\n" + truncated,
+			expectedOffset: int64(len(truncated)),
+			expectedError: fmt.Sprintf("decoding trivy scan output of image alpine:3.10.2 at offset %d, the output may be truncated: unexpected EOF",
+				len(truncated)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, convert := range map[string]func() error{
+				"Convert": func() error {
+					_, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+					return err
+				},
+				"ConvertBytes": func() error {
+					_, err := converter.ConvertBytes(config, "alpine:3.10.2", []byte(tc.input))
+					return err
+				},
+			} {
+				err := convert()
+				require.Error(t, err, name)
+				assert.True(t, errors.Is(err, trivy.ErrMalformedOutput), "%s: expected malformed output error but got: %v", name, err)
+				var malformedErr *trivy.MalformedOutputError
+				require.True(t, errors.As(err, &malformedErr), name)
+				assert.Equal(t, "alpine:3.10.2", malformedErr.ImageRef, name)
+				assert.Equal(t, tc.expectedOffset, malformedErr.Offset, name)
+				assert.EqualError(t, err, tc.expectedError, name)
+			}
+		})
+	}
+}
+
+func TestConverter_Convert_PackageNameNormalizer(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "debian:10 (debian 10.10)",
+		"Type": "debian",
+		"Vulnerabilities": [
+			{
+				"VulnerabilityID": "CVE-2021-3711",
+				"PkgName": "libssl1.1",
+				"InstalledVersion": "1.1.1d-0+deb10u6",
+				"Severity": "CRITICAL"
+			},
+			{
+				"VulnerabilityID": "CVE-2021-3712",
+				"PkgName": "openssl",
+				"InstalledVersion": "1.1.1d-0+deb10u6",
+				"Severity": "HIGH"
+			},
+			{
+				"VulnerabilityID": "CVE-2021-3711",
+				"PkgName": "openssl",
+				"InstalledVersion": "1.1.1d-0+deb10u6",
+				"Severity": "CRITICAL"
+			},
+			{
+				"VulnerabilityID": "CVE-2021-33574",
+				"PkgName": "libc6",
+				"InstalledVersion": "2.28-10",
+				"Severity": "HIGH"
+			}
+		]
+	}
+]`
+
+	resources := func(report starboardv1alpha1.VulnerabilityScanResult) []string {
+		var result []string
+		for _, v := range report.Vulnerabilities {
+			result = append(result, v.VulnerabilityID+" "+v.Resource)
+		}
+		return result
+	}
+
+	t.Run("Should keep package names by default", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).
+			Convert(config, "debian:10", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"CVE-2021-3711 libssl1.1",
+			"CVE-2021-3711 openssl",
+			"CVE-2021-33574 libc6",
+			"CVE-2021-3712 openssl",
+		}, resources(report))
+	})
+
+	t.Run("Should rewrite aliases to canonical package name", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock),
+			trivy.WithPackageNameNormalizer(trivy.PackageNameMap(map[string]string{
+				"libssl1.1": "openssl",
+			}))).
+			Convert(config, "debian:10", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"CVE-2021-3711 openssl",
+			"CVE-2021-33574 libc6",
+			"CVE-2021-3712 openssl",
+		}, resources(report))
+		assert.Len(t, trivy.GroupByPackage(report)["openssl"], 2)
+	})
+}
+
+func TestConverter_Convert_ScannedTargets(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should record targets of multi-target report", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "example.com/app:1.0", "testdata/app-1.0-multi-target.json")
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"example.com/app:1.0 (debian 10.10)",
+			"usr/local/bin/app",
+			"srv/web/package-lock.json",
+		}, report.ScannedTargets)
+	})
+
+	t.Run("Should not record targets when nothing was scanned", func(t *testing.T) {
+		report, err := converter.Convert(config, "example.com/app:1.0", strings.NewReader("null"))
+		require.NoError(t, err)
+		assert.Nil(t, report.ScannedTargets)
+	})
+}
+
+func TestConverter_Convert_DisallowUnknownFields(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		input         string
+		expectedError string
+	}{
+		{
+			name:          "Should reject unknown field of vulnerability",
+			path:          "testdata/alpine-3.10.2-unknown-field.json",
+			expectedError: `converting report file testdata/alpine-3.10.2-unknown-field.json: decoding trivy scan output of image alpine:3.10.2 at offset 763: json: unknown field "ExperimentalScore"`,
+		},
+		{
+			name:          "Should reject unknown field of schema-versioned report",
+			input:         `{"SchemaVersion": 2, "Results": [], "Attestation": {}}`,
+			expectedError: `decoding trivy scan output of image alpine:3.10.2 at offset 54: json: unknown field "Attestation"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			convert := func(converter trivy.Converter) error {
+				if tc.path != "" {
+					_, err := converter.ConvertFile(config, "alpine:3.10.2", tc.path)
+					return err
+				}
+				_, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+				return err
+			}
+
+			err := convert(trivy.NewConverter(trivy.WithDisallowUnknownFields()))
+			require.EqualError(t, err, tc.expectedError)
+			assert.True(t, errors.Is(err, trivy.ErrMalformedOutput))
+
+			err = convert(trivy.NewConverter())
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("Should accept known fields", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock), trivy.WithDisallowUnknownFields()).
+			Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+}
+
+func TestConverter_ConvertStream(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+	refFor := func(report trivy.Report) string {
+		return report.ArtifactName
+	}
+
+	stream, err := ioutil.ReadFile("testdata/batch.ndjson")
+	require.NoError(t, err)
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err = gzipWriter.Write(stream)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	testCases := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "Should convert good lines and aggregate errors of malformed lines",
+			input: stream,
+		},
+		{
+			name:  "Should convert gzip-compressed stream",
+			input: compressed.Bytes(),
+		},
+		{
+			name:  "Should convert stream with UTF-8 byte order mark",
+			input: append([]byte("\xef\xbb\xbf"), stream...),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := converter.ConvertStream(config, bytes.NewReader(tc.input), refFor)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "converting line 2: ")
+			assert.True(t, errors.Is(err, trivy.ErrMalformedOutput), "expected malformed output error but got: %v", err)
+
+			require.Len(t, results, 2)
+			assert.Equal(t, sampleReport, results[0])
+			assert.Equal(t, starboardv1alpha1.Artifact{Repository: "library/redis", Tag: "5"}, results[1].Artifact)
+			assert.Empty(t, results[1].Vulnerabilities)
+		})
+	}
+
+	t.Run("Should return empty results when stream is empty", func(t *testing.T) {
+		results, err := converter.ConvertStream(config, strings.NewReader("\n\n"), refFor)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestConverter_Convert_ScannerVersionStamp(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	t.Run("Should not stamp scanner version by default", func(t *testing.T) {
+		report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		for _, v := range report.Vulnerabilities {
+			assert.Empty(t, v.ScannerVersion)
+		}
+	})
+
+	t.Run("Should stamp scanner version on each vulnerability", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithScannerVersionStamp()).
+			Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 2)
+		for _, v := range report.Vulnerabilities {
+			assert.Equal(t, "0.9.1", v.ScannerVersion)
+		}
+	})
+
+	t.Run("Should stamp unknown version when version cannot be resolved", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithScannerVersionStamp()).
+			Convert(starboard.ConfigData{"trivy.imageRef": "aquasec/trivy@latest"}, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 2)
+		for _, v := range report.Vulnerabilities {
+			assert.Equal(t, "unknown", v.ScannerVersion)
+		}
+	})
+}
+
+// slowReader is an io.Reader that returns one byte per read after a delay.
+type slowReader struct {
+	data  string
+	delay time.Duration
+	reads int32
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	atomic.AddInt32(&r.reads, 1)
+	time.Sleep(r.delay)
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p[:1], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestConverter_Convert_Timeout(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	t.Run("Should return error when conversion overruns timeout", func(t *testing.T) {
+		reader := &slowReader{data: sampleReportAsString, delay: 5 * time.Millisecond}
+		converter := trivy.NewConverter(trivy.WithConvertTimeout(50 * time.Millisecond))
+
+		start := time.Now()
+		_, err := converter.Convert(config, "alpine:3.10.2", reader)
+		elapsed := time.Since(start)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded but got: %v", err)
+		assert.EqualError(t, err, "conversion timed out after 50ms: context deadline exceeded")
+		assert.Less(t, int64(elapsed), int64(time.Second))
+
+		// The goroutine stops reading once the timeout elapses.
+		reads := atomic.LoadInt32(&reader.reads)
+		time.Sleep(50 * time.Millisecond)
+		assert.LessOrEqual(t, atomic.LoadInt32(&reader.reads), reads+1)
+	})
+
+	t.Run("Should return parent context error when it's done first", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		converter := trivy.NewConverter(trivy.WithConvertTimeout(time.Minute))
+		_, err := converter.ConvertWithContext(ctx, config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled), "expected canceled but got: %v", err)
+	})
+
+	t.Run("Should convert within timeout", func(t *testing.T) {
+		converter := trivy.NewConverter(trivy.WithClock(fixedClock), trivy.WithConvertTimeout(time.Minute))
+		report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+
+		report, err = converter.ConvertBytes(config, "alpine:3.10.2", []byte(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+}
+
+func TestConverter_Convert_EcosystemSummary(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should summarize vulnerabilities by ecosystem", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "example.com/app:2.0", "testdata/app-2.0-ecosystems.json")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]starboardv1alpha1.VulnerabilitySummary{
+			"debian": {
+				HighCount:    2,
+				UnknownCount: 1,
+				RiskScore:    11,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					HighCount: 1,
+				},
+			},
+			"npm": {
+				CriticalCount: 1,
+				RiskScore:     10,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					CriticalCount: 1,
+				},
+			},
+			"pip": {
+				HighCount: 1,
+				LowCount:  1,
+				RiskScore: 6,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					HighCount: 1,
+					LowCount:  1,
+				},
+			},
+			"unknown": {
+				MediumCount: 1,
+				RiskScore:   2,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					MediumCount: 1,
+				},
+			},
+		}, report.EcosystemSummary)
+	})
+
+	t.Run("Should not summarize ecosystems when there are no vulnerabilities", func(t *testing.T) {
+		report, err := converter.Convert(config, "example.com/app:2.0", strings.NewReader("null"))
+		require.NoError(t, err)
+		assert.Nil(t, report.EcosystemSummary)
+	})
+}
+
+func TestConverter_Convert_Aliases(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	report, err := converter.ConvertFile(config, "example.com/node-app:1.0", "testdata/node-app-aliases.json")
+	require.NoError(t, err)
+
+	type identity struct {
+		VulnerabilityID string
+		Resource        string
+		Aliases         []string
+	}
+	var identities []identity
+	for _, v := range report.Vulnerabilities {
+		identities = append(identities, identity{
+			VulnerabilityID: v.VulnerabilityID,
+			Resource:        v.Resource,
+			Aliases:         v.Aliases,
+		})
+	}
+	assert.Equal(t, []identity{
+		// The CVE-primary finding and the GHSA-primary finding with the same
+		// CVE in references are deduplicated.
+		{VulnerabilityID: "CVE-2021-23337", Resource: "lodash", Aliases: []string{"GHSA-35jh-r3h4-6jhm"}},
+		{VulnerabilityID: "GHSA-f8q6-p94x-37v3", Resource: "minimatch"},
+		{VulnerabilityID: "CVE-2022-25883", Resource: "semver", Aliases: []string{"GHSA-c2qf-rxjj-qqgw"}},
+	}, identities)
+}
+
+func TestConverter_Convert_IgnorePolicy(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		ignorePolicy            string
+		opts                    []trivy.Option
+		expectedVulnerabilities []string
+		expectedWarnings        []string
+	}{
+		{
+			name:                    "Should keep vulnerabilities when there is no policy",
+			expectedVulnerabilities: []string{"CVE-2019-1549", "CVE-2019-1547"},
+		},
+		{
+			name:                    "Should omit vulnerabilities of active rules",
+			ignorePolicy:            "# Accepted until the next release\nCVE-2019-1549\nCVE-2019-1547 exp:2020-10-15\n",
+			expectedVulnerabilities: []string{},
+		},
+		{
+			name:                    "Should keep vulnerabilities of expired rules",
+			ignorePolicy:            "CVE-2019-1549 exp:2020-10-14\nCVE-2019-1547 exp:2020-01-01",
+			expectedVulnerabilities: []string{"CVE-2019-1549", "CVE-2019-1547"},
+		},
+		{
+			name:                    "Should warn about expired rules when enabled",
+			ignorePolicy:            "CVE-2019-1549 exp:2020-10-14\nCVE-2019-1547\nCVE-2021-3449 exp:2020-01-01",
+			opts:                    []trivy.Option{trivy.WithExpiredIgnoreWarnings()},
+			expectedVulnerabilities: []string{"CVE-2019-1549"},
+			expectedWarnings:        []string{"ignore rule of CVE-2019-1549 expired on 2020-10-14"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef":     "aquasec/trivy:0.9.1",
+				"trivy.ignorePolicy": tc.ignorePolicy,
+			}
+			converter := trivy.NewConverter(append([]trivy.Option{trivy.WithClock(fixedClock)}, tc.opts...)...)
+			report, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+			require.NoError(t, err)
+			ids := make([]string, 0)
+			for _, v := range report.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedVulnerabilities, ids)
+			assert.Equal(t, tc.expectedWarnings, report.Warnings)
+			assert.Equal(t, len(tc.expectedVulnerabilities), report.Summary.Total())
+		})
+	}
+}
+
+func TestConverter_Convert_IncludedFields(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        starboard.ConfigData
+		expectedTitle string
+		expectedDesc  string
+		expectedLinks []string
+		expectedURL   string
+	}{
+		{
+			name: "Should include all fields by default",
+			config: starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			},
+			expectedTitle: "openssl: information disclosure in fork()",
+			expectedDesc:  "Example description",
+			expectedLinks: []string{"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549"},
+			expectedURL:   "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+		},
+		{
+			name: "Should omit description and links",
+			config: starboard.ConfigData{
+				"trivy.imageRef":      "aquasec/trivy:0.9.1",
+				"trivy.includeFields": "title,primaryURL",
+			},
+			expectedTitle: "openssl: information disclosure in fork()",
+			expectedLinks: []string{},
+			expectedURL:   "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+		},
+		{
+			name: "Should omit fields that are not listed",
+			config: starboard.ConfigData{
+				"trivy.imageRef":      "aquasec/trivy:0.9.1",
+				"trivy.includeFields": "description,links,title",
+			},
+			expectedTitle: "openssl: information disclosure in fork()",
+			expectedDesc:  "Example description",
+			expectedLinks: []string{"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549"},
+		},
+		{
+			name: "Should include all fields when none is listed",
+			config: starboard.ConfigData{
+				"trivy.imageRef":      "aquasec/trivy:0.9.1",
+				"trivy.includeFields": " , ",
+			},
+			expectedTitle: "openssl: information disclosure in fork()",
+			expectedDesc:  "Example description",
+			expectedLinks: []string{"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549"},
+			expectedURL:   "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549",
+		},
+	}
+
+	input := `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Title": "openssl: information disclosure in fork()",
+			"Description": "Example description",
+			"Severity": "MEDIUM",
+			"CweIDs": ["CWE-330"],
+			"References": [
+				"https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-1549"
+			]
+		}
+	]
+	}
+]`
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(tc.config, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			v := report.Vulnerabilities[0]
+			assert.Equal(t, tc.expectedTitle, v.Title)
+			assert.Equal(t, tc.expectedDesc, v.Description)
+			assert.Equal(t, tc.expectedLinks, v.Links)
+			assert.Equal(t, tc.expectedURL, v.PrimaryURL)
+			assert.Equal(t, "CVE-2019-1549", v.VulnerabilityID)
+			assert.Equal(t, 1, report.Summary.MediumCount)
+		})
+	}
+}
+
+func TestConverter_Convert_Windows(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).
+		ConvertFile(config, "mcr.microsoft.com/windows/servercore:ltsc2019", "testdata/windows-servercore-ltsc2019.log")
+	require.NoError(t, err)
+
+	assert.Equal(t, starboardv1alpha1.Registry{Server: "mcr.microsoft.com"}, report.Registry)
+	assert.Equal(t, starboardv1alpha1.Artifact{
+		Repository:   "windows/servercore",
+		Tag:          "ltsc2019",
+		Architecture: "amd64",
+	}, report.Artifact)
+	assert.Equal(t, "windows", report.OSFamily)
+	assert.Equal(t, "10.0.17763.2565", report.OSVersion)
+	assert.Equal(t, []string{
+		"mcr.microsoft.com/windows/servercore:ltsc2019 (windows 10.0.17763.2565)",
+		`C:\app\packages.lock.json`,
+	}, report.ScannedTargets)
+	require.Len(t, report.Vulnerabilities, 2)
+	assert.Equal(t, "CVE-2022-21907", report.Vulnerabilities[0].VulnerabilityID)
+	assert.Equal(t, []string{"KB5009557"}, report.Vulnerabilities[0].FixedVersions)
+	assert.Equal(t, "GHSA-5crp-9r3c-p9vr", report.Vulnerabilities[1].VulnerabilityID)
+	assert.Equal(t, `C:\app\packages.lock.json`, report.Vulnerabilities[1].Target)
+	assert.Equal(t, `C:\app\bin\Newtonsoft.Json.dll`, report.Vulnerabilities[1].PkgPath)
+	assert.Equal(t, 1, report.EcosystemSummary["windows"].CriticalCount)
+	assert.Equal(t, 1, report.EcosystemSummary["nuget"].HighCount)
+}
+
+func TestConverter_Convert_VendorSeverity(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+	report, err := converter.ConvertFile(config, "registry.access.redhat.com/ubi8/ubi:8.5", "testdata/ubi8-vendor-severity.json")
+	require.NoError(t, err)
+
+	require.Len(t, report.Vulnerabilities, 3)
+	critical := report.Vulnerabilities[0]
+	assert.Equal(t, "CVE-2022-23218", critical.VulnerabilityID)
+	assert.Equal(t, starboardv1alpha1.SeverityCritical, critical.Severity)
+	assert.Equal(t, map[string]string{"nvd": "CRITICAL", "redhat": "LOW", "ubuntu": "LOW"}, critical.VendorSeverity)
+	high := report.Vulnerabilities[1]
+	assert.Equal(t, "CVE-2021-3999", high.VulnerabilityID)
+	assert.Equal(t, starboardv1alpha1.SeverityHigh, high.Severity)
+	assert.Equal(t, map[string]string{"nvd": "HIGH", "redhat": "MEDIUM"}, high.VendorSeverity)
+	assert.Nil(t, report.Vulnerabilities[2].VendorSeverity)
+
+	assert.Equal(t, 1, report.Summary.CriticalCount)
+	assert.Equal(t, 1, report.Summary.HighCount)
+	assert.Equal(t, 1, report.Summary.MediumCount)
+	assert.Equal(t, 0, report.Summary.LowCount)
+
+	t.Run("Should write vendor severity as numbers", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, trivy.NewWriter(trivy.WithIndent("")).Write(report, &out))
+		assert.Contains(t, out.String(), `"VendorSeverity":{"nvd":3,"redhat":2}`)
+		reconverted, err := converter.Convert(config, "registry.access.redhat.com/ubi8/ubi:8.5", &out)
+		require.NoError(t, err)
+		assert.Equal(t, report.Vulnerabilities[0].VendorSeverity, reconverted.Vulnerabilities[0].VendorSeverity)
+		assert.Equal(t, report.Vulnerabilities[1].VendorSeverity, reconverted.Vulnerabilities[1].VendorSeverity)
+	})
+}
+
+func TestConverter_ConvertEach(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+	expected, err := converter.ConvertFile(config, "example.com/app:2.0", "testdata/app-2.0-ecosystems.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, expected.Vulnerabilities)
+
+	t.Run("Should pass each vulnerability to callback and return summary", func(t *testing.T) {
+		file, err := os.Open("testdata/app-2.0-ecosystems.json")
+		require.NoError(t, err)
+		defer func() {
+			_ = file.Close()
+		}()
+		var vulnerabilities []starboardv1alpha1.Vulnerability
+		summary, err := converter.ConvertEach(config, "example.com/app:2.0", file, func(v starboardv1alpha1.Vulnerability) error {
+			vulnerabilities = append(vulnerabilities, v)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Len(t, vulnerabilities, len(expected.Vulnerabilities))
+		assert.ElementsMatch(t, expected.Vulnerabilities, vulnerabilities)
+		assert.Equal(t, expected.Summary, summary)
+	})
+
+	t.Run("Should stop and return error of callback", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		_, err := converter.ConvertEach(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString), func(v starboardv1alpha1.Vulnerability) error {
+			calls++
+			return errStop
+		})
+		assert.Equal(t, errStop, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Should return error of malformed output", func(t *testing.T) {
+		calls := 0
+		_, err := converter.ConvertEach(config, "alpine:3.10.2", strings.NewReader(`[{"Target": "alpine:3.10.2", "Vulnerabilities": [`), func(v starboardv1alpha1.Vulnerability) error {
+			calls++
+			return nil
+		})
+		assert.True(t, errors.Is(err, trivy.ErrMalformedOutput))
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func TestConverter_Convert_PkgIdentifier(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should capture Maven coordinates of Java packages", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "example.com/java-app:1.0", "testdata/java-app-jar.json")
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 2)
+		assert.Equal(t, &starboardv1alpha1.PkgIdentifier{
+			PURL:       "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			GroupID:    "org.apache.logging.log4j",
+			ArtifactID: "log4j-core",
+			Version:    "2.14.1",
+		}, report.Vulnerabilities[0].PkgIdentifier)
+		assert.Equal(t, &starboardv1alpha1.PkgIdentifier{
+			PURL:       "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.3",
+			GroupID:    "com.fasterxml.jackson.core",
+			ArtifactID: "jackson-databind",
+			Version:    "2.13.3",
+		}, report.Vulnerabilities[1].PkgIdentifier)
+	})
+
+	t.Run("Should leave identifier of non-Java packages empty", func(t *testing.T) {
+		report, err := converter.ConvertFile(config, "example.com/app:2.0", "testdata/app-2.0-ecosystems.json")
+		require.NoError(t, err)
+		require.NotEmpty(t, report.Vulnerabilities)
+		for _, v := range report.Vulnerabilities {
+			assert.Nil(t, v.PkgIdentifier, v.VulnerabilityID)
+		}
+	})
+}
+
+func TestConverter_Convert_MinScore(t *testing.T) {
+	input := `[
+	{
+		"Target": "example.com/app:1.0 (debian 10.4)",
+		"Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2020-0001", "PkgName": "a", "InstalledVersion": "1", "Severity": "HIGH", "CVSS": {"nvd": {"V3Score": 6.9}}},
+			{"VulnerabilityID": "CVE-2020-0002", "PkgName": "b", "InstalledVersion": "1", "Severity": "HIGH", "CVSS": {"nvd": {"V3Score": 7.5}}},
+			{"VulnerabilityID": "CVE-2020-0003", "PkgName": "c", "InstalledVersion": "1", "Severity": "MEDIUM", "CVSS": {"nvd": {"V3Score": 7.2}}},
+			{"VulnerabilityID": "CVE-2020-0004", "PkgName": "d", "InstalledVersion": "1", "Severity": "CRITICAL"},
+			{"VulnerabilityID": "CVE-2020-0005", "PkgName": "e", "InstalledVersion": "1", "Severity": "LOW", "CVSS": {"nvd": {"V2Score": 3.1}}}
+		]
+	}
+]`
+
+	testCases := []struct {
+		name        string
+		configData  starboard.ConfigData
+		expectedIDs []string
+	}{
+		{
+			name:        "Should keep all vulnerabilities by default",
+			configData:  starboard.ConfigData{},
+			expectedIDs: []string{"CVE-2020-0004", "CVE-2020-0001", "CVE-2020-0002", "CVE-2020-0003", "CVE-2020-0005"},
+		},
+		{
+			name: "Should drop vulnerabilities below minimum score and keep unscored ones",
+			configData: starboard.ConfigData{
+				"trivy.minScore": "7.0",
+			},
+			expectedIDs: []string{"CVE-2020-0004", "CVE-2020-0002", "CVE-2020-0003"},
+		},
+		{
+			name: "Should drop unscored vulnerabilities when configured",
+			configData: starboard.ConfigData{
+				"trivy.minScore":     "7.0",
+				"trivy.dropUnscored": "true",
+			},
+			expectedIDs: []string{"CVE-2020-0002", "CVE-2020-0003"},
+		},
+		{
+			name: "Should ignore unscored policy when minimum score is not set",
+			configData: starboard.ConfigData{
+				"trivy.dropUnscored": "true",
+			},
+			expectedIDs: []string{"CVE-2020-0004", "CVE-2020-0001", "CVE-2020-0002", "CVE-2020-0003", "CVE-2020-0005"},
+		},
+		{
+			name: "Should combine minimum score with severity threshold",
+			configData: starboard.ConfigData{
+				"trivy.minScore":          "7.0",
+				"trivy.severityThreshold": "HIGH",
+			},
+			expectedIDs: []string{"CVE-2020-0004", "CVE-2020-0002"},
+		},
+		{
+			name: "Should combine minimum score with severity threshold and drop unscored vulnerabilities",
+			configData: starboard.ConfigData{
+				"trivy.minScore":          "7.0",
+				"trivy.severityThreshold": "HIGH",
+				"trivy.dropUnscored":      "true",
+			},
+			expectedIDs: []string{"CVE-2020-0002"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.configData["trivy.imageRef"] = "aquasec/trivy:0.9.1"
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(tc.configData, "example.com/app:1.0", strings.NewReader(input))
+			require.NoError(t, err)
+			ids := make([]string, 0)
+			for _, v := range report.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, len(tc.expectedIDs), report.Summary.Total())
+		})
+	}
+}
+
+func TestConverter_Convert_Limits(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef":          "aquasec/trivy:0.9.1",
+		"trivy.maxScanOutputSize": "1024",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	t.Run("Should convert output within size limit", func(t *testing.T) {
+		_, err := converter.Convert(starboard.ConfigData{
+			"trivy.imageRef":          "aquasec/trivy:0.9.1",
+			"trivy.maxScanOutputSize": strconv.Itoa(len(sampleReportAsString)),
+		}, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+	})
+
+	t.Run("Should stop reading oversized output", func(t *testing.T) {
+		reader := &countingReader{reader: io.MultiReader(
+			strings.NewReader(`[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [`),
+			strings.NewReader(strings.Repeat(`{"VulnerabilityID": "CVE-2019-1549", "PkgName": "openssl", "Severity": "MEDIUM"},`, 100000)),
+		)}
+		_, err := converter.Convert(config, "alpine:3.10.2", reader)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, trivy.ErrLimitExceeded))
+		var limitErr *trivy.LimitExceededError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, "size", limitErr.Limit)
+		assert.Equal(t, int64(1024), limitErr.Max)
+		assert.Less(t, reader.count, 64*1024)
+	})
+
+	t.Run("Should reject oversized output in memory", func(t *testing.T) {
+		_, err := converter.ConvertBytes(config, "alpine:3.10.2", []byte(strings.Repeat(" ", 1025)+sampleReportAsString))
+		assert.True(t, errors.Is(err, trivy.ErrLimitExceeded))
+	})
+
+	t.Run("Should reject deeply nested output", func(t *testing.T) {
+		bomb := `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [{"CVSS": ` + strings.Repeat("[", 100000)
+		_, err := trivy.NewConverter().Convert(starboard.ConfigData{
+			"trivy.imageRef": "aquasec/trivy:0.9.1",
+		}, "alpine:3.10.2", strings.NewReader(bomb))
+		require.Error(t, err)
+		assert.EqualError(t, err, "trivy scan output exceeds the maximum nesting depth of 100")
+		assert.True(t, errors.Is(err, trivy.ErrLimitExceeded))
+	})
+
+	t.Run("Should not count brackets in strings", func(t *testing.T) {
+		input := strings.Replace(sampleReportAsString, `"Title": "openssl: information disclosure in fork()"`,
+			`"Title": "`+strings.Repeat("[{", 200)+`"`, 1)
+		report, err := converter.Convert(starboard.ConfigData{
+			"trivy.imageRef": "aquasec/trivy:0.9.1",
+		}, "alpine:3.10.2", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("[{", 200), report.Vulnerabilities[0].Title)
+	})
+}
+
+func TestConverter_Convert_CategorizedLinks(t *testing.T) {
+	testCases := []struct {
+		name               string
+		references         string
+		expectedCategories *starboardv1alpha1.CategorizedLinks
+	}{
+		{
+			name: "Should categorize advisories",
+			references: `[
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-3449",
+				"https://access.redhat.com/security/cve/CVE-2021-3449",
+				"https://bugzilla.redhat.com/show_bug.cgi?id=1941554",
+				"https://github.com/advisories/GHSA-35jh-r3h4-6jhm"
+			]`,
+			expectedCategories: &starboardv1alpha1.CategorizedLinks{
+				Advisory: []string{
+					"https://nvd.nist.gov/vuln/detail/CVE-2021-3449",
+					"https://access.redhat.com/security/cve/CVE-2021-3449",
+					"https://bugzilla.redhat.com/show_bug.cgi?id=1941554",
+					"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+				},
+			},
+		},
+		{
+			name: "Should categorize patches",
+			references: `[
+				"https://github.com/openssl/openssl/commit/fb9fa6b51defd48157eeb207f52181f735d96148",
+				"https://gitlab.com/gnutls/gnutls/-/commit/29ee67c205855e848a0a26e6d0e4f65b6b943e0a"
+			]`,
+			expectedCategories: &starboardv1alpha1.CategorizedLinks{
+				Patch: []string{
+					"https://github.com/openssl/openssl/commit/fb9fa6b51defd48157eeb207f52181f735d96148",
+					"https://gitlab.com/gnutls/gnutls/-/commit/29ee67c205855e848a0a26e6d0e4f65b6b943e0a",
+				},
+			},
+		},
+		{
+			name: "Should categorize exploits",
+			references: `[
+				"https://www.exploit-db.com/exploits/49743",
+				"http://packetstormsecurity.com/files/161442/OpenSSL-DoS.html"
+			]`,
+			expectedCategories: &starboardv1alpha1.CategorizedLinks{
+				Exploit: []string{
+					"https://www.exploit-db.com/exploits/49743",
+					"http://packetstormsecurity.com/files/161442/OpenSSL-DoS.html",
+				},
+			},
+		},
+		{
+			name: "Should fall back to other links",
+			references: `[
+				"https://github.com/openssl/openssl/issues/14683",
+				"https://www.openssl.org/news/secadv/20210325.txt",
+				"not a link"
+			]`,
+			expectedCategories: &starboardv1alpha1.CategorizedLinks{
+				Other: []string{
+					"https://github.com/openssl/openssl/issues/14683",
+					"https://www.openssl.org/news/secadv/20210325.txt",
+					"not a link",
+				},
+			},
+		},
+		{
+			name:               "Should omit categorized links without references",
+			references:         `[]`,
+			expectedCategories: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := `[{"Target": "alpine:3.13.2 (alpine 3.13.2)", "Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2021-3449", "PkgName": "libssl1.1", "InstalledVersion": "1.1.1j-r0", "Severity": "MEDIUM", "References": ` + tc.references + `}
+			]}]`
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			}, "alpine:3.13.2", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.Equal(t, tc.expectedCategories, report.Vulnerabilities[0].CategorizedLinks)
+		})
+	}
+
+	t.Run("Should categorize links within the limit", func(t *testing.T) {
+		input := `[{"Target": "alpine:3.13.2 (alpine 3.13.2)", "Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2021-3449", "PkgName": "libssl1.1", "InstalledVersion": "1.1.1j-r0", "Severity": "MEDIUM", "References": [
+				"https://www.exploit-db.com/exploits/49743",
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-3449"
+			]}
+		]}]`
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(starboard.ConfigData{
+			"trivy.imageRef": "aquasec/trivy:0.9.1",
+			"trivy.maxLinks": "1",
+		}, "alpine:3.13.2", strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 1)
+		assert.Equal(t, []string{"https://nvd.nist.gov/vuln/detail/CVE-2021-3449"}, report.Vulnerabilities[0].Links)
+		assert.Equal(t, &starboardv1alpha1.CategorizedLinks{
+			Advisory: []string{"https://nvd.nist.gov/vuln/detail/CVE-2021-3449"},
+		}, report.Vulnerabilities[0].CategorizedLinks)
+	})
+
+	t.Run("Should omit categorized links when links are excluded", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(starboard.ConfigData{
+			"trivy.imageRef":      "aquasec/trivy:0.9.1",
+			"trivy.includeFields": "title",
+		}, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		for _, v := range report.Vulnerabilities {
+			assert.Nil(t, v.CategorizedLinks)
+		}
+	})
+}
+
+func TestConverter_Convert_FilterStats(t *testing.T) {
+	input := `[
+	{
+		"Target": "example.com/app:1.0 (debian 10.4)",
+		"Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2020-0001", "PkgName": "a", "InstalledVersion": "1", "FixedVersion": "2", "Severity": "HIGH", "CVSS": {"nvd": {"V3Score": 7.5}}},
+			{"VulnerabilityID": "CVE-2020-0001", "PkgName": "a", "InstalledVersion": "1", "FixedVersion": "2", "Severity": "HIGH", "CVSS": {"nvd": {"V3Score": 7.5}}},
+			{"VulnerabilityID": "CVE-2020-0002", "PkgName": "b", "InstalledVersion": "1", "FixedVersion": "2", "Severity": "LOW", "CVSS": {"nvd": {"V3Score": 3.1}}},
+			{"VulnerabilityID": "CVE-2020-0003", "PkgName": "c", "InstalledVersion": "1", "Severity": "CRITICAL", "CVSS": {"nvd": {"V3Score": 9.8}}},
+			{"VulnerabilityID": "CVE-2020-0004", "PkgName": "d", "InstalledVersion": "1", "FixedVersion": "2", "Severity": "HIGH", "CVSS": {"nvd": {"V3Score": 7.2}}},
+			{"VulnerabilityID": "CVE-2020-0005", "PkgName": "e", "InstalledVersion": "1", "FixedVersion": "2", "Severity": "MEDIUM", "CVSS": {"nvd": {"V3Score": 6.1}}}
+		]
+	}
+]`
+
+	testCases := []struct {
+		name          string
+		configData    starboard.ConfigData
+		expectedStats *starboardv1alpha1.FilterStats
+	}{
+		{
+			name:       "Should count deduplicated vulnerabilities",
+			configData: starboard.ConfigData{},
+			expectedStats: &starboardv1alpha1.FilterStats{
+				Deduplicated: 1,
+			},
+		},
+		{
+			name: "Should count vulnerabilities below severity threshold",
+			configData: starboard.ConfigData{
+				"trivy.severityThreshold": "HIGH",
+			},
+			expectedStats: &starboardv1alpha1.FilterStats{
+				BelowThreshold: 2,
+				Deduplicated:   1,
+			},
+		},
+		{
+			name: "Should count vulnerabilities below minimum score",
+			configData: starboard.ConfigData{
+				"trivy.minScore": "7.0",
+			},
+			expectedStats: &starboardv1alpha1.FilterStats{
+				BelowMinScore: 2,
+				Deduplicated:  1,
+			},
+		},
+		{
+			name: "Should count ignored vulnerabilities",
+			configData: starboard.ConfigData{
+				"trivy.ignorePolicy": "CVE-2020-0004\nCVE-2020-0005 exp:2020-01-01",
+			},
+			expectedStats: &starboardv1alpha1.FilterStats{
+				Ignored:      1,
+				Deduplicated: 1,
+			},
+		},
+		{
+			name: "Should count unfixed vulnerabilities",
+			configData: starboard.ConfigData{
+				"trivy.ignoreUnfixed": "true",
+			},
+			expectedStats: &starboardv1alpha1.FilterStats{
+				Unfixed:      1,
+				Deduplicated: 1,
+			},
+		},
+		{
+			name: "Should count each vulnerability under the first filter that omits it",
+			configData: starboard.ConfigData{
+				"trivy.severityThreshold": "HIGH",
+				"trivy.minScore":          "7.5",
+				"trivy.ignoreUnfixed":     "true",
+				"trivy.ignorePolicy":      "CVE-2020-0002",
+			},
+			expectedStats: &starboardv1alpha1.FilterStats{
+				BelowThreshold: 1,
+				BelowMinScore:  1,
+				Ignored:        1,
+				Unfixed:        1,
+				Deduplicated:   1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.configData["trivy.imageRef"] = "aquasec/trivy:0.9.1"
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(tc.configData, "example.com/app:1.0", strings.NewReader(input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStats, report.FilterStats)
+			assert.Equal(t, 6, tc.expectedStats.Total()+report.Summary.Total())
+			assert.NoError(t, report.Validate())
+		})
+	}
+
+	t.Run("Should omit stats if no vulnerability was filtered", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(starboard.ConfigData{
+			"trivy.imageRef": "aquasec/trivy:0.9.1",
+		}, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Nil(t, report.FilterStats)
+	})
+}
+
+func TestConverter_Convert_CleanScan(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Should serialize clean scan of OS packages",
+			input: `[{"Target": "alpine:3.12.0 (alpine 3.12.0)", "Type": "alpine", "Vulnerabilities": null}]`,
+		},
+		{
+			name:  "Should serialize clean scan without targets",
+			input: `null`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			}, "alpine:3.12.0", strings.NewReader(tc.input))
+			require.NoError(t, err)
+			assert.False(t, report.HasVulnerabilities())
+			assert.NotNil(t, report.Vulnerabilities)
+
+			data, err := json.Marshal(report)
+			require.NoError(t, err)
+			var serialized map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &serialized))
+			assert.Equal(t, []interface{}{}, serialized["vulnerabilities"])
+			require.Contains(t, serialized, "summary")
+			summary := serialized["summary"].(map[string]interface{})
+			for _, count := range []string{"criticalCount", "highCount", "mediumCount", "lowCount", "noneCount", "unknownCount"} {
+				assert.Equal(t, float64(0), summary[count], count)
+			}
+		})
+	}
+}
+
+func TestConverter_Convert_SeverityOverrides(t *testing.T) {
+	testCases := []struct {
+		name               string
+		configData         starboard.ConfigData
+		expectedSeverities map[string]starboardv1alpha1.Severity
+		expectedSummary    starboardv1alpha1.VulnerabilitySummary
+	}{
+		{
+			name: "Should override severity of packages matching glob",
+			configData: starboard.ConfigData{
+				"trivy.severityOverrides": "HIGH pkg:openssl*",
+			},
+			expectedSeverities: map[string]starboardv1alpha1.Severity{
+				"CVE-2019-1549": starboardv1alpha1.SeverityHigh,
+				"CVE-2019-1547": starboardv1alpha1.SeverityHigh,
+			},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				HighCount: 2,
+				RiskScore: 10,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					HighCount: 2,
+				},
+			},
+		},
+		{
+			name: "Should override severity of vulnerability",
+			configData: starboard.ConfigData{
+				"trivy.severityOverrides": "CRITICAL id:CVE-2019-1547",
+			},
+			expectedSeverities: map[string]starboardv1alpha1.Severity{
+				"CVE-2019-1549": starboardv1alpha1.SeverityMedium,
+				"CVE-2019-1547": starboardv1alpha1.SeverityCritical,
+			},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				MediumCount:   1,
+				RiskScore:     12,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					CriticalCount: 1,
+					MediumCount:   1,
+				},
+			},
+		},
+		{
+			name: "Should apply first matching rule",
+			configData: starboard.ConfigData{
+				"trivy.severityOverrides": "LOW pkg:openssl id:CVE-2019-1549\nHIGH pkg:openssl*\nCRITICAL id:CVE-2019-1547",
+			},
+			expectedSeverities: map[string]starboardv1alpha1.Severity{
+				"CVE-2019-1549": starboardv1alpha1.SeverityLow,
+				"CVE-2019-1547": starboardv1alpha1.SeverityHigh,
+			},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				HighCount: 1,
+				LowCount:  1,
+				RiskScore: 6,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					HighCount: 1,
+					LowCount:  1,
+				},
+			},
+		},
+		{
+			name: "Should compare overridden severity with threshold",
+			configData: starboard.ConfigData{
+				"trivy.severityOverrides": "HIGH id:CVE-2019-1547",
+				"trivy.severityThreshold": "HIGH",
+			},
+			expectedSeverities: map[string]starboardv1alpha1.Severity{
+				"CVE-2019-1547": starboardv1alpha1.SeverityHigh,
+			},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				HighCount: 1,
+				RiskScore: 5,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					HighCount: 1,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.configData["trivy.imageRef"] = "aquasec/trivy:0.9.1"
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(tc.configData, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+			require.NoError(t, err)
+			severities := make(map[string]starboardv1alpha1.Severity)
+			for _, v := range report.Vulnerabilities {
+				severities[v.VulnerabilityID] = v.Severity
+			}
+			assert.Equal(t, tc.expectedSeverities, severities)
+			assert.Equal(t, tc.expectedSummary, report.Summary)
+		})
+	}
+}
+
+func TestConverter_Convert_ImageType(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	testCases := []struct {
+		name              string
+		path              string
+		expectedImageType starboardv1alpha1.ImageType
+		expectedCount     int
+	}{
+		{
+			name:              "Should classify image with OS packages as full",
+			path:              "testdata/app-1.0-multi-target.json",
+			expectedImageType: starboardv1alpha1.ImageTypeFull,
+			expectedCount:     2,
+		},
+		{
+			name:              "Should classify image as full by OS target of legacy format",
+			path:              "testdata/alpine-3.10.2.json",
+			expectedImageType: starboardv1alpha1.ImageTypeFull,
+			expectedCount:     2,
+		},
+		{
+			name:              "Should classify image with language packages only as distroless",
+			path:              "testdata/distroless-app.json",
+			expectedImageType: starboardv1alpha1.ImageTypeDistroless,
+			expectedCount:     1,
+		},
+		{
+			name:              "Should classify image without targets as scratch",
+			path:              "testdata/scratch-app.json",
+			expectedImageType: starboardv1alpha1.ImageTypeScratch,
+		},
+		{
+			name:          "Should not classify artifact other than image",
+			path:          "testdata/fs-app.json",
+			expectedCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := newVerboseTestLogger(1)
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock), trivy.WithLogger(logger)).ConvertFile(config, "example.com/app:1.0", tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedImageType, report.ImageType)
+			assert.Len(t, report.Vulnerabilities, tc.expectedCount)
+			assert.Empty(t, report.Warnings)
+			assert.Contains(t, *logger.messages, fmt.Sprint("Classified image", "imageType", tc.expectedImageType))
+		})
+	}
+
+	t.Run("Should classify image with secrets only as scratch", func(t *testing.T) {
+		input := `{
+	"ArtifactType": "container_image",
+	"Results": [
+		{
+			"Target": "app/.env",
+			"Class": "secret",
+			"Secrets": [{"RuleID": "aws-access-key-id", "Severity": "CRITICAL", "StartLine": 1, "EndLine": 1}]
+		}
+	]
+}`
+		report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(config, "example.com/app:1.0", strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, starboardv1alpha1.ImageTypeScratch, report.ImageType)
+		assert.Len(t, report.Secrets, 1)
+	})
+}
+
+func TestConverter_Convert_DuplicateReferences(t *testing.T) {
+	input := `[
+	{
+		"Target": "app/package-lock.json",
+		"Type": "npm",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2021-23337",
+			"PkgName": "lodash",
+			"InstalledVersion": "4.17.15",
+			"FixedVersion": "4.17.21",
+			"Severity": "HIGH",
+			"References": [
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-23337",
+				"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-23337"
+			]
+		}
+		]
+	},
+	{
+		"Target": "app/node_modules/lodash/package.json",
+		"Type": "node-pkg",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2021-23337",
+			"PkgName": "lodash",
+			"InstalledVersion": "4.17.15",
+			"FixedVersion": "4.17.21",
+			"Severity": "HIGH",
+			"References": [
+				"https://github.com/lodash/lodash/commit/3469357cff396a26c363f8c1b5a91dde28ba4b1c",
+				"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+				"https://github.com/advisories/GHSA-29mw-wpgm-hmr9"
+			]
+		}
+		]
+	}
+]`
+
+	testCases := []struct {
+		name                     string
+		configData               starboard.ConfigData
+		expectedLinks            []string
+		expectedAliases          []string
+		expectedCategorizedLinks *starboardv1alpha1.CategorizedLinks
+	}{
+		{
+			name:       "Should merge references of duplicates",
+			configData: starboard.ConfigData{},
+			expectedLinks: []string{
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-23337",
+				"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+				"https://github.com/lodash/lodash/commit/3469357cff396a26c363f8c1b5a91dde28ba4b1c",
+				"https://github.com/advisories/GHSA-29mw-wpgm-hmr9",
+			},
+			expectedAliases: []string{"GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"},
+			expectedCategorizedLinks: &starboardv1alpha1.CategorizedLinks{
+				Advisory: []string{
+					"https://nvd.nist.gov/vuln/detail/CVE-2021-23337",
+					"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+					"https://github.com/advisories/GHSA-29mw-wpgm-hmr9",
+				},
+				Patch: []string{"https://github.com/lodash/lodash/commit/3469357cff396a26c363f8c1b5a91dde28ba4b1c"},
+			},
+		},
+		{
+			name: "Should limit merged references",
+			configData: starboard.ConfigData{
+				"trivy.maxLinks": "2",
+			},
+			expectedLinks: []string{
+				"https://nvd.nist.gov/vuln/detail/CVE-2021-23337",
+				"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+			},
+			expectedAliases: []string{"GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"},
+			expectedCategorizedLinks: &starboardv1alpha1.CategorizedLinks{
+				Advisory: []string{
+					"https://nvd.nist.gov/vuln/detail/CVE-2021-23337",
+					"https://github.com/advisories/GHSA-35jh-r3h4-6jhm",
+				},
+			},
+		},
+		{
+			name: "Should not merge excluded fields",
+			configData: starboard.ConfigData{
+				"trivy.includeFields": "title",
+			},
+			expectedLinks:   []string{},
+			expectedAliases: []string{"GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.configData["trivy.imageRef"] = "aquasec/trivy:0.9.1"
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(tc.configData, "example.com/node-app:1.0", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			v := report.Vulnerabilities[0]
+			assert.Equal(t, "app/package-lock.json", v.Target)
+			assert.Equal(t, tc.expectedLinks, v.Links)
+			assert.Equal(t, tc.expectedAliases, v.Aliases)
+			assert.Equal(t, tc.expectedCategorizedLinks, v.CategorizedLinks)
+			assert.Equal(t, &starboardv1alpha1.FilterStats{Deduplicated: 1}, report.FilterStats)
+		})
+	}
+
+	t.Run("Should not merge references of duplicates when converting each vulnerability", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.imageRef": "aquasec/trivy:0.9.1"}
+		var vulnerabilities []starboardv1alpha1.Vulnerability
+		summary, err := trivy.NewConverter().ConvertEach(config, "example.com/node-app:1.0", strings.NewReader(input), func(v starboardv1alpha1.Vulnerability) error {
+			vulnerabilities = append(vulnerabilities, v)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Total())
+		require.Len(t, vulnerabilities, 1)
+		assert.Equal(t, "app/package-lock.json", vulnerabilities[0].Target)
+		assert.NotContains(t, vulnerabilities[0].Links, "https://github.com/lodash/lodash/commit/3469357cff396a26c363f8c1b5a91dde28ba4b1c")
+		assert.Equal(t, []string{"GHSA-35jh-r3h4-6jhm"}, vulnerabilities[0].Aliases)
+	})
+}
+
+type closeRecordingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeRecordingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestConverter_ConvertResponse(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	get := func(t *testing.T, handler http.HandlerFunc) *http.Response {
+		t.Helper()
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Should convert body of successful response", func(t *testing.T) {
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = io.WriteString(w, sampleReportAsString)
+		})
+		result, err := converter.ConvertResponse(config, "alpine:3.10.2", resp)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, result)
+	})
+
+	t.Run("Should convert chunked body of successful response", func(t *testing.T) {
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			half := len(sampleReportAsString) / 2
+			_, _ = io.WriteString(w, sampleReportAsString[:half])
+			w.(http.Flusher).Flush()
+			_, _ = io.WriteString(w, sampleReportAsString[half:])
+		})
+		assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+		result, err := converter.ConvertResponse(config, "alpine:3.10.2", resp)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, result)
+	})
+
+	t.Run("Should convert gzip-compressed body of successful response", func(t *testing.T) {
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/gzip")
+			gzipWriter := gzip.NewWriter(w)
+			_, _ = io.WriteString(gzipWriter, sampleReportAsString)
+			_ = gzipWriter.Close()
+		})
+		result, err := converter.ConvertResponse(config, "alpine:3.10.2", resp)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, result)
+	})
+
+	t.Run("Should return error with body snippet when status is not successful", func(t *testing.T) {
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "failed to scan image: "+strings.Repeat("x", 1024), http.StatusInternalServerError)
+		})
+		_, err := converter.ConvertResponse(config, "alpine:3.10.2", resp)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, trivy.ErrUnexpectedStatus))
+		var statusErr *trivy.ResponseStatusError
+		require.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+		assert.Equal(t, "500 Internal Server Error", statusErr.Status)
+		assert.Len(t, statusErr.Body, 512)
+		assert.True(t, strings.HasPrefix(statusErr.Body, "failed to scan image: xxx"))
+		assert.True(t, strings.HasPrefix(err.Error(), "unexpected status of trivy server response: 500 Internal Server Error: failed to scan image: xxx"))
+	})
+
+	t.Run("Should return error when body is truncated", func(t *testing.T) {
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", strconv.Itoa(len(sampleReportAsString)))
+			_, _ = io.WriteString(w, sampleReportAsString[:len(sampleReportAsString)/2])
+		})
+		_, err := converter.ConvertResponse(config, "alpine:3.10.2", resp)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+		assert.True(t, strings.HasPrefix(err.Error(), "converting trivy server response: "))
+	})
+
+	t.Run("Should return error when content type is not supported", func(t *testing.T) {
+		resp := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = io.WriteString(w, "<html><body>Bad Gateway</body></html>")
+		})
+		_, err := converter.ConvertResponse(config, "alpine:3.10.2", resp)
+		assert.EqualError(t, err, "unsupported content type of trivy server response: text/html")
+	})
+
+	t.Run("Should close body", func(t *testing.T) {
+		for _, statusCode := range []int{http.StatusOK, http.StatusBadGateway} {
+			body := &closeRecordingBody{Reader: strings.NewReader(sampleReportAsString)}
+			_, _ = converter.ConvertResponse(config, "alpine:3.10.2", &http.Response{
+				StatusCode: statusCode,
+				Header:     http.Header{},
+				Body:       body,
+			})
+			assert.True(t, body.closed, "status code %d", statusCode)
+		}
+	})
+}
+
+func TestConverter_Convert_PackagePatterns(t *testing.T) {
+	input := `[
+	{
+		"Target": "alpine:3.10.2 (alpine 3.10.2)",
+		"Type": "alpine",
+		"Vulnerabilities": [
+		{"VulnerabilityID": "CVE-2019-14697", "PkgName": "musl", "InstalledVersion": "1.1.22-r2", "FixedVersion": "1.1.22-r3", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2019-14697", "PkgName": "musl-utils", "InstalledVersion": "1.1.22-r2", "FixedVersion": "1.1.22-r3", "Severity": "CRITICAL"},
+		{"VulnerabilityID": "CVE-2019-1549", "PkgName": "openssl", "InstalledVersion": "1.1.1c-r0", "FixedVersion": "1.1.1d-r0", "Severity": "MEDIUM"},
+		{"VulnerabilityID": "CVE-2021-42378", "PkgName": "busybox", "InstalledVersion": "1.30.1-r2", "FixedVersion": "1.30.1-r5", "Severity": "HIGH"}
+		]
+	}
+]`
+
+	testCases := []struct {
+		name                string
+		configData          starboard.ConfigData
+		expectedPackages    []string
+		expectedSummary     starboardv1alpha1.VulnerabilitySummary
+		expectedFilterStats *starboardv1alpha1.FilterStats
+	}{
+		{
+			name:             "Should keep vulnerabilities of all packages by default",
+			configData:       starboard.ConfigData{},
+			expectedPackages: []string{"musl", "musl-utils", "busybox", "openssl"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 2,
+				HighCount:     1,
+				MediumCount:   1,
+				RiskScore:     27,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					CriticalCount: 2,
+					HighCount:     1,
+					MediumCount:   1,
+				},
+			},
+		},
+		{
+			name: "Should keep vulnerabilities of included packages only",
+			configData: starboard.ConfigData{
+				"trivy.includePackages": "musl*,openssl",
+			},
+			expectedPackages: []string{"musl", "musl-utils", "openssl"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 2,
+				MediumCount:   1,
+				RiskScore:     22,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					CriticalCount: 2,
+					MediumCount:   1,
+				},
+			},
+			expectedFilterStats: &starboardv1alpha1.FilterStats{ExcludedPackages: 1},
+		},
+		{
+			name: "Should omit vulnerabilities of excluded packages",
+			configData: starboard.ConfigData{
+				"trivy.excludePackages": "busybox",
+			},
+			expectedPackages: []string{"musl", "musl-utils", "openssl"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 2,
+				MediumCount:   1,
+				RiskScore:     22,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					CriticalCount: 2,
+					MediumCount:   1,
+				},
+			},
+			expectedFilterStats: &starboardv1alpha1.FilterStats{ExcludedPackages: 1},
+		},
+		{
+			name: "Should omit vulnerabilities of excluded packages even if they're included",
+			configData: starboard.ConfigData{
+				"trivy.includePackages": "musl*,busybox",
+				"trivy.excludePackages": "*-utils",
+			},
+			expectedPackages: []string{"musl", "busybox"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				HighCount:     1,
+				RiskScore:     15,
+				Fixable: &starboardv1alpha1.FixableSummary{
+					CriticalCount: 1,
+					HighCount:     1,
+				},
+			},
+			expectedFilterStats: &starboardv1alpha1.FilterStats{ExcludedPackages: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.configData["trivy.imageRef"] = "aquasec/trivy:0.9.1"
+			report, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(tc.configData, "alpine:3.10.2", strings.NewReader(input))
+			require.NoError(t, err)
+			var packages []string
+			for _, v := range report.Vulnerabilities {
+				packages = append(packages, v.Resource)
+			}
+			assert.Equal(t, tc.expectedPackages, packages)
+			assert.Equal(t, tc.expectedSummary, report.Summary)
+			assert.Equal(t, tc.expectedFilterStats, report.FilterStats)
+		})
+	}
+}
+
+func TestConverter_Convert_Errors(t *testing.T) {
+	errVersionNotSet := errors.New("TRIVY_VERSION is not set")
+	failingResolver := trivy.WithVersionResolver(func(_ trivy.Config) (string, error) {
+		return "", errVersionNotSet
+	})
+
+	testCases := []struct {
+		name          string
+		options       []trivy.Option
+		imageRef      string
+		input         string
+		expectedError error
+		assertCause   func(t *testing.T, err error)
+	}{
+		{
+			name:          "Should return malformed output error of invalid JSON",
+			imageRef:      "alpine:3.10.2",
+			input:         `[{"Target": }]`,
+			expectedError: trivy.ErrMalformedOutput,
+			assertCause: func(t *testing.T, err error) {
+				var syntaxErr *json.SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+			},
+		},
+		{
+			name:          "Should return malformed output error of truncated JSON",
+			imageRef:      "alpine:3.10.2",
+			input:         sampleReportAsString[:100],
+			expectedError: trivy.ErrMalformedOutput,
+			assertCause: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+			},
+		},
+		{
+			name:          "Should return malformed output error of unexpected top-level value",
+			imageRef:      "alpine:3.10.2",
+			input:         `"report"`,
+			expectedError: trivy.ErrMalformedOutput,
+			assertCause: func(t *testing.T, err error) {
+				var malformedErr *trivy.MalformedOutputError
+				require.True(t, errors.As(err, &malformedErr))
+				assert.EqualError(t, malformedErr.Err, "expected JSON array or object of scan reports but got: report")
+			},
+		},
+		{
+			name:          "Should return empty scan output error",
+			imageRef:      "alpine:3.10.2",
+			input:         " \n",
+			expectedError: trivy.ErrEmptyScanOutput,
+		},
+		{
+			name:          "Should return invalid image reference error",
+			options:       []trivy.Option{trivy.WithRejectInvalidImageRefs()},
+			imageRef:      "alpine@latest",
+			input:         sampleReportAsString,
+			expectedError: trivy.ErrInvalidImageRef,
+			assertCause: func(t *testing.T, err error) {
+				var refErr *trivy.InvalidImageRefError
+				require.True(t, errors.As(err, &refErr))
+				assert.Equal(t, "alpine@latest", refErr.ImageRef)
+				assert.EqualError(t, err, `parsing image reference "alpine@latest": malformed digest "latest": expected algorithm:hex`)
+			},
+		},
+		{
+			name:          "Should return version resolution error",
+			options:       []trivy.Option{failingResolver, trivy.WithRejectUnknownVersions()},
+			imageRef:      "alpine:3.10.2",
+			input:         sampleReportAsString,
+			expectedError: trivy.ErrVersionResolution,
+			assertCause: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, errVersionNotSet))
+				assert.EqualError(t, err, "resolving scanner version: TRIVY_VERSION is not set")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{"trivy.imageRef": "aquasec/trivy:0.9.1"}
+			_, err := trivy.NewConverter(tc.options...).Convert(config, tc.imageRef, strings.NewReader(tc.input))
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "unexpected error: %v", err)
+			if tc.assertCause != nil {
+				tc.assertCause(t, err)
+			}
+		})
+	}
+
+	t.Run("Should report warning instead of invalid image reference error by default", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.imageRef": "aquasec/trivy:0.9.1"}
+		report, err := trivy.NewConverter().Convert(config, "alpine@latest", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.Equal(t, "alpine@latest", report.Artifact.Repository)
+		assert.Equal(t, []string{`parsing image reference "alpine@latest": malformed digest "latest": expected algorithm:hex`}, report.Warnings)
+	})
+
+	t.Run("Should return version resolution error when stamping vulnerabilities", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.imageRef": "aquasec/trivy:0.9.1"}
+		converter := trivy.NewConverter(failingResolver, trivy.WithScannerVersionStamp(), trivy.WithRejectUnknownVersions())
+		_, err := converter.ConvertEach(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString), func(v starboardv1alpha1.Vulnerability) error {
+			return nil
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, trivy.ErrVersionResolution))
+		assert.True(t, errors.Is(err, errVersionNotSet))
+	})
+}
+
+func TestConverter_Convert_Status(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		status         string
+		expectedStatus starboardv1alpha1.VulnerabilityStatus
+	}{
+		{status: "", expectedStatus: starboardv1alpha1.VulnerabilityStatusAffected},
+		{status: "unknown", expectedStatus: starboardv1alpha1.VulnerabilityStatusUnknown},
+		{status: "not_affected", expectedStatus: starboardv1alpha1.VulnerabilityStatusNotAffected},
+		{status: "affected", expectedStatus: starboardv1alpha1.VulnerabilityStatusAffected},
+		{status: "fixed", expectedStatus: starboardv1alpha1.VulnerabilityStatusFixed},
+		{status: "under_investigation", expectedStatus: starboardv1alpha1.VulnerabilityStatusUnderInvestigation},
+		{status: "will_not_fix", expectedStatus: starboardv1alpha1.VulnerabilityStatusWillNotFix},
+		{status: "fix_deferred", expectedStatus: starboardv1alpha1.VulnerabilityStatusFixDeferred},
+		{status: "end_of_life", expectedStatus: starboardv1alpha1.VulnerabilityStatusEndOfLife},
+		{status: "Will_Not_Fix", expectedStatus: starboardv1alpha1.VulnerabilityStatusWillNotFix},
+		{status: "wontfix", expectedStatus: starboardv1alpha1.VulnerabilityStatusUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Should map status %q to %s", tc.status, tc.expectedStatus), func(t *testing.T) {
+			input := fmt.Sprintf(`[{"Target": "debian 10.4", "Vulnerabilities": [{
+				"VulnerabilityID": "CVE-2019-18276",
+				"PkgName": "bash",
+				"InstalledVersion": "5.0-4",
+				"Severity": "LOW",
+				"Status": %q
+			}]}]`, tc.status)
+			report, err := trivy.NewConverter().Convert(config, "nginx:1.16", strings.NewReader(input))
+			require.NoError(t, err)
+			require.Len(t, report.Vulnerabilities, 1)
+			assert.Equal(t, tc.expectedStatus, report.Vulnerabilities[0].Status)
+		})
+	}
+}
+
+func TestConverter_Convert_IgnoreUnfixed(t *testing.T) {
+	input := `[{"Target": "nginx:1.16 (debian 10.4)", "Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-18276",
+			"PkgName": "bash",
+			"InstalledVersion": "5.0-4",
+			"Severity": "LOW"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"FixedVersion": "1.1.1d-r0",
+			"Severity": "MEDIUM"
+		},
+		{
+			"VulnerabilityID": "CVE-2020-9546",
+			"PkgName": "com.fasterxml.jackson.core:jackson-databind",
+			"InstalledVersion": "2.9.10.3",
+			"FixedVersion": " ",
+			"Severity": "CRITICAL"
+		}
+	]}]`
+
+	testCases := []struct {
+		name            string
+		ignoreUnfixed   string
+		expectedIDs     []string
+		expectedSummary starboardv1alpha1.VulnerabilitySummary
+	}{
+		{
+			name:        "Should keep unfixed vulnerabilities by default",
+			expectedIDs: []string{"CVE-2020-9546", "CVE-2019-1549", "CVE-2019-18276"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				MediumCount:   1,
+				LowCount:      1,
+				RiskScore:     13,
+				Fixable:       &starboardv1alpha1.FixableSummary{MediumCount: 1},
+			},
+		},
+		{
+			name:          "Should keep unfixed vulnerabilities when disabled",
+			ignoreUnfixed: "false",
+			expectedIDs:   []string{"CVE-2020-9546", "CVE-2019-1549", "CVE-2019-18276"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				MediumCount:   1,
+				LowCount:      1,
+				RiskScore:     13,
+				Fixable:       &starboardv1alpha1.FixableSummary{MediumCount: 1},
+			},
+		},
+		{
+			name:          "Should skip unfixed vulnerabilities when enabled",
+			ignoreUnfixed: "true",
+			expectedIDs:   []string{"CVE-2019-1549"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				RiskScore:   2,
+				Fixable:     &starboardv1alpha1.FixableSummary{MediumCount: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := starboard.ConfigData{
+				"trivy.imageRef": "aquasec/trivy:0.9.1",
+			}
+			if tc.ignoreUnfixed != "" {
+				config["trivy.ignoreUnfixed"] = tc.ignoreUnfixed
+			}
+			report, err := trivy.NewConverter().Convert(config, "nginx:1.16", strings.NewReader(input))
+			require.NoError(t, err)
+			var ids []string
+			for _, v := range report.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedSummary, report.Summary)
+		})
+	}
+}
+
+func TestConverter_Convert_DataSource(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "MEDIUM",
+			"DataSource": {
+				"ID": "alpine",
+				"Name": "Alpine Secdb",
+				"URL": "https://secdb.alpinelinux.org/"
+			}
+		},
+		{
+			"VulnerabilityID": "CVE-2019-1547",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "LOW"
+		}
+	]}]`
+
+	report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, report.Vulnerabilities, 2)
+
+	t.Run("Should populate data source", func(t *testing.T) {
+		assert.Equal(t, starboardv1alpha1.DataSource{
+			ID:   "alpine",
+			Name: "Alpine Secdb",
+			URL:  "https://secdb.alpinelinux.org/",
+		}, report.Vulnerabilities[0].DataSource)
+	})
+
+	t.Run("Should leave data source zero-valued when it's absent", func(t *testing.T) {
+		assert.Equal(t, "CVE-2019-1547", report.Vulnerabilities[1].VulnerabilityID)
+		assert.Equal(t, starboardv1alpha1.DataSource{}, report.Vulnerabilities[1].DataSource)
+	})
+}
+
+func TestConverter_Convert_EmptyOutput(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Should return error when output is empty",
+			input: "",
+		},
+		{
+			name:  "Should return error when output is whitespace only",
+			input: " \n\t\r\n  ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader(tc.input))
+			assert.True(t, errors.Is(err, trivy.ErrEmptyScanOutput), "expected ErrEmptyScanOutput but got: %v", err)
+
+			_, err = trivy.NewConverter().ConvertBytes(config, "alpine:3.10.2", []byte(tc.input))
+			assert.True(t, errors.Is(err, trivy.ErrEmptyScanOutput), "expected ErrEmptyScanOutput but got: %v", err)
+		})
+	}
+
+	t.Run("Should not return error when output is the null literal", func(t *testing.T) {
+		report, err := trivy.NewConverter().Convert(config, "alpine:3.10.2", strings.NewReader("null"))
+		require.NoError(t, err)
+		assert.Empty(t, report.Vulnerabilities)
+	})
+}
+
+func TestConverter_Convert_SeverityClassifier(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef":          "aquasec/trivy:0.9.1",
+		"trivy.severityThreshold": "HIGH",
+	}
+	input := `[{"Target": "alpine:3.10.2 (alpine 3.10.2)", "Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-1549",
+			"PkgName": "openssl",
+			"InstalledVersion": "1.1.1c-r0",
+			"Severity": "medium"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-18276",
+			"PkgName": "bash",
+			"InstalledVersion": "5.0-4",
+			"Severity": "HIGH"
+		},
+		{
+			"VulnerabilityID": "CVE-2019-14697",
+			"PkgName": "musl",
+			"InstalledVersion": "1.1.22-r2",
+			"Severity": "LOW"
+		}
+	]}]`
+
+	var classified []starboardv1alpha1.Severity
+	escalateOpenSSL := trivy.SeverityClassifierFunc(func(v trivy.Vulnerability) starboardv1alpha1.Severity {
+		classified = append(classified, v.Severity)
+		if v.PkgName == "openssl" {
+			return starboardv1alpha1.SeverityCritical
+		}
+		return v.Severity
+	})
+
+	report, err := trivy.NewConverter(trivy.WithSeverityClassifier(escalateOpenSSL)).
+		Convert(config, "alpine:3.10.2", strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []starboardv1alpha1.Severity{
+		starboardv1alpha1.SeverityMedium,
+		starboardv1alpha1.SeverityHigh,
+		starboardv1alpha1.SeverityLow,
+	}, classified, "classifier should receive normalized severities")
+	require.Len(t, report.Vulnerabilities, 2)
+	assert.Equal(t, "CVE-2019-1549", report.Vulnerabilities[0].VulnerabilityID)
+	assert.Equal(t, starboardv1alpha1.SeverityCritical, report.Vulnerabilities[0].Severity)
+	assert.Equal(t, "CVE-2019-18276", report.Vulnerabilities[1].VulnerabilityID)
+	assert.Equal(t, starboardv1alpha1.SeverityHigh, report.Vulnerabilities[1].Severity)
+	assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+		CriticalCount: 1,
+		HighCount:     1,
+		RiskScore:     15,
+		Fixable:       &starboardv1alpha1.FixableSummary{},
+	}, report.Summary)
+}
+
+func TestConverter_Convert_SeverityClassifier_Canonicalization(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	classifier := trivy.SeverityClassifierFunc(func(v trivy.Vulnerability) starboardv1alpha1.Severity {
+		if v.VulnerabilityID == "CVE-2019-1549" {
+			return "Critical"
+		}
+		return "SEVER"
+	})
+
+	t.Run("Should canonicalize severity and map unrecognized one to UNKNOWN", func(t *testing.T) {
+		report, err := trivy.NewConverter(trivy.WithSeverityClassifier(classifier)).
+			Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 2)
+		assert.Equal(t, starboardv1alpha1.SeverityCritical, report.Vulnerabilities[0].Severity)
+		assert.Equal(t, starboardv1alpha1.SeverityUnknown, report.Vulnerabilities[1].Severity)
+		assert.Equal(t, 1, report.Summary.CriticalCount)
+		assert.Equal(t, 1, report.Summary.UnknownCount)
+		assert.Equal(t, []string{`severity classifier returned unrecognized severity "SEVER", reported as UNKNOWN`}, report.Warnings)
+		assert.Nil(t, report.FilterStats, "no vulnerability should be omitted")
+	})
+
+	t.Run("Should map NONE severity to UNKNOWN", func(t *testing.T) {
+		none := trivy.SeverityClassifierFunc(func(_ trivy.Vulnerability) starboardv1alpha1.Severity {
+			return starboardv1alpha1.SeverityNone
+		})
+		report, err := trivy.NewConverter(trivy.WithSeverityClassifier(none)).
+			Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		require.Len(t, report.Vulnerabilities, 2)
+		for _, v := range report.Vulnerabilities {
+			assert.Equal(t, starboardv1alpha1.SeverityUnknown, v.Severity)
+		}
+		assert.Equal(t, len(report.Vulnerabilities), report.Summary.Total())
+		assert.Equal(t, 2, report.Summary.UnknownCount)
+		assert.Equal(t, []string{`severity classifier returned unrecognized severity "NONE", reported as UNKNOWN`}, report.Warnings)
+	})
+
+	t.Run("Should return error of unrecognized severity when severity is strict", func(t *testing.T) {
+		_, err := trivy.NewConverter(trivy.WithSeverityClassifier(classifier), trivy.WithStrictSeverity()).
+			Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		assert.EqualError(t, err, `unrecognized severity of vulnerability CVE-2019-1547 returned by classifier: "SEVER"`)
+	})
+}
@@ -0,0 +1,131 @@
+package trivy
+
+import (
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+)
+
+// ResultDiff is the difference between two results of scans, e.g. of a base
+// image and a candidate image built from it.
+type ResultDiff struct {
+	// Added are vulnerabilities of the new result that the old one lacks.
+	Added []starboardv1alpha1.Vulnerability
+	// Removed are vulnerabilities of the old result that the new one lacks.
+	Removed []starboardv1alpha1.Vulnerability
+	// Unchanged are vulnerabilities of the new result that the old one has too.
+	Unchanged []starboardv1alpha1.Vulnerability
+	// SummaryDelta is the summary of the new result minus the summary of the
+	// old one, hence its counts are negative if vulnerabilities were removed.
+	SummaryDelta starboardv1alpha1.VulnerabilitySummary
+	// AddedSummary is the summary of the Added vulnerabilities.
+	AddedSummary starboardv1alpha1.VulnerabilitySummary
+}
+
+// DiffResults compares the specified results of scans. Vulnerabilities are
+// matched by their identifier, package name and installed version. Added and
+// Unchanged vulnerabilities keep the order of the new result, and Removed
+// vulnerabilities the order of the old one. The AddedSummary is computed with
+// the default risk score weights. The results are not modified.
+func DiffResults(old, new starboardv1alpha1.VulnerabilityScanResult) ResultDiff {
+	return DiffResultsWithWeights(old, new, starboard.ConfigData{}.GetRiskScoreWeights())
+}
+
+// DiffResultsWithWeights is like DiffResults but it computes the AddedSummary
+// with the specified risk score weights, e.g. the ones returned by
+// Config.GetRiskScoreWeights.
+func DiffResultsWithWeights(old, new starboardv1alpha1.VulnerabilityScanResult, weights map[starboardv1alpha1.Severity]int) ResultDiff {
+	oldKeys := vulnerabilityKeys(old.Vulnerabilities)
+	newKeys := vulnerabilityKeys(new.Vulnerabilities)
+
+	var diff ResultDiff
+	for _, v := range new.Vulnerabilities {
+		if oldKeys[keyOf(v)] {
+			diff.Unchanged = append(diff.Unchanged, v)
+		} else {
+			diff.Added = append(diff.Added, v)
+		}
+	}
+	for _, v := range old.Vulnerabilities {
+		if !newKeys[keyOf(v)] {
+			diff.Removed = append(diff.Removed, v)
+		}
+	}
+
+	diff.AddedSummary = toSummary(diff.Added, weights)
+	diff.SummaryDelta = subtractSummaries(new.Summary, old.Summary)
+	return diff
+}
+
+func keyOf(v starboardv1alpha1.Vulnerability) vulnerabilityKey {
+	return vulnerabilityKey{
+		VulnerabilityID:  v.VulnerabilityID,
+		PkgName:          v.Resource,
+		InstalledVersion: v.InstalledVersion,
+	}
+}
+
+func vulnerabilityKeys(vulnerabilities []starboardv1alpha1.Vulnerability) map[vulnerabilityKey]bool {
+	keys := make(map[vulnerabilityKey]bool, len(vulnerabilities))
+	for _, v := range vulnerabilities {
+		keys[keyOf(v)] = true
+	}
+	return keys
+}
+
+func addSummaries(a, b starboardv1alpha1.VulnerabilitySummary) starboardv1alpha1.VulnerabilitySummary {
+	sum := starboardv1alpha1.VulnerabilitySummary{
+		CriticalCount: a.CriticalCount + b.CriticalCount,
+		HighCount:     a.HighCount + b.HighCount,
+		MediumCount:   a.MediumCount + b.MediumCount,
+		LowCount:      a.LowCount + b.LowCount,
+		NoneCount:     a.NoneCount + b.NoneCount,
+		UnknownCount:  a.UnknownCount + b.UnknownCount,
+		RiskScore:     a.RiskScore + b.RiskScore,
+	}
+	if a.Fixable != nil || b.Fixable != nil {
+		var fa, fb starboardv1alpha1.FixableSummary
+		if a.Fixable != nil {
+			fa = *a.Fixable
+		}
+		if b.Fixable != nil {
+			fb = *b.Fixable
+		}
+		sum.Fixable = &starboardv1alpha1.FixableSummary{
+			CriticalCount: fa.CriticalCount + fb.CriticalCount,
+			HighCount:     fa.HighCount + fb.HighCount,
+			MediumCount:   fa.MediumCount + fb.MediumCount,
+			LowCount:      fa.LowCount + fb.LowCount,
+			UnknownCount:  fa.UnknownCount + fb.UnknownCount,
+		}
+	}
+	return sum
+}
+
+func subtractSummaries(a, b starboardv1alpha1.VulnerabilitySummary) starboardv1alpha1.VulnerabilitySummary {
+	delta := starboardv1alpha1.VulnerabilitySummary{
+		CriticalCount: a.CriticalCount - b.CriticalCount,
+		HighCount:     a.HighCount - b.HighCount,
+		MediumCount:   a.MediumCount - b.MediumCount,
+		LowCount:      a.LowCount - b.LowCount,
+		NoneCount:     a.NoneCount - b.NoneCount,
+		UnknownCount:  a.UnknownCount - b.UnknownCount,
+		RiskScore:     a.RiskScore - b.RiskScore,
+	}
+	if a.Fixable != nil || b.Fixable != nil {
+		var fa, fb starboardv1alpha1.FixableSummary
+		if a.Fixable != nil {
+			fa = *a.Fixable
+		}
+		if b.Fixable != nil {
+			fb = *b.Fixable
+		}
+		delta.Fixable = &starboardv1alpha1.FixableSummary{
+			CriticalCount: fa.CriticalCount - fb.CriticalCount,
+			HighCount:     fa.HighCount - fb.HighCount,
+			MediumCount:   fa.MediumCount - fb.MediumCount,
+			LowCount:      fa.LowCount - fb.LowCount,
+			UnknownCount:  fa.UnknownCount - fb.UnknownCount,
+		}
+	}
+	return delta
+}
@@ -0,0 +1,141 @@
+package trivy_test
+
+import (
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffResults(t *testing.T) {
+	openssl := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  "CVE-2019-1549",
+		Resource:         "openssl",
+		InstalledVersion: "1.1.1c-r0",
+		FixedVersion:     "1.1.1d-r0",
+		Severity:         starboardv1alpha1.SeverityMedium,
+	}
+	opensslUpgraded := openssl
+	opensslUpgraded.InstalledVersion = "1.1.1d-r0"
+	jackson := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  "CVE-2020-9546",
+		Resource:         "com.fasterxml.jackson.core:jackson-databind",
+		InstalledVersion: "2.9.10.3",
+		Severity:         starboardv1alpha1.SeverityCritical,
+	}
+	bash := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  "CVE-2019-18276",
+		Resource:         "bash",
+		InstalledVersion: "5.0-4",
+		Severity:         starboardv1alpha1.SeverityLow,
+	}
+	newResult := func(summary starboardv1alpha1.VulnerabilitySummary, vulnerabilities ...starboardv1alpha1.Vulnerability) starboardv1alpha1.VulnerabilityScanResult {
+		return starboardv1alpha1.VulnerabilityScanResult{
+			Summary:         summary,
+			Vulnerabilities: vulnerabilities,
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		old          starboardv1alpha1.VulnerabilityScanResult
+		new          starboardv1alpha1.VulnerabilityScanResult
+		expectedDiff trivy.ResultDiff
+	}{
+		{
+			name: "Should return empty diff for identical results",
+			old:  newResult(starboardv1alpha1.VulnerabilitySummary{MediumCount: 1, LowCount: 1, RiskScore: 3}, openssl, bash),
+			new:  newResult(starboardv1alpha1.VulnerabilitySummary{MediumCount: 1, LowCount: 1, RiskScore: 3}, openssl, bash),
+			expectedDiff: trivy.ResultDiff{
+				Unchanged:    []starboardv1alpha1.Vulnerability{openssl, bash},
+				AddedSummary: starboardv1alpha1.VulnerabilitySummary{Fixable: &starboardv1alpha1.FixableSummary{}},
+			},
+		},
+		{
+			name: "Should return added vulnerabilities",
+			old:  newResult(starboardv1alpha1.VulnerabilitySummary{MediumCount: 1, RiskScore: 2}, openssl),
+			new:  newResult(starboardv1alpha1.VulnerabilitySummary{CriticalCount: 1, MediumCount: 1, RiskScore: 12}, jackson, openssl),
+			expectedDiff: trivy.ResultDiff{
+				Added:     []starboardv1alpha1.Vulnerability{jackson},
+				Unchanged: []starboardv1alpha1.Vulnerability{openssl},
+				SummaryDelta: starboardv1alpha1.VulnerabilitySummary{
+					CriticalCount: 1,
+					RiskScore:     10,
+				},
+				AddedSummary: starboardv1alpha1.VulnerabilitySummary{
+					CriticalCount: 1,
+					RiskScore:     10,
+					Fixable:       &starboardv1alpha1.FixableSummary{},
+				},
+			},
+		},
+		{
+			name: "Should return removed vulnerabilities",
+			old:  newResult(starboardv1alpha1.VulnerabilitySummary{CriticalCount: 1, MediumCount: 1, RiskScore: 12}, jackson, openssl),
+			new:  newResult(starboardv1alpha1.VulnerabilitySummary{MediumCount: 1, RiskScore: 2}, openssl),
+			expectedDiff: trivy.ResultDiff{
+				Removed:   []starboardv1alpha1.Vulnerability{jackson},
+				Unchanged: []starboardv1alpha1.Vulnerability{openssl},
+				SummaryDelta: starboardv1alpha1.VulnerabilitySummary{
+					CriticalCount: -1,
+					RiskScore:     -10,
+				},
+				AddedSummary: starboardv1alpha1.VulnerabilitySummary{Fixable: &starboardv1alpha1.FixableSummary{}},
+			},
+		},
+		{
+			name: "Should return added, removed and unchanged vulnerabilities",
+			old: newResult(starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				LowCount:    1,
+				RiskScore:   3,
+				Fixable:     &starboardv1alpha1.FixableSummary{MediumCount: 1},
+			}, openssl, bash),
+			new: newResult(starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				MediumCount:   1,
+				LowCount:      1,
+				RiskScore:     13,
+				Fixable:       &starboardv1alpha1.FixableSummary{MediumCount: 1},
+			}, jackson, opensslUpgraded, bash),
+			expectedDiff: trivy.ResultDiff{
+				Added:     []starboardv1alpha1.Vulnerability{jackson, opensslUpgraded},
+				Removed:   []starboardv1alpha1.Vulnerability{openssl},
+				Unchanged: []starboardv1alpha1.Vulnerability{bash},
+				SummaryDelta: starboardv1alpha1.VulnerabilitySummary{
+					CriticalCount: 1,
+					RiskScore:     10,
+					Fixable:       &starboardv1alpha1.FixableSummary{},
+				},
+				AddedSummary: starboardv1alpha1.VulnerabilitySummary{
+					CriticalCount: 1,
+					MediumCount:   1,
+					RiskScore:     12,
+					Fixable:       &starboardv1alpha1.FixableSummary{MediumCount: 1},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldVulnerabilities := append([]starboardv1alpha1.Vulnerability{}, tc.old.Vulnerabilities...)
+			newVulnerabilities := append([]starboardv1alpha1.Vulnerability{}, tc.new.Vulnerabilities...)
+
+			diff := trivy.DiffResults(tc.old, tc.new)
+			assert.Equal(t, tc.expectedDiff, diff)
+
+			assert.Equal(t, oldVulnerabilities, tc.old.Vulnerabilities)
+			assert.Equal(t, newVulnerabilities, tc.new.Vulnerabilities)
+		})
+	}
+
+	t.Run("Should compute summary of added vulnerabilities with specified weights", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.riskScoreWeights": "CRITICAL=20"}
+		diff := trivy.DiffResultsWithWeights(newResult(starboardv1alpha1.VulnerabilitySummary{}, openssl),
+			newResult(starboardv1alpha1.VulnerabilitySummary{}, openssl, jackson), config.GetRiskScoreWeights())
+		assert.Equal(t, 20, diff.AddedSummary.RiskScore)
+	})
+}
@@ -0,0 +1,242 @@
+package trivy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrEmptyScanOutput is returned by Converter when the output of Trivy is
+// empty or consists of whitespace only, e.g. because Trivy crashed before it
+// wrote anything. Unlike malformed output it's worth retrying the scan.
+var ErrEmptyScanOutput = errors.New("trivy scan output is empty")
+
+// ErrThresholdExceeded is returned by ConvertAndGate along with the converted
+// result when the result has vulnerabilities of the severity returned by
+// Config.GetFailOnSeverity or higher.
+var ErrThresholdExceeded = errors.New("vulnerabilities exceed the fail-on severity")
+
+// ErrLimitExceeded is matched by errors.Is for LimitExceededError.
+var ErrLimitExceeded = errors.New("trivy scan output exceeds limit")
+
+// LimitExceededError is returned by Converter when the output of Trivy exceeds
+// a limit that guards against exhausting memory or stack, e.g. because the
+// output was crafted by a malicious image. The conversion stops as soon as the
+// limit is exceeded.
+type LimitExceededError struct {
+	// Limit is the name of the exceeded limit, i.e. size or nesting depth.
+	Limit string
+	// Max is the value of the exceeded limit.
+	Max int64
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("trivy scan output exceeds the maximum %s of %d", e.Limit, e.Max)
+}
+
+// Is reports whether the target is ErrLimitExceeded.
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// ErrMalformedOutput is matched by errors.Is for MalformedOutputError.
+var ErrMalformedOutput = errors.New("malformed trivy scan output")
+
+// MalformedOutputError is returned by Converter when the JSON output of Trivy
+// cannot be decoded, e.g. because it was cut off when the disk filled up.
+type MalformedOutputError struct {
+	// ImageRef is the reference of the scanned image, if known.
+	ImageRef string
+	// Offset is the offset of the JSON output, without the noisy output that
+	// precedes it, where decoding failed. For truncated output it's the
+	// length of the output.
+	Offset int64
+	// Err is the error of decoding.
+	Err error
+}
+
+func (e *MalformedOutputError) Error() string {
+	subject := "trivy scan output"
+	if e.ImageRef != "" {
+		subject = fmt.Sprintf("trivy scan output of image %s", e.ImageRef)
+	}
+	hint := ""
+	if errors.Is(e.Err, io.ErrUnexpectedEOF) {
+		hint = ", the output may be truncated"
+	}
+	return fmt.Sprintf("decoding %s at offset %d%s: %v", subject, e.Offset, hint, e.Err)
+}
+
+func (e *MalformedOutputError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrMalformedOutput.
+func (e *MalformedOutputError) Is(target error) bool {
+	return target == ErrMalformedOutput
+}
+
+// toMalformedOutputError wraps the specified error of decoding JSON output in
+// MalformedOutputError. The length is the number of bytes read so far, which
+// is the offset of a MalformedOutputError returned without one. Other errors,
+// e.g. of reading the output, are returned unchanged.
+func toMalformedOutputError(err error, length int64) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var malformedErr *MalformedOutputError
+	switch {
+	case errors.As(err, &malformedErr):
+		if malformedErr.Offset == 0 {
+			malformedErr.Offset = length
+		}
+		return err
+	case errors.As(err, &syntaxErr):
+		return &MalformedOutputError{Offset: syntaxErr.Offset, Err: err}
+	case errors.As(err, &typeErr):
+		return &MalformedOutputError{Offset: typeErr.Offset, Err: err}
+	case errors.Is(err, io.ErrUnexpectedEOF), isUnknownFieldError(err):
+		return &MalformedOutputError{Offset: length, Err: err}
+	}
+	return err
+}
+
+// isUnknownFieldError checks whether the specified error is the error returned
+// by json.Decoder for an unknown field when unknown fields are disallowed. The
+// error has no type of its own, so it's recognized by its message.
+func isUnknownFieldError(err error) bool {
+	return strings.HasPrefix(err.Error(), "json: unknown field ")
+}
+
+// withImageRef sets the image reference of the specified error if it's
+// MalformedOutputError.
+func withImageRef(err error, imageRef string) error {
+	var malformedErr *MalformedOutputError
+	if errors.As(err, &malformedErr) {
+		malformedErr.ImageRef = imageRef
+	}
+	return err
+}
+
+// ErrUnexpectedStatus is matched by errors.Is for ResponseStatusError.
+var ErrUnexpectedStatus = errors.New("unexpected status of trivy server response")
+
+// ResponseStatusError is returned by Converter.ConvertResponse when the status
+// code of the response is not 2xx, e.g. because Trivy server failed to scan
+// the image.
+type ResponseStatusError struct {
+	// StatusCode is the status code of the response, e.g. 500.
+	StatusCode int
+	// Status is the status line of the response, e.g. "500 Internal Server Error".
+	Status string
+	// Body is the beginning of the body of the response, which usually tells
+	// why the request failed.
+	Body string
+}
+
+func (e *ResponseStatusError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = strconv.Itoa(e.StatusCode)
+	}
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected status of trivy server response: %s", status)
+	}
+	return fmt.Sprintf("unexpected status of trivy server response: %s: %s", status, e.Body)
+}
+
+// Is reports whether the target is ErrUnexpectedStatus.
+func (e *ResponseStatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
+
+// ErrInvalidImageRef is matched by errors.Is for InvalidImageRefError.
+var ErrInvalidImageRef = errors.New("invalid image reference")
+
+// InvalidImageRefError is returned by a Converter constructed with
+// WithRejectInvalidImageRefs when the reference of the scanned image cannot be
+// parsed. Otherwise the error is reported as a warning of the result.
+type InvalidImageRefError struct {
+	// ImageRef is the reference of the scanned image.
+	ImageRef string
+	// Err is the error of parsing the reference, e.g. name.ErrBadName.
+	Err error
+}
+
+func (e *InvalidImageRefError) Error() string {
+	return fmt.Sprintf("%s %q: %v", imageRefWarningPrefix, e.ImageRef, e.Err)
+}
+
+func (e *InvalidImageRefError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrInvalidImageRef.
+func (e *InvalidImageRefError) Is(target error) bool {
+	return target == ErrInvalidImageRef
+}
+
+// ErrVersionResolution is matched by errors.Is for VersionResolutionError.
+var ErrVersionResolution = errors.New("scanner version cannot be resolved")
+
+// VersionResolutionError is returned by a Converter constructed with
+// WithRejectUnknownVersions when the VersionResolver fails. Otherwise the
+// error is reported as a warning of the result with an unknown version.
+type VersionResolutionError struct {
+	// Err is the error returned by the VersionResolver.
+	Err error
+}
+
+func (e *VersionResolutionError) Error() string {
+	return fmt.Sprintf("resolving scanner version: %v", e.Err)
+}
+
+func (e *VersionResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrVersionResolution.
+func (e *VersionResolutionError) Is(target error) bool {
+	return target == ErrVersionResolution
+}
+
+// ScanError is returned by Converter when the output of Trivy indicates that
+// the scan failed, e.g. because the image could not be pulled or the
+// vulnerabilities database could not be downloaded.
+type ScanError struct {
+	// Message is the line of the output that reported the failure.
+	Message string
+}
+
+func (e *ScanError) Error() string {
+	return "trivy scan failed: " + e.Message
+}
+
+// scanErrorSignatures are fragments of log lines printed by Trivy when a scan
+// fails.
+var scanErrorSignatures = [][]byte{
+	[]byte("FATAL"),
+	[]byte("unable to"),
+}
+
+// toScanError returns ScanError if any line of the specified output matches
+// one of the scanErrorSignatures, nil otherwise. Lines that look like JSON are
+// ignored, because descriptions of vulnerabilities often contain phrases such
+// as "unable to".
+func toScanError(output []byte) error {
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) > 0 && bytes.IndexByte([]byte(`"{}[]`), trimmed[0]) >= 0 {
+			continue
+		}
+		for _, signature := range scanErrorSignatures {
+			if bytes.Contains(line, signature) {
+				return &ScanError{Message: string(bytes.TrimSpace(line))}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package trivy
+
+import (
+	"path"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+)
+
+// FilterExcluding returns a copy of the specified VulnerabilityScanResult
+// without vulnerabilities whose identifiers match any of the specified ids.
+// An id is either an exact identifier, e.g. CVE-2019-1549, or a glob pattern,
+// e.g. CVE-2021-*. The summary and the ecosystem summary of the returned result
+// are recomputed from the remaining vulnerabilities with the default risk score
+// weights. If the result is truncated, its summaries also count the dropped
+// vulnerabilities, so the removed vulnerabilities are discounted from them
+// instead.
+//
+// The specified result is not modified.
+func FilterExcluding(result starboardv1alpha1.VulnerabilityScanResult, ids []string) starboardv1alpha1.VulnerabilityScanResult {
+	return FilterExcludingWithWeights(result, ids, starboard.ConfigData{}.GetRiskScoreWeights())
+}
+
+// FilterExcludingWithWeights is like FilterExcluding but it recomputes the
+// summaries with the specified risk score weights, e.g. the ones returned by
+// Config.GetRiskScoreWeights.
+func FilterExcludingWithWeights(result starboardv1alpha1.VulnerabilityScanResult, ids []string, weights map[starboardv1alpha1.Severity]int) starboardv1alpha1.VulnerabilityScanResult {
+	if len(ids) == 0 {
+		return result
+	}
+	vulnerabilities := make([]starboardv1alpha1.Vulnerability, 0, len(result.Vulnerabilities))
+	var removed []starboardv1alpha1.Vulnerability
+	for _, v := range result.Vulnerabilities {
+		if matchesAny(v.VulnerabilityID, ids) {
+			removed = append(removed, v)
+			continue
+		}
+		vulnerabilities = append(vulnerabilities, v)
+	}
+	result.Vulnerabilities = vulnerabilities
+	if result.Truncated {
+		result.Summary = adjustSummary(result.Summary, nil, removed, weights)
+		if result.EcosystemSummary != nil {
+			result.EcosystemSummary = adjustEcosystemSummary(result.EcosystemSummary, nil, removed, weights)
+		}
+		return result
+	}
+	result.Summary = toSummary(vulnerabilities, weights)
+	if result.EcosystemSummary != nil {
+		result.EcosystemSummary = toEcosystemSummary(groupByEcosystem(vulnerabilities), weights)
+	}
+	return result
+}
+
+// matchesAny checks whether the specified vulnerability identifier or package
+// name matches any of the specified names or glob patterns. A malformed pattern
+// only matches the identical name.
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package trivy_test
+
+import (
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterExcluding(t *testing.T) {
+	result := starboardv1alpha1.VulnerabilityScanResult{
+		Summary: starboardv1alpha1.VulnerabilitySummary{
+			CriticalCount: 1,
+			HighCount:     1,
+			MediumCount:   1,
+			LowCount:      1,
+			RiskScore:     18,
+		},
+		Vulnerabilities: []starboardv1alpha1.Vulnerability{
+			{VulnerabilityID: "CVE-2019-1549", Severity: starboardv1alpha1.SeverityMedium},
+			{VulnerabilityID: "CVE-2021-3449", Severity: starboardv1alpha1.SeverityHigh},
+			{VulnerabilityID: "CVE-2021-23840", Severity: starboardv1alpha1.SeverityCritical},
+			{VulnerabilityID: "GHSA-jfh8-c2jp-5v3q", Severity: starboardv1alpha1.SeverityLow},
+		},
+	}
+
+	testCases := []struct {
+		name                    string
+		ids                     []string
+		expectedVulnerabilities []string
+		expectedSummary         starboardv1alpha1.VulnerabilitySummary
+	}{
+		{
+			name:                    "Should return result unchanged when exclusion list is empty",
+			ids:                     nil,
+			expectedVulnerabilities: []string{"CVE-2019-1549", "CVE-2021-3449", "CVE-2021-23840", "GHSA-jfh8-c2jp-5v3q"},
+			expectedSummary:         result.Summary,
+		},
+		{
+			name:                    "Should exclude vulnerabilities with exact identifiers",
+			ids:                     []string{"CVE-2019-1549", "GHSA-jfh8-c2jp-5v3q"},
+			expectedVulnerabilities: []string{"CVE-2021-3449", "CVE-2021-23840"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				HighCount:     1,
+				RiskScore:     15,
+				Fixable:       &starboardv1alpha1.FixableSummary{},
+			},
+		},
+		{
+			name:                    "Should exclude vulnerabilities matching glob pattern",
+			ids:                     []string{"CVE-2021-*"},
+			expectedVulnerabilities: []string{"CVE-2019-1549", "GHSA-jfh8-c2jp-5v3q"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				LowCount:    1,
+				RiskScore:   3,
+				Fixable:     &starboardv1alpha1.FixableSummary{},
+			},
+		},
+		{
+			name:                    "Should treat malformed pattern as exact identifier",
+			ids:                     []string{"CVE-2019-[1549"},
+			expectedVulnerabilities: []string{"CVE-2019-1549", "CVE-2021-3449", "CVE-2021-23840", "GHSA-jfh8-c2jp-5v3q"},
+			expectedSummary: starboardv1alpha1.VulnerabilitySummary{
+				CriticalCount: 1,
+				HighCount:     1,
+				MediumCount:   1,
+				LowCount:      1,
+				RiskScore:     18,
+				Fixable:       &starboardv1alpha1.FixableSummary{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := trivy.FilterExcluding(result, tc.ids)
+
+			var ids []string
+			for _, v := range filtered.Vulnerabilities {
+				ids = append(ids, v.VulnerabilityID)
+			}
+			assert.Equal(t, tc.expectedVulnerabilities, ids)
+			assert.Equal(t, tc.expectedSummary, filtered.Summary)
+			assert.Len(t, result.Vulnerabilities, 4, "input result must not be modified")
+			assert.Equal(t, "CVE-2019-1549", result.Vulnerabilities[0].VulnerabilityID, "input result must not be modified")
+		})
+	}
+
+	t.Run("Should recompute ecosystem summary", func(t *testing.T) {
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2019-1549", Severity: starboardv1alpha1.SeverityMedium, Ecosystem: "alpine"},
+				{VulnerabilityID: "CVE-2021-23337", Severity: starboardv1alpha1.SeverityHigh, Ecosystem: "npm"},
+			},
+			EcosystemSummary: map[string]starboardv1alpha1.VulnerabilitySummary{
+				"alpine": {MediumCount: 1, RiskScore: 2},
+				"npm":    {HighCount: 1, RiskScore: 5},
+			},
+		}
+
+		filtered := trivy.FilterExcluding(result, []string{"CVE-2021-*"})
+		assert.Equal(t, map[string]starboardv1alpha1.VulnerabilitySummary{
+			"alpine": {MediumCount: 1, RiskScore: 2, Fixable: &starboardv1alpha1.FixableSummary{}},
+		}, filtered.EcosystemSummary)
+		assert.Len(t, result.EcosystemSummary, 2, "input result must not be modified")
+	})
+
+	t.Run("Should recompute risk score with specified weights", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.riskScoreWeights": "CRITICAL=20,HIGH=8"}
+		filtered := trivy.FilterExcludingWithWeights(result, []string{"CVE-2019-1549", "GHSA-jfh8-c2jp-5v3q"}, config.GetRiskScoreWeights())
+		assert.Equal(t, 28, filtered.Summary.RiskScore)
+	})
+
+	t.Run("Should count vulnerabilities of NONE severity", func(t *testing.T) {
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2019-1549", Severity: starboardv1alpha1.SeverityNone},
+				{VulnerabilityID: "CVE-2021-3449", Severity: starboardv1alpha1.SeverityHigh},
+			},
+		}
+		filtered := trivy.FilterExcluding(result, []string{"CVE-2021-3449"})
+		assert.Equal(t, 1, filtered.Summary.NoneCount)
+		assert.Equal(t, len(filtered.Vulnerabilities), filtered.Summary.Total())
+	})
+
+	t.Run("Should discount removed vulnerabilities from summaries of truncated result", func(t *testing.T) {
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2021-3449", Severity: starboardv1alpha1.SeverityHigh, FixedVersion: "1.1.1k-r0", Ecosystem: "alpine"},
+				{VulnerabilityID: "CVE-2019-1549", Severity: starboardv1alpha1.SeverityMedium, Ecosystem: "alpine"},
+			},
+			Summary: starboardv1alpha1.VulnerabilitySummary{
+				HighCount:   1,
+				MediumCount: 2,
+				LowCount:    3,
+				RiskScore:   12,
+				Fixable:     &starboardv1alpha1.FixableSummary{HighCount: 1, LowCount: 1},
+			},
+			EcosystemSummary: map[string]starboardv1alpha1.VulnerabilitySummary{
+				"alpine": {HighCount: 1, MediumCount: 2, RiskScore: 9, Fixable: &starboardv1alpha1.FixableSummary{HighCount: 1}},
+				"npm":    {LowCount: 3, RiskScore: 3, Fixable: &starboardv1alpha1.FixableSummary{LowCount: 1}},
+			},
+			Truncated:    true,
+			DroppedCount: 4,
+		}
+
+		filtered := trivy.FilterExcluding(result, []string{"CVE-2021-3449"})
+		assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+			MediumCount: 2,
+			LowCount:    3,
+			RiskScore:   7,
+			Fixable:     &starboardv1alpha1.FixableSummary{LowCount: 1},
+		}, filtered.Summary)
+		assert.Equal(t, map[string]starboardv1alpha1.VulnerabilitySummary{
+			"alpine": {MediumCount: 2, RiskScore: 4, Fixable: &starboardv1alpha1.FixableSummary{}},
+			"npm":    {LowCount: 3, RiskScore: 3, Fixable: &starboardv1alpha1.FixableSummary{LowCount: 1}},
+		}, filtered.EcosystemSummary)
+		assert.True(t, filtered.Truncated)
+		assert.Equal(t, 4, filtered.DroppedCount)
+	})
+}
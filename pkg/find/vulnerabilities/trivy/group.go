@@ -0,0 +1,22 @@
+package trivy
+
+import (
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+// GroupByPackage groups the vulnerabilities of the specified
+// VulnerabilityScanResult by the name of the affected package. The
+// vulnerabilities of each package are ordered from the most to the least
+// severe. An empty map is returned if the result has no vulnerabilities.
+//
+// The specified result is not modified.
+func GroupByPackage(result starboardv1alpha1.VulnerabilityScanResult) map[string][]starboardv1alpha1.Vulnerability {
+	groups := make(map[string][]starboardv1alpha1.Vulnerability)
+	for _, v := range result.Vulnerabilities {
+		groups[v.Resource] = append(groups[v.Resource], v)
+	}
+	for _, vulnerabilities := range groups {
+		sortVulnerabilities(vulnerabilities)
+	}
+	return groups
+}
@@ -0,0 +1,49 @@
+package trivy_test
+
+import (
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByPackage(t *testing.T) {
+	t.Run("Should return empty map when result has no vulnerabilities", func(t *testing.T) {
+		groups := trivy.GroupByPackage(starboardv1alpha1.VulnerabilityScanResult{})
+		assert.NotNil(t, groups)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("Should group vulnerabilities by package ordered by severity", func(t *testing.T) {
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{VulnerabilityID: "CVE-2020-7595", Resource: "libxml2", Severity: starboardv1alpha1.SeverityHigh},
+				{VulnerabilityID: "CVE-2019-1549", Resource: "openssl", Severity: starboardv1alpha1.SeverityMedium},
+				{VulnerabilityID: "CVE-2019-20388", Resource: "libxml2", Severity: starboardv1alpha1.SeverityMedium},
+				{VulnerabilityID: "CVE-2021-3449", Resource: "openssl", Severity: starboardv1alpha1.SeverityHigh},
+				{VulnerabilityID: "CVE-2021-3517", Resource: "libxml2", Severity: starboardv1alpha1.SeverityCritical},
+				{VulnerabilityID: "CVE-2021-23840", Resource: "openssl", Severity: starboardv1alpha1.SeverityCritical},
+				{VulnerabilityID: "CVE-2019-1551", Resource: "openssl", Severity: starboardv1alpha1.SeverityLow},
+			},
+		}
+		original := append([]starboardv1alpha1.Vulnerability(nil), result.Vulnerabilities...)
+
+		groups := trivy.GroupByPackage(result)
+
+		assert.Equal(t, map[string][]starboardv1alpha1.Vulnerability{
+			"libxml2": {
+				{VulnerabilityID: "CVE-2021-3517", Resource: "libxml2", Severity: starboardv1alpha1.SeverityCritical},
+				{VulnerabilityID: "CVE-2020-7595", Resource: "libxml2", Severity: starboardv1alpha1.SeverityHigh},
+				{VulnerabilityID: "CVE-2019-20388", Resource: "libxml2", Severity: starboardv1alpha1.SeverityMedium},
+			},
+			"openssl": {
+				{VulnerabilityID: "CVE-2021-23840", Resource: "openssl", Severity: starboardv1alpha1.SeverityCritical},
+				{VulnerabilityID: "CVE-2021-3449", Resource: "openssl", Severity: starboardv1alpha1.SeverityHigh},
+				{VulnerabilityID: "CVE-2019-1549", Resource: "openssl", Severity: starboardv1alpha1.SeverityMedium},
+				{VulnerabilityID: "CVE-2019-1551", Resource: "openssl", Severity: starboardv1alpha1.SeverityLow},
+			},
+		}, groups)
+		assert.Equal(t, original, result.Vulnerabilities)
+	})
+}
@@ -0,0 +1,76 @@
+package trivy
+
+import (
+	"io"
+)
+
+// Limits of LimitExceededError.
+const (
+	limitSize         = "size"
+	limitNestingDepth = "nesting depth"
+)
+
+// maxNestingDepth is the maximum nesting depth of JSON arrays and objects in
+// the output of Trivy. The output of Trivy is nested a few levels deep, hence
+// deeper nesting is a sign of a decode bomb.
+const maxNestingDepth = 100
+
+// sizeLimitingReader is an io.Reader that fails with LimitExceededError once
+// more than max bytes are read.
+type sizeLimitingReader struct {
+	reader io.Reader
+	max    int64
+	count  int64
+}
+
+func (r *sizeLimitingReader) Read(p []byte) (int, error) {
+	// Read at most one byte past the limit, which is enough to tell that the
+	// limit is exceeded.
+	if remaining := r.max - r.count + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	if r.count > r.max {
+		return 0, &LimitExceededError{Limit: limitSize, Max: r.max}
+	}
+	return n, err
+}
+
+// depthLimitingReader is an io.Reader that fails with LimitExceededError once
+// the JSON read from it is nested deeper than max arrays or objects, before the
+// JSON decoder recurses into them.
+type depthLimitingReader struct {
+	reader   io.Reader
+	max      int
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+func (r *depthLimitingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	for _, b := range p[:n] {
+		switch {
+		case r.escaped:
+			r.escaped = false
+		case r.inString:
+			switch b {
+			case '\\':
+				r.escaped = true
+			case '"':
+				r.inString = false
+			}
+		case b == '"':
+			r.inString = true
+		case b == '[' || b == '{':
+			r.depth++
+			if r.depth > r.max {
+				return 0, &LimitExceededError{Limit: limitNestingDepth, Max: int64(r.max)}
+			}
+		case b == ']' || b == '}':
+			r.depth--
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,137 @@
+package trivy
+
+import (
+	"fmt"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+)
+
+// MergeResults merges the specified results of scans of the same artifact,
+// e.g. separate scans of OS packages and application dependencies of an image.
+// Vulnerabilities are concatenated and deduplicated, keeping the first
+// occurrence with the links and aliases of all occurrences, and the summary and
+// the ecosystem summary are recomputed with the default risk score weights. The
+// summaries of a truncated result also count the dropped vulnerabilities, so
+// they are added up instead, and duplicates are discounted from them. The
+// results are truncated if either of them is, and dropped counts are added up.
+// The FilterStats of the results are added up, and vulnerabilities dropped as
+// duplicates count as deduplicated. Secrets and warnings are concatenated, and
+// so are scanned targets, which are deduplicated. The scanner and the other
+// metadata are taken from the first result.
+//
+// An error is returned if the results describe different artifacts.
+func MergeResults(a, b starboardv1alpha1.VulnerabilityScanResult) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	return MergeResultsWithWeights(a, b, starboard.ConfigData{}.GetRiskScoreWeights())
+}
+
+// MergeResultsWithWeights is like MergeResults but it recomputes the summaries
+// with the specified risk score weights, e.g. the ones returned by
+// Config.GetRiskScoreWeights.
+func MergeResultsWithWeights(a, b starboardv1alpha1.VulnerabilityScanResult, weights map[starboardv1alpha1.Severity]int) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	if a.Registry != b.Registry || a.Artifact != b.Artifact {
+		return starboardv1alpha1.VulnerabilityScanResult{}, fmt.Errorf("cannot merge results of different artifacts: %s and %s",
+			a.ImageRef(), b.ImageRef())
+	}
+
+	vulnerabilities := make([]starboardv1alpha1.Vulnerability, 0, len(a.Vulnerabilities)+len(b.Vulnerabilities))
+	indexByKey := make(map[vulnerabilityKey]int)
+	var stats starboardv1alpha1.FilterStats
+	var duplicates []starboardv1alpha1.Vulnerability
+	for _, vulnerabilitiesOfResult := range [][]starboardv1alpha1.Vulnerability{a.Vulnerabilities, b.Vulnerabilities} {
+		for _, v := range vulnerabilitiesOfResult {
+			key := keyOf(v)
+			if index, ok := indexByKey[key]; ok {
+				stats.Deduplicated++
+				duplicates = append(duplicates, v)
+				mergeReferences(&vulnerabilities[index], v)
+				continue
+			}
+			indexByKey[key] = len(vulnerabilities)
+			vulnerabilities = append(vulnerabilities, v)
+		}
+	}
+	sortVulnerabilities(vulnerabilities)
+
+	merged := a
+	merged.Vulnerabilities = vulnerabilities
+	if a.Truncated || b.Truncated {
+		merged.Summary, merged.EcosystemSummary = mergeTruncatedSummaries(a, b, duplicates, weights)
+	} else {
+		merged.Summary = toSummary(vulnerabilities, weights)
+		if a.EcosystemSummary != nil || b.EcosystemSummary != nil {
+			merged.EcosystemSummary = toEcosystemSummary(groupByEcosystem(vulnerabilities), weights)
+		}
+	}
+	merged.Truncated = a.Truncated || b.Truncated
+	merged.DroppedCount = a.DroppedCount + b.DroppedCount
+	merged.FilterStats = mergeFilterStats(stats, a.FilterStats, b.FilterStats)
+	if len(a.Secrets) > 0 || len(b.Secrets) > 0 {
+		merged.Secrets = append(append([]starboardv1alpha1.SecretFinding{}, a.Secrets...), b.Secrets...)
+	}
+	merged.ScannedTargets = unionStrings(a.ScannedTargets, b.ScannedTargets)
+	if len(a.Warnings) > 0 || len(b.Warnings) > 0 {
+		merged.Warnings = append(append([]string{}, a.Warnings...), b.Warnings...)
+	}
+	return merged, nil
+}
+
+// mergeTruncatedSummaries adds up the summaries and the ecosystem summaries of
+// the specified results, at least one of which is truncated, and discounts the
+// given duplicates from them. The summaries of a result that is not truncated
+// are computed from its vulnerabilities. The ecosystem summary is nil unless
+// either result has one.
+func mergeTruncatedSummaries(a, b starboardv1alpha1.VulnerabilityScanResult, duplicates []starboardv1alpha1.Vulnerability, weights map[starboardv1alpha1.Severity]int) (starboardv1alpha1.VulnerabilitySummary, map[string]starboardv1alpha1.VulnerabilitySummary) {
+	var summary starboardv1alpha1.VulnerabilitySummary
+	ecosystemSummary := make(map[string]starboardv1alpha1.VulnerabilitySummary)
+	for _, result := range []starboardv1alpha1.VulnerabilityScanResult{a, b} {
+		resultSummary := result.Summary
+		resultEcosystemSummary := result.EcosystemSummary
+		if !result.Truncated {
+			resultSummary = toSummary(result.Vulnerabilities, weights)
+		}
+		if !result.Truncated || resultEcosystemSummary == nil {
+			resultEcosystemSummary = toEcosystemSummary(groupByEcosystem(result.Vulnerabilities), weights)
+		}
+		summary = addSummaries(summary, resultSummary)
+		for ecosystem, s := range resultEcosystemSummary {
+			ecosystemSummary[ecosystem] = addSummaries(ecosystemSummary[ecosystem], s)
+		}
+	}
+	summary = adjustSummary(summary, nil, duplicates, weights)
+	if a.EcosystemSummary == nil && b.EcosystemSummary == nil {
+		return summary, nil
+	}
+	return summary, adjustEcosystemSummary(ecosystemSummary, nil, duplicates, weights)
+}
+
+// mergeReferences adds the links and aliases of the specified duplicate to
+// the given vulnerability, which are kept in the order they first appear, and
+// recategorizes the links.
+func mergeReferences(v *starboardv1alpha1.Vulnerability, duplicate starboardv1alpha1.Vulnerability) {
+	v.Links = unionStrings(v.Links, duplicate.Links)
+	v.Aliases = unionStrings(v.Aliases, duplicate.Aliases)
+	if v.CategorizedLinks != nil || duplicate.CategorizedLinks != nil {
+		v.CategorizedLinks = toCategorizedLinks(v.Links)
+	}
+}
+
+// mergeFilterStats adds up the specified FilterStats. It returns nil if the
+// sum is zero.
+func mergeFilterStats(stats starboardv1alpha1.FilterStats, others ...*starboardv1alpha1.FilterStats) *starboardv1alpha1.FilterStats {
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+		stats.BelowThreshold += other.BelowThreshold
+		stats.BelowMinScore += other.BelowMinScore
+		stats.Ignored += other.Ignored
+		stats.Unfixed += other.Unfixed
+		stats.Deduplicated += other.Deduplicated
+		stats.ExcludedPackages += other.ExcludedPackages
+	}
+	if stats.Total() == 0 {
+		return nil
+	}
+	return &stats
+}
@@ -0,0 +1,226 @@
+package trivy_test
+
+import (
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeResults(t *testing.T) {
+	newResult := func(vulnerabilities ...starboardv1alpha1.Vulnerability) starboardv1alpha1.VulnerabilityScanResult {
+		return starboardv1alpha1.VulnerabilityScanResult{
+			Scanner: starboardv1alpha1.Scanner{
+				Name:    "Trivy",
+				Vendor:  "Aqua Security",
+				Version: "0.9.1",
+			},
+			Registry: starboardv1alpha1.Registry{
+				Server: "index.docker.io",
+			},
+			Artifact: starboardv1alpha1.Artifact{
+				Repository: "library/tomcat",
+				Tag:        "9.0",
+			},
+			Vulnerabilities: vulnerabilities,
+		}
+	}
+	openssl := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  "CVE-2019-1549",
+		Resource:         "openssl",
+		InstalledVersion: "1.1.1c-r0",
+		FixedVersion:     "1.1.1d-r0",
+		Severity:         starboardv1alpha1.SeverityMedium,
+	}
+	jackson := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  "CVE-2020-9546",
+		Resource:         "com.fasterxml.jackson.core:jackson-databind",
+		InstalledVersion: "2.9.10.3",
+		Severity:         starboardv1alpha1.SeverityCritical,
+	}
+	bash := starboardv1alpha1.Vulnerability{
+		VulnerabilityID:  "CVE-2019-18276",
+		Resource:         "bash",
+		InstalledVersion: "5.0-4",
+		Severity:         starboardv1alpha1.SeverityLow,
+	}
+
+	t.Run("Should merge results of the same artifact", func(t *testing.T) {
+		merged, err := trivy.MergeResults(newResult(openssl), newResult(jackson))
+		require.NoError(t, err)
+
+		expected := newResult(jackson, openssl)
+		expected.Summary = starboardv1alpha1.VulnerabilitySummary{
+			CriticalCount: 1,
+			MediumCount:   1,
+			RiskScore:     12,
+			Fixable: &starboardv1alpha1.FixableSummary{
+				MediumCount: 1,
+			},
+		}
+		assert.Equal(t, expected, merged)
+	})
+
+	t.Run("Should deduplicate overlapping vulnerabilities", func(t *testing.T) {
+		duplicate := openssl
+		duplicate.Title = "duplicate"
+
+		merged, err := trivy.MergeResults(newResult(openssl, bash), newResult(duplicate, jackson))
+		require.NoError(t, err)
+
+		var ids []string
+		for _, v := range merged.Vulnerabilities {
+			ids = append(ids, v.VulnerabilityID)
+		}
+		assert.Equal(t, []string{"CVE-2020-9546", "CVE-2019-1549", "CVE-2019-18276"}, ids)
+		assert.Equal(t, "", merged.Vulnerabilities[1].Title)
+		assert.Equal(t, 3, merged.Summary.Total())
+	})
+
+	t.Run("Should merge links and aliases of overlapping vulnerabilities", func(t *testing.T) {
+		first := openssl
+		first.Links = []string{
+			"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+			"https://www.openssl.org/news/secadv/20190910.txt",
+		}
+		first.Aliases = []string{"GHSA-2222-2222-2222"}
+		first.CategorizedLinks = &starboardv1alpha1.CategorizedLinks{
+			Advisory: []string{"https://nvd.nist.gov/vuln/detail/CVE-2019-1549"},
+			Other:    []string{"https://www.openssl.org/news/secadv/20190910.txt"},
+		}
+		duplicate := openssl
+		duplicate.Links = []string{
+			"https://github.com/openssl/openssl/commit/1b0fe00e2704b5e20334a16d3c9099d1ba2ef1be",
+			"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+		}
+		duplicate.Aliases = []string{"GHSA-1111-1111-1111", "GHSA-2222-2222-2222"}
+
+		merged, err := trivy.MergeResults(newResult(first), newResult(duplicate))
+		require.NoError(t, err)
+		require.Len(t, merged.Vulnerabilities, 1)
+		v := merged.Vulnerabilities[0]
+		assert.Equal(t, []string{
+			"https://nvd.nist.gov/vuln/detail/CVE-2019-1549",
+			"https://www.openssl.org/news/secadv/20190910.txt",
+			"https://github.com/openssl/openssl/commit/1b0fe00e2704b5e20334a16d3c9099d1ba2ef1be",
+		}, v.Links)
+		assert.Equal(t, []string{"GHSA-2222-2222-2222", "GHSA-1111-1111-1111"}, v.Aliases)
+		assert.Equal(t, &starboardv1alpha1.CategorizedLinks{
+			Advisory: []string{"https://nvd.nist.gov/vuln/detail/CVE-2019-1549"},
+			Patch:    []string{"https://github.com/openssl/openssl/commit/1b0fe00e2704b5e20334a16d3c9099d1ba2ef1be"},
+			Other:    []string{"https://www.openssl.org/news/secadv/20190910.txt"},
+		}, v.CategorizedLinks)
+		assert.Len(t, first.Links, 2, "links of merged result must not be modified")
+	})
+
+	t.Run("Should add up filter stats", func(t *testing.T) {
+		a := newResult(openssl)
+		a.FilterStats = &starboardv1alpha1.FilterStats{BelowThreshold: 2, Ignored: 1}
+		b := newResult(openssl, jackson)
+		b.FilterStats = &starboardv1alpha1.FilterStats{BelowThreshold: 1, Unfixed: 3}
+
+		merged, err := trivy.MergeResults(a, b)
+		require.NoError(t, err)
+		assert.Equal(t, &starboardv1alpha1.FilterStats{
+			BelowThreshold: 3,
+			Ignored:        1,
+			Unfixed:        3,
+			Deduplicated:   1,
+		}, merged.FilterStats)
+	})
+
+	t.Run("Should concatenate secrets", func(t *testing.T) {
+		awsKey := starboardv1alpha1.SecretFinding{RuleID: "aws-access-key-id", Category: "AWS", Severity: starboardv1alpha1.SeverityCritical, Target: "/app/config"}
+		githubToken := starboardv1alpha1.SecretFinding{RuleID: "github-pat", Category: "GitHub", Severity: starboardv1alpha1.SeverityCritical, Target: "/app/.env"}
+		a := newResult(openssl)
+		a.Secrets = []starboardv1alpha1.SecretFinding{awsKey}
+		b := newResult(jackson)
+		b.Secrets = []starboardv1alpha1.SecretFinding{githubToken}
+
+		merged, err := trivy.MergeResults(a, b)
+		require.NoError(t, err)
+		assert.Equal(t, []starboardv1alpha1.SecretFinding{awsKey, githubToken}, merged.Secrets)
+		assert.Len(t, a.Secrets, 1, "secrets of merged result must not be modified")
+
+		merged, err = trivy.MergeResults(newResult(openssl), b)
+		require.NoError(t, err)
+		assert.Equal(t, []starboardv1alpha1.SecretFinding{githubToken}, merged.Secrets)
+	})
+
+	t.Run("Should merge scanned targets", func(t *testing.T) {
+		a := newResult(openssl)
+		a.ScannedTargets = []string{"tomcat:9.0 (debian 10.4)", "usr/local/tomcat/lib"}
+		b := newResult(jackson)
+		b.ScannedTargets = []string{"usr/local/tomcat/lib", "usr/local/tomcat/webapps"}
+
+		merged, err := trivy.MergeResults(a, b)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tomcat:9.0 (debian 10.4)", "usr/local/tomcat/lib", "usr/local/tomcat/webapps"}, merged.ScannedTargets)
+	})
+
+	t.Run("Should recompute ecosystem summary", func(t *testing.T) {
+		alpine := openssl
+		alpine.Ecosystem = "alpine"
+		jar := jackson
+		jar.Ecosystem = "jar"
+		a := newResult(alpine)
+		a.EcosystemSummary = map[string]starboardv1alpha1.VulnerabilitySummary{
+			"alpine": {MediumCount: 1, RiskScore: 2, Fixable: &starboardv1alpha1.FixableSummary{MediumCount: 1}},
+		}
+		b := newResult(alpine, jar)
+		b.EcosystemSummary = map[string]starboardv1alpha1.VulnerabilitySummary{
+			"alpine": {MediumCount: 1, RiskScore: 2, Fixable: &starboardv1alpha1.FixableSummary{MediumCount: 1}},
+			"jar":    {CriticalCount: 1, RiskScore: 10, Fixable: &starboardv1alpha1.FixableSummary{}},
+		}
+
+		merged, err := trivy.MergeResults(a, b)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]starboardv1alpha1.VulnerabilitySummary{
+			"alpine": {MediumCount: 1, RiskScore: 2, Fixable: &starboardv1alpha1.FixableSummary{MediumCount: 1}},
+			"jar":    {CriticalCount: 1, RiskScore: 10, Fixable: &starboardv1alpha1.FixableSummary{}},
+		}, merged.EcosystemSummary)
+	})
+
+	t.Run("Should recompute risk score with specified weights", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.riskScoreWeights": "CRITICAL=20,MEDIUM=3"}
+		merged, err := trivy.MergeResultsWithWeights(newResult(openssl), newResult(jackson), config.GetRiskScoreWeights())
+		require.NoError(t, err)
+		assert.Equal(t, 23, merged.Summary.RiskScore)
+	})
+
+	t.Run("Should add up summaries of truncated result", func(t *testing.T) {
+		a := newResult(openssl)
+		a.Summary = starboardv1alpha1.VulnerabilitySummary{
+			MediumCount: 1,
+			LowCount:    2,
+			RiskScore:   4,
+			Fixable:     &starboardv1alpha1.FixableSummary{MediumCount: 1},
+		}
+		a.Truncated = true
+		a.DroppedCount = 2
+
+		merged, err := trivy.MergeResults(a, newResult(openssl, jackson))
+		require.NoError(t, err)
+		assert.Equal(t, starboardv1alpha1.VulnerabilitySummary{
+			CriticalCount: 1,
+			MediumCount:   1,
+			LowCount:      2,
+			RiskScore:     14,
+			Fixable:       &starboardv1alpha1.FixableSummary{MediumCount: 1},
+		}, merged.Summary)
+		assert.Nil(t, merged.EcosystemSummary)
+		assert.True(t, merged.Truncated)
+		assert.Equal(t, 2, merged.DroppedCount)
+	})
+
+	t.Run("Should return error when artifacts are different", func(t *testing.T) {
+		other := newResult(jackson)
+		other.Artifact.Tag = "8.5"
+
+		_, err := trivy.MergeResults(newResult(openssl), other)
+		assert.EqualError(t, err, "cannot merge results of different artifacts: index.docker.io/library/tomcat:9.0 and index.docker.io/library/tomcat:8.5")
+	})
+}
@@ -0,0 +1,114 @@
+package trivy
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes of conversions counted by the conversions metric.
+const (
+	OutcomeSuccess    = "success"
+	OutcomeParseError = "parse-error"
+	OutcomeRefError   = "ref-error"
+)
+
+// WithRegisterer makes the Converter register its metrics with the specified
+// Registerer. By default metrics are disabled.
+func WithRegisterer(registerer prometheus.Registerer) Option {
+	return func(c *converter) {
+		c.registerer = registerer
+	}
+}
+
+// metrics holds Prometheus collectors observing conversions.
+type metrics struct {
+	conversions     *prometheus.CounterVec
+	vulnerabilities prometheus.Histogram
+	duration        prometheus.Histogram
+}
+
+// newMetrics registers collectors with the specified Registerer. Collectors
+// that are already registered, e.g. by another Converter, are reused.
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		conversions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "starboard",
+			Subsystem: "trivy",
+			Name:      "conversions_total",
+			Help:      "Number of conversions of Trivy reports by outcome.",
+		}, []string{"outcome"}),
+		vulnerabilities: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "starboard",
+			Subsystem: "trivy",
+			Name:      "report_vulnerabilities",
+			Help:      "Number of vulnerabilities per converted report.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "starboard",
+			Subsystem: "trivy",
+			Name:      "conversion_duration_seconds",
+			Help:      "Duration of conversions of Trivy reports.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+	conversions, err := register(registerer, m.conversions)
+	if err != nil {
+		return nil, err
+	}
+	vulnerabilities, err := register(registerer, m.vulnerabilities)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := register(registerer, m.duration)
+	if err != nil {
+		return nil, err
+	}
+	m.conversions = conversions.(*prometheus.CounterVec)
+	m.vulnerabilities = vulnerabilities.(prometheus.Histogram)
+	m.duration = duration.(prometheus.Histogram)
+	return m, nil
+}
+
+// register registers the specified collector, or returns the equal collector
+// that has already been registered.
+func register(registerer prometheus.Registerer, collector prometheus.Collector) (prometheus.Collector, error) {
+	err := registerer.Register(collector)
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector, nil
+	}
+	return collector, err
+}
+
+// observe records the outcome and the duration of a conversion that began at
+// the specified time.
+func (c *converter) observe(start time.Time, report *starboardv1alpha1.VulnerabilityScanResult, err *error) {
+	outcome := OutcomeSuccess
+	switch {
+	case errors.Is(*err, ErrInvalidImageRef):
+		outcome = OutcomeRefError
+	case *err != nil:
+		outcome = OutcomeParseError
+	case hasImageRefWarning(report.Warnings):
+		outcome = OutcomeRefError
+	}
+	c.metrics.conversions.WithLabelValues(outcome).Inc()
+	if *err == nil {
+		c.metrics.vulnerabilities.Observe(float64(report.Summary.Total()))
+	}
+	c.metrics.duration.Observe(c.clock.Now().Sub(start).Seconds())
+}
+
+func hasImageRefWarning(warnings []string) bool {
+	for _, warning := range warnings {
+		if strings.HasPrefix(warning, imageRefWarningPrefix) {
+			return true
+		}
+	}
+	return false
+}
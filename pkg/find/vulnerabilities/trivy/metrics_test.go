@@ -0,0 +1,74 @@
+package trivy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_Metrics(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	registry := prometheus.NewRegistry()
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock), trivy.WithRegisterer(registry))
+
+	_, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+	require.NoError(t, err)
+	_, err = converter.ConvertBytes(config, "alpine:3.10.2", []byte(sampleReportAsString))
+	require.NoError(t, err)
+	_, err = converter.Convert(config, "alpine:@@", strings.NewReader(sampleReportAsString))
+	require.NoError(t, err)
+	_, err = converter.Convert(config, "alpine:3.10.2", strings.NewReader(`[{"Target": 5}]`))
+	require.Error(t, err)
+
+	expected := `
+# HELP starboard_trivy_conversions_total Number of conversions of Trivy reports by outcome.
+# TYPE starboard_trivy_conversions_total counter
+starboard_trivy_conversions_total{outcome="parse-error"} 1
+starboard_trivy_conversions_total{outcome="ref-error"} 1
+starboard_trivy_conversions_total{outcome="success"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "starboard_trivy_conversions_total"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if histogram := metric.GetHistogram(); histogram != nil {
+				counts[family.GetName()] = histogram.GetSampleCount()
+			}
+		}
+	}
+	assert.Equal(t, map[string]uint64{
+		"starboard_trivy_report_vulnerabilities":      3,
+		"starboard_trivy_conversion_duration_seconds": 4,
+	}, counts)
+
+	t.Run("Should count rejected image reference as reference error", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		converter := trivy.NewConverter(trivy.WithRegisterer(registry), trivy.WithRejectInvalidImageRefs())
+		_, err := converter.Convert(config, "alpine:@@", strings.NewReader(sampleReportAsString))
+		require.Error(t, err)
+		assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP starboard_trivy_conversions_total Number of conversions of Trivy reports by outcome.
+# TYPE starboard_trivy_conversions_total counter
+starboard_trivy_conversions_total{outcome="ref-error"} 1
+`), "starboard_trivy_conversions_total"))
+	})
+
+	t.Run("Should reuse metrics registered by another converter", func(t *testing.T) {
+		another := trivy.NewConverter(trivy.WithRegisterer(registry))
+		_, err := another.Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(strings.Replace(expected,
+			`{outcome="success"} 2`, `{outcome="success"} 3`, 1)), "starboard_trivy_conversions_total"))
+	})
+}
@@ -1,22 +1,330 @@
 package trivy
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	sec "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
 )
 
+// Report is the JSON model of the schema-versioned output of newer Trivy
+// releases, which wraps the array of scan reports in the Results field.
+type Report struct {
+	SchemaVersion int          `json:"SchemaVersion"`
+	ArtifactName  string       `json:"ArtifactName"`
+	ArtifactType  string       `json:"ArtifactType"`
+	Metadata      Metadata     `json:"Metadata"`
+	Results       []ScanReport `json:"Results"`
+
+	// raw is the JSON output the Report was decoded from, if the Converter
+	// retains it.
+	raw []byte
+}
+
+// Metadata is the JSON model of the metadata of the scanned artifact.
+type Metadata struct {
+	// OS is the operating system of the scanned image. It's nil for images
+	// without an operating system, e.g. built from scratch.
+	OS *OS `json:"OS,omitempty"`
+	// ImageConfig is the configuration of the scanned image. It's empty for
+	// artifacts other than images.
+	ImageConfig ImageConfig `json:"ImageConfig"`
+}
+
+// ImageConfig is the JSON model of the configuration of the scanned image.
+type ImageConfig struct {
+	// Architecture is the CPU architecture of the image, e.g. amd64.
+	Architecture string `json:"architecture"`
+}
+
+// OS is the JSON model of the operating system detected by Trivy.
+type OS struct {
+	// Family of the operating system, e.g. debian or alpine.
+	Family string `json:"Family"`
+	// Name is the version of the operating system, e.g. 10.4.
+	Name string `json:"Name"`
+}
+
+// GetFamily returns the family of the operating system, or an empty string if
+// the operating system is nil.
+func (os *OS) GetFamily() string {
+	if os == nil {
+		return ""
+	}
+	return os.Family
+}
+
+// GetName returns the version of the operating system, or an empty string if
+// the operating system is nil.
+func (os *OS) GetName() string {
+	if os == nil {
+		return ""
+	}
+	return os.Name
+}
+
+// ScanReport is the JSON model of a single element of the array output by
+// Trivy, which holds vulnerabilities detected in a scan target such as OS
+// packages of an image or a language-specific lock file.
 type ScanReport struct {
-	Target          string          `json:"Target"`
+	// Target is the name of the scanned target, e.g. "alpine:3.10.2 (alpine 3.10.2)".
+	Target string `json:"Target"`
+	// Class is the class of the result, e.g. os-pkgs, lang-pkgs, secret or
+	// config. It's empty in the output of older Trivy releases.
+	Class string `json:"Class,omitempty"`
+	// Type is the type of the Target, e.g. alpine or npm.
+	Type string `json:"Type,omitempty"`
+	// Vulnerabilities detected in the Target.
 	Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+	// Secrets detected in the Target.
+	Secrets []Secret `json:"Secrets,omitempty"`
 }
 
+// Classes of results that do not report vulnerabilities.
+const (
+	ClassSecret = "secret"
+	ClassConfig = "config"
+)
+
+// Classes of results that report vulnerabilities of packages.
+const (
+	ClassOSPackages   = "os-pkgs"
+	ClassLangPackages = "lang-pkgs"
+)
+
+// ArtifactTypeContainerImage is the ArtifactType of the Report of a scan of
+// a container image.
+const ArtifactTypeContainerImage = "container_image"
+
+// osTypes are the Types of targets of OS packages reported by Trivy releases
+// that do not report the Class of a target.
+var osTypes = map[string]bool{
+	"alma":                         true,
+	"alpine":                       true,
+	"amazon":                       true,
+	"cbl-mariner":                  true,
+	"centos":                       true,
+	"chainguard":                   true,
+	"debian":                       true,
+	"fedora":                       true,
+	"opensuse.leap":                true,
+	"oracle":                       true,
+	"photon":                       true,
+	"redhat":                       true,
+	"rocky":                        true,
+	"suse linux enterprise server": true,
+	"ubuntu":                       true,
+	"wolfi":                        true,
+}
+
+// Secret is the JSON model of a secret detected by Trivy.
+type Secret struct {
+	RuleID    string       `json:"RuleID"`
+	Category  string       `json:"Category"`
+	Severity  sec.Severity `json:"Severity"`
+	Title     string       `json:"Title"`
+	StartLine int          `json:"StartLine"`
+	EndLine   int          `json:"EndLine"`
+	// Match is the redacted line that contains the secret.
+	Match string `json:"Match,omitempty"`
+}
+
+// Vulnerability is the JSON model of a vulnerability detected by Trivy.
 type Vulnerability struct {
-	VulnerabilityID  string       `json:"VulnerabilityID"`
-	PkgName          string       `json:"PkgName"`
-	InstalledVersion string       `json:"InstalledVersion"`
-	FixedVersion     string       `json:"FixedVersion"`
-	Title            string       `json:"Title"`
-	Description      string       `json:"Description"`
-	Severity         sec.Severity `json:"Severity"`
-	LayerID          string       `json:"LayerID"`
-	References       []string     `json:"References"`
+	// VulnerabilityID is the identifier of the vulnerability, e.g. CVE-2019-1549.
+	VulnerabilityID string `json:"VulnerabilityID"`
+	// VendorIDs are the identifiers assigned to the vulnerability by vendors,
+	// e.g. DSA-4539-1.
+	VendorIDs []string `json:"VendorIDs,omitempty"`
+	// PkgName is the name of the vulnerable package.
+	PkgName string `json:"PkgName"`
+	// PkgPath is the path to the file that contains an application dependency,
+	// e.g. a JAR file. It's empty for OS packages.
+	PkgPath string `json:"PkgPath,omitempty"`
+	// PkgIdentifier identifies the vulnerable package, e.g. by its Package URL.
+	PkgIdentifier *PkgIdentifier `json:"PkgIdentifier,omitempty"`
+	// InstalledVersion is the version of the vulnerable package.
+	InstalledVersion string `json:"InstalledVersion"`
+	// FixedVersion is the version of the package that fixes the vulnerability.
+	FixedVersion string `json:"FixedVersion"`
+	Title        string `json:"Title"`
+	Description  string `json:"Description"`
+	// Severity of the vulnerability as rated by the SeveritySource.
+	Severity sec.Severity `json:"Severity"`
+	// SeveritySource is the name of the vulnerability database that the
+	// Severity comes from, e.g. nvd.
+	SeveritySource string `json:"SeveritySource,omitempty"`
+	// VendorSeverity holds the severity of the vulnerability as rated by each
+	// vendor, keyed by the name of the vendor, e.g. nvd or redhat.
+	VendorSeverity VendorSeverity `json:"VendorSeverity,omitempty"`
+	// Status of the vulnerability in the distribution of the package, e.g.
+	// fixed, affected or will_not_fix.
+	Status  string `json:"Status,omitempty"`
+	LayerID string `json:"LayerID"`
+	// Layer is the image layer that introduced the vulnerable package.
+	Layer Layer `json:"Layer"`
+	// PrimaryURL is the URL of the authoritative advisory.
+	PrimaryURL string   `json:"PrimaryURL"`
+	References []string `json:"References"`
+	// CVSS holds CVSS data keyed by the name of the vendor, e.g. nvd or redhat.
+	CVSS map[string]CVSS `json:"CVSS"`
+	// CweIDs are the identifiers of weaknesses, e.g. CWE-79.
+	CweIDs []string `json:"CweIDs"`
+	// PublishedDate is the RFC3339 timestamp of the publication of the
+	// vulnerability.
+	PublishedDate string `json:"PublishedDate,omitempty"`
+	// LastModifiedDate is the RFC3339 timestamp of the last modification of
+	// the vulnerability.
+	LastModifiedDate string `json:"LastModifiedDate,omitempty"`
+	// DataSource is the source of the advisory of the vulnerability.
+	DataSource *DataSource `json:"DataSource,omitempty"`
+}
+
+// VendorSeverity is the JSON model of the severities of a vulnerability keyed
+// by the name of the vendor. Trivy encodes each severity as a number, from 0
+// for UNKNOWN to 4 for CRITICAL, but the name of the severity is accepted too.
+type VendorSeverity map[string]sec.Severity
+
+// UnmarshalJSON decodes severities encoded either as numbers or as names. A
+// number out of range is decoded as UNKNOWN.
+func (vs *VendorSeverity) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*vs = nil
+		return nil
+	}
+	severities := make(VendorSeverity, len(raw))
+	for vendor, value := range raw {
+		var rank int
+		if err := json.Unmarshal(value, &rank); err == nil {
+			severities[vendor] = severityOfRank(rank)
+			continue
+		}
+		var name string
+		if err := json.Unmarshal(value, &name); err != nil {
+			return fmt.Errorf("decoding severity of vendor %s: %w", vendor, err)
+		}
+		severities[vendor] = sec.Severity(strings.ToUpper(name))
+	}
+	*vs = severities
+	return nil
+}
+
+// MarshalJSON encodes severities as numbers like Trivy does. Severities that
+// Trivy does not rank are encoded as UNKNOWN.
+func (vs VendorSeverity) MarshalJSON() ([]byte, error) {
+	if vs == nil {
+		return []byte("null"), nil
+	}
+	ranks := make(map[string]int, len(vs))
+	for vendor, severity := range vs {
+		ranks[vendor] = trivySeverityNumber(severity)
+	}
+	return json.Marshal(ranks)
+}
+
+// trivySeverities are the severities reported by Trivy, indexed by the numbers
+// that encode them in the JSON output, e.g. in VendorSeverity. Those numbers
+// are specific to Trivy, unlike Severity.Rank.
+var trivySeverities = []sec.Severity{
+	sec.SeverityUnknown,
+	sec.SeverityLow,
+	sec.SeverityMedium,
+	sec.SeverityHigh,
+	sec.SeverityCritical,
+}
+
+// severityOfRank returns the severity encoded by the specified number in the
+// JSON output of Trivy, or UNKNOWN if there is no such severity.
+func severityOfRank(rank int) sec.Severity {
+	if rank < 0 || rank >= len(trivySeverities) {
+		return sec.SeverityUnknown
+	}
+	return trivySeverities[rank]
+}
+
+// trivySeverityNumber returns the number that encodes the specified severity
+// in the JSON output of Trivy. A severity that Trivy does not report is
+// encoded as UNKNOWN.
+func trivySeverityNumber(severity sec.Severity) int {
+	for number, s := range trivySeverities {
+		if s == severity {
+			return number
+		}
+	}
+	return 0
+}
+
+// isTrivySeverity checks whether the specified severity is one of the
+// severities reported by Trivy. NONE is not.
+func isTrivySeverity(severity sec.Severity) bool {
+	for _, s := range trivySeverities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// PkgIdentifier is the JSON model of the identifiers of a package.
+type PkgIdentifier struct {
+	// PURL is the Package URL of the package, e.g.
+	// pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1.
+	PURL string `json:"PURL,omitempty"`
+}
+
+// DataSource is the JSON model of the source of an advisory.
+type DataSource struct {
+	ID   string `json:"ID"`
+	Name string `json:"Name"`
+	URL  string `json:"URL"`
+}
+
+// Layer identifies the image layer that introduced a vulnerable package.
+type Layer struct {
+	Digest string `json:"Digest"`
+	DiffID string `json:"DiffID"`
+}
+
+// CVSS holds the CVSS v2 and v3 data reported by a single vendor.
+type CVSS struct {
+	V2Vector string  `json:"V2Vector"`
+	V3Vector string  `json:"V3Vector"`
+	V2Score  float64 `json:"V2Score"`
+	V3Score  float64 `json:"V3Score"`
+}
+
+// CycloneDXBOM is the JSON model of a CycloneDX SBOM. Only the fields used by
+// SbomConverter are modeled.
+type CycloneDXBOM struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Components  []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent is the JSON model of a component of a CycloneDX SBOM.
+type CycloneDXComponent struct {
+	Type     string                   `json:"type"`
+	Name     string                   `json:"name"`
+	Version  string                   `json:"version"`
+	PURL     string                   `json:"purl"`
+	Licenses []CycloneDXLicenseChoice `json:"licenses"`
+}
+
+// CycloneDXLicenseChoice is the JSON model of a license of a CycloneDX
+// component, which is either a license or an SPDX license expression.
+type CycloneDXLicenseChoice struct {
+	License    *CycloneDXLicense `json:"license,omitempty"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+// CycloneDXLicense is the JSON model of a license identified by its SPDX
+// identifier or by its name.
+type CycloneDXLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
 }
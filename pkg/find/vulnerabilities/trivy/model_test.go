@@ -1 +1,62 @@
 package trivy_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanReport_Unmarshal(t *testing.T) {
+	input := `[
+	{
+		"Target": "debian:10.4 (debian 10.4)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-18276",
+			"VendorIDs": ["DSA-4539-1"],
+			"PkgName": "bash",
+			"InstalledVersion": "5.0-4",
+			"Severity": "LOW",
+			"SeveritySource": "debian",
+			"Layer": {
+				"DiffID": "sha256:d0f104dc0a1f9c744b65b23b3fd4d4d3236b4656e67f776fe13f8ad8423b955c"
+			},
+			"CVSS": {
+				"nvd": {"V2Vector": "AV:L/AC:L/Au:N/C:C/I:C/A:C", "V2Score": 7.2}
+			},
+			"CweIDs": ["CWE-273"]
+		}
+		]
+	}
+]`
+
+	var reports []trivy.ScanReport
+	err := json.Unmarshal([]byte(input), &reports)
+	require.NoError(t, err)
+	assert.Equal(t, []trivy.ScanReport{
+		{
+			Target: "debian:10.4 (debian 10.4)",
+			Vulnerabilities: []trivy.Vulnerability{
+				{
+					VulnerabilityID:  "CVE-2019-18276",
+					VendorIDs:        []string{"DSA-4539-1"},
+					PkgName:          "bash",
+					InstalledVersion: "5.0-4",
+					Severity:         starboardv1alpha1.SeverityLow,
+					SeveritySource:   "debian",
+					Layer: trivy.Layer{
+						DiffID: "sha256:d0f104dc0a1f9c744b65b23b3fd4d4d3236b4656e67f776fe13f8ad8423b955c",
+					},
+					CVSS: map[string]trivy.CVSS{
+						"nvd": {V2Vector: "AV:L/AC:L/Au:N/C:C/I:C/A:C", V2Score: 7.2},
+					},
+					CweIDs: []string{"CWE-273"},
+				},
+			},
+		},
+	}, reports)
+}
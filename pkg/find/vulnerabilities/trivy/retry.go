@@ -0,0 +1,65 @@
+package trivy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultRetryBackoff is the backoff of ConvertWithRetry suitable for a reader
+// of the output that is still being written.
+var DefaultRetryBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: 100 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// ConvertWithRetry converts the output of Trivy read from the specified reader
+// with the given Converter, and retries the conversion with the specified
+// backoff if it fails because of a transient error. The number of attempts is
+// limited by the Steps of the backoff.
+//
+// The output is buffered, so that each attempt converts the whole output read
+// so far. An attempt resumes reading where the previous one stopped, which
+// allows converting the output of a process that has not finished writing it
+// when the first attempt is made.
+//
+// I/O errors, errors of decoding truncated JSON and ErrEmptyScanOutput are
+// considered transient. Other errors, such as ScanError or MalformedOutputError
+// of a syntax error, are returned without retrying.
+func ConvertWithRetry(ctx context.Context, converter Converter, config Config, imageRef string, reader io.Reader, backoff wait.Backoff) (starboardv1alpha1.VulnerabilityScanResult, error) {
+	var buffer bytes.Buffer
+	for {
+		_, err := buffer.ReadFrom(reader)
+		if err == nil {
+			var result starboardv1alpha1.VulnerabilityScanResult
+			result, err = converter.ConvertBytes(config, imageRef, buffer.Bytes())
+			if err == nil || !isTransient(err) {
+				return result, err
+			}
+		}
+		if backoff.Steps <= 1 {
+			return starboardv1alpha1.VulnerabilityScanResult{}, err
+		}
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return starboardv1alpha1.VulnerabilityScanResult{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransient checks whether the specified error of converting the output of
+// Trivy may not recur once the whole output is available, i.e. whether the
+// output is truncated. Malformed output, e.g. a syntax error, is permanent.
+func isTransient(err error) bool {
+	return errors.Is(err, ErrEmptyScanOutput) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,103 @@
+package trivy_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// flakyReader is an io.Reader that returns consecutive chunks of data in
+// consecutive passes, where each pass ends with the specified error.
+type flakyReader struct {
+	chunks []string
+	err    error
+	reads  int
+}
+
+func (r *flakyReader) Read(p []byte) (int, error) {
+	r.reads++
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	if n < len(r.chunks[0]) {
+		r.chunks[0] = r.chunks[0][n:]
+		return n, nil
+	}
+	r.chunks = r.chunks[1:]
+	if len(r.chunks) == 0 {
+		return n, io.EOF
+	}
+	return n, r.err
+}
+
+func TestConvertWithRetry(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond}
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+	half := len(sampleReportAsString) / 2
+
+	t.Run("Should retry when reading fails", func(t *testing.T) {
+		reader := &flakyReader{
+			chunks: []string{"", sampleReportAsString},
+			err:    errors.New("connection reset by peer"),
+		}
+		report, err := trivy.ConvertWithRetry(context.Background(), converter, config, "alpine:3.10.2", reader, backoff)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should retry when output is truncated", func(t *testing.T) {
+		reader := &flakyReader{
+			chunks: []string{sampleReportAsString[:half], sampleReportAsString[half:]},
+			err:    io.EOF,
+		}
+		report, err := trivy.ConvertWithRetry(context.Background(), converter, config, "alpine:3.10.2", reader, backoff)
+		require.NoError(t, err)
+		assert.Equal(t, sampleReport, report)
+	})
+
+	t.Run("Should return error when attempts are exhausted", func(t *testing.T) {
+		reader := strings.NewReader(sampleReportAsString[:half])
+		_, err := trivy.ConvertWithRetry(context.Background(), converter, config, "alpine:3.10.2", reader, backoff)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "expected unexpected EOF but got: %v", err)
+	})
+
+	t.Run("Should not retry when scan failed", func(t *testing.T) {
+		reader := &flakyReader{
+			chunks: []string{"2020-06-17T23:37:46.102+0200	FATAL	failed to download vulnerability DB\n[]"},
+		}
+		_, err := trivy.ConvertWithRetry(context.Background(), converter, config, "alpine:3.10.2", reader, backoff)
+		var scanErr *trivy.ScanError
+		require.True(t, errors.As(err, &scanErr), "expected ScanError but got: %v", err)
+		assert.Equal(t, 1, reader.reads)
+	})
+
+	t.Run("Should not retry when output is malformed", func(t *testing.T) {
+		reader := &flakyReader{
+			chunks: []string{`[{"Target": "alpine:3.10.2 (alpine 3.10.2)",, "Vulnerabilities": []}]`},
+		}
+		_, err := trivy.ConvertWithRetry(context.Background(), converter, config, "alpine:3.10.2", reader, backoff)
+		require.True(t, errors.Is(err, trivy.ErrMalformedOutput), "expected malformed output but got: %v", err)
+		assert.Equal(t, 1, reader.reads)
+	})
+
+	t.Run("Should return error when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reader := strings.NewReader(sampleReportAsString[:half])
+		_, err := trivy.ConvertWithRetry(ctx, converter, config, "alpine:3.10.2", reader, wait.Backoff{Steps: 3, Duration: time.Hour})
+		assert.Equal(t, context.Canceled, err)
+	})
+}
@@ -0,0 +1,100 @@
+package trivy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+// SbomReport is the package inventory of a scanned image.
+type SbomReport struct {
+	Registry   starboardv1alpha1.Registry
+	Artifact   starboardv1alpha1.Artifact
+	Components []SbomComponent
+	// Warnings describe non-fatal issues encountered while the report was
+	// produced, e.g. an image reference that could not be parsed.
+	Warnings []string
+}
+
+// SbomComponent is a package listed in an SbomReport.
+type SbomComponent struct {
+	Name    string
+	Version string
+	PURL    string
+	// Licenses are SPDX license identifiers, names or expressions of the
+	// package.
+	Licenses []string
+}
+
+// SbomConverter is the interface that wraps the Convert method.
+//
+// Convert converts the CycloneDX SBOM, e.g. the output of Trivy with the
+// cyclonedx format, of the image with the specified reference into an
+// SbomReport.
+type SbomConverter interface {
+	Convert(config Config, imageRef string, reader io.Reader) (SbomReport, error)
+}
+
+type sbomConverter struct {
+	*converter
+}
+
+// NewSbomConverter constructs a new SbomConverter with the specified options.
+func NewSbomConverter(opts ...Option) SbomConverter {
+	return &sbomConverter{converter: NewConverter(opts...).(*converter)}
+}
+
+// cycloneDXFormat is the value of the bomFormat field of CycloneDX documents.
+const cycloneDXFormat = "CycloneDX"
+
+func (c *sbomConverter) Convert(config Config, imageRef string, reader io.Reader) (SbomReport, error) {
+	var bom CycloneDXBOM
+	if err := json.NewDecoder(reader).Decode(&bom); err != nil {
+		return SbomReport{}, fmt.Errorf("decoding CycloneDX SBOM: %w", err)
+	}
+	if bom.BOMFormat != cycloneDXFormat {
+		return SbomReport{}, fmt.Errorf("unsupported SBOM format: %q", bom.BOMFormat)
+	}
+	c.logger.V(1).Info("Decoded CycloneDX SBOM", "specVersion", bom.SpecVersion, "components", len(bom.Components))
+
+	components := make([]SbomComponent, 0, len(bom.Components))
+	for _, component := range bom.Components {
+		components = append(components, SbomComponent{
+			Name:     component.Name,
+			Version:  component.Version,
+			PURL:     component.PURL,
+			Licenses: c.toLicenses(component.Licenses),
+		})
+	}
+	registry, artifact, warnings, err := c.resolveImage(config, imageRef)
+	if err != nil {
+		return SbomReport{}, err
+	}
+	return SbomReport{
+		Registry:   registry,
+		Artifact:   artifact,
+		Components: components,
+		Warnings:   warnings,
+	}, nil
+}
+
+// toLicenses returns the identifiers, names or expressions of the specified
+// licenses, in this order of preference for each license.
+func (c *sbomConverter) toLicenses(licenses []CycloneDXLicenseChoice) []string {
+	result := make([]string, 0, len(licenses))
+	for _, choice := range licenses {
+		switch {
+		case choice.Expression != "":
+			result = append(result, choice.Expression)
+		case choice.License == nil:
+			continue
+		case choice.License.ID != "":
+			result = append(result, choice.License.ID)
+		case choice.License.Name != "":
+			result = append(result, choice.License.Name)
+		}
+	}
+	return result
+}
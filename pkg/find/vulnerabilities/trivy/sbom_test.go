@@ -0,0 +1,78 @@
+package trivy_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSbomConverter_Convert(t *testing.T) {
+	config := starboard.ConfigData{}
+	converter := trivy.NewSbomConverter()
+
+	t.Run("Should convert CycloneDX SBOM", func(t *testing.T) {
+		file, err := os.Open("testdata/alpine-3.10.2.cdx.json")
+		require.NoError(t, err)
+		defer func() {
+			_ = file.Close()
+		}()
+
+		report, err := converter.Convert(config, "alpine:3.10.2", file)
+		require.NoError(t, err)
+		assert.Equal(t, trivy.SbomReport{
+			Registry: starboardv1alpha1.Registry{
+				Server:            "index.docker.io",
+				IsDefaultRegistry: true,
+			},
+			Artifact: starboardv1alpha1.Artifact{
+				Repository: "library/alpine",
+				Tag:        "3.10.2",
+			},
+			Components: []trivy.SbomComponent{
+				{
+					Name:     "musl",
+					Version:  "1.1.22-r3",
+					PURL:     "pkg:apk/alpine/musl@1.1.22-r3?distro=3.10.2",
+					Licenses: []string{"MIT"},
+				},
+				{
+					Name:     "openssl",
+					Version:  "1.1.1c-r0",
+					PURL:     "pkg:apk/alpine/openssl@1.1.1c-r0?distro=3.10.2",
+					Licenses: []string{"OpenSSL"},
+				},
+				{
+					Name:     "busybox",
+					Version:  "1.30.1-r2",
+					PURL:     "pkg:apk/alpine/busybox@1.30.1-r2?distro=3.10.2",
+					Licenses: []string{"GPL-2.0-only"},
+				},
+				{
+					Name:     "alpine-baselayout",
+					Version:  "3.1.2-r0",
+					PURL:     "pkg:apk/alpine/alpine-baselayout@3.1.2-r0?distro=3.10.2",
+					Licenses: []string{},
+				},
+			},
+		}, report)
+	})
+
+	t.Run("Should report malformed image reference as warning", func(t *testing.T) {
+		report, err := converter.Convert(config, "alpine:3.10.2:x", strings.NewReader(`{"bomFormat":"CycloneDX","components":[]}`))
+		require.NoError(t, err)
+		assert.Equal(t, starboardv1alpha1.Artifact{Repository: "alpine:3.10.2:x"}, report.Artifact)
+		assert.Len(t, report.Warnings, 1)
+		assert.Empty(t, report.Components)
+	})
+
+	t.Run("Should return error when document is not CycloneDX", func(t *testing.T) {
+		_, err := converter.Convert(config, "alpine:3.10.2", strings.NewReader(`{"spdxVersion":"SPDX-2.2"}`))
+		require.EqualError(t, err, `unsupported SBOM format: ""`)
+	})
+}
@@ -0,0 +1,46 @@
+package trivy
+
+import (
+	"time"
+
+	"github.com/aquasecurity/starboard/pkg/kube"
+)
+
+// WithScanTimes returns a Config that extends the specified Config with the
+// times when a scan started and completed. The Converter derives the update
+// timestamp and the scan duration of a VulnerabilityScanResult from them.
+func WithScanTimes(config Config, startTime, completionTime time.Time) Config {
+	sc := newScanConfig(config)
+	sc.startTime = startTime
+	sc.completionTime = completionTime
+	return sc
+}
+
+// WithWorkload returns a Config that extends the specified Config with the
+// Kubernetes workload that runs the scanned image. The Converter copies it to
+// a VulnerabilityScanResult, so that the result is self-describing when it's
+// exported outside the cluster.
+func WithWorkload(config Config, workload kube.Object) Config {
+	sc := newScanConfig(config)
+	sc.workload = workload
+	return sc
+}
+
+// scanConfig is a Config that carries details of a particular scan, which are
+// not part of the Starboard configuration.
+type scanConfig struct {
+	Config
+	startTime      time.Time
+	completionTime time.Time
+	workload       kube.Object
+}
+
+// newScanConfig returns a copy of the specified Config if it's already
+// a scanConfig, or wraps it otherwise.
+func newScanConfig(config Config) *scanConfig {
+	if sc, ok := config.(*scanConfig); ok {
+		copied := *sc
+		return &copied
+	}
+	return &scanConfig{Config: config}
+}
@@ -31,6 +31,24 @@ import (
 
 type Config interface {
 	GetTrivyImageRef() string
+	GetSeverityThreshold() string
+	GetFailOnSeverity() string
+	GetDockerHubRegistry() string
+	GetMaxVulnerabilities() int
+	GetMaxLinks() int
+	GetIgnoreUnfixed() bool
+	GetIgnorePolicy() starboard.IgnorePolicy
+	GetSeverityOverrides() starboard.SeverityOverrides
+	GetIncludePackages() []string
+	GetExcludePackages() []string
+	GetIncludedFields() map[string]bool
+	GetStrictImageRefValidation() bool
+	GetMinScore() float64
+	GetMaxScanOutputSize() int64
+	GetDropUnscored() bool
+	GetRiskScoreWeights() map[sec.Severity]int
+	GetScannerName() string
+	GetScannerVendor() string
 }
 
 // NewScanner constructs a new vulnerability Scanner with the specified options and Kubernetes client Interface.
@@ -368,6 +386,14 @@ func (s *Scanner) GetVulnerabilityReportsByScanJob(ctx context.Context, job *bat
 		return nil, fmt.Errorf("reading scan job annotation: %s: %w", kube.AnnotationContainerImages, err)
 	}
 
+	config := s.config
+	if job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+		config = WithScanTimes(s.config, job.Status.StartTime.Time, job.Status.CompletionTime.Time)
+	}
+	if workload, err := kube.ObjectFromLabelsSet(job.Labels); err == nil {
+		config = WithWorkload(config, workload)
+	}
+
 	for _, c := range job.Spec.Template.Spec.Containers {
 		klog.V(3).Infof("Getting logs for %s container in job: %s/%s", c.Name, job.Namespace, job.Name)
 		var logReader io.ReadCloser
@@ -375,7 +401,11 @@ func (s *Scanner) GetVulnerabilityReportsByScanJob(ctx context.Context, job *bat
 		if err != nil {
 			return nil, err
 		}
-		result, err := s.converter.Convert(s.config, containerImages[c.Name], logReader)
+		result, err := s.converter.ConvertWithContext(ctx, config, containerImages[c.Name], logReader)
+		_ = logReader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("converting scan output of container %s: %w", c.Name, err)
+		}
 
 		report, err := vulnerabilityreport.NewBuilder(s.scheme).
 			Owner(owner).
@@ -387,8 +417,6 @@ func (s *Scanner) GetVulnerabilityReportsByScanJob(ctx context.Context, job *bat
 		}
 
 		reports = append(reports, report)
-
-		_ = logReader.Close()
 	}
 	return reports, nil
 }
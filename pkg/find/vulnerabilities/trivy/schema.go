@@ -0,0 +1,255 @@
+package trivy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+var (
+	timeType     = reflect.TypeOf(metav1.Time{})
+	durationType = reflect.TypeOf(metav1.Duration{})
+	severityType = reflect.TypeOf(starboardv1alpha1.Severity(""))
+	bytesType    = reflect.TypeOf([]byte(nil))
+)
+
+// severityEnum are the severity levels allowed by the schema, from the most
+// to the least severe.
+var severityEnum = []starboardv1alpha1.Severity{
+	starboardv1alpha1.SeverityCritical,
+	starboardv1alpha1.SeverityHigh,
+	starboardv1alpha1.SeverityMedium,
+	starboardv1alpha1.SeverityLow,
+	starboardv1alpha1.SeverityUnknown,
+	starboardv1alpha1.SeverityNone,
+}
+
+// resultSchema is generated once, because the schema of a type never changes.
+var resultSchema = schemaOf(reflect.TypeOf(starboardv1alpha1.VulnerabilityScanResult{}))
+
+// VulnerabilityScanResultSchema returns the schema of the JSON encoding of
+// VulnerabilityScanResult. It's an OpenAPI v3 schema, i.e. the dialect of JSON
+// Schema used by custom resource definitions, which is generated from the Go
+// type, so that it cannot drift from the encoding.
+//
+// Fields that are not omitted when empty are required. Unknown fields are not
+// allowed. Lists, maps, and optional objects may be null, which is how nil
+// values are encoded.
+func VulnerabilityScanResultSchema() extv1beta1.JSONSchemaProps {
+	return *resultSchema.DeepCopy()
+}
+
+// ValidateJSON checks the specified JSON encoding of a VulnerabilityScanResult
+// against the schema returned by VulnerabilityScanResultSchema. It returns an
+// aggregate of all problems found, e.g. a missing required field or a value
+// of the wrong type, or nil if the encoding conforms to the schema.
+func ValidateJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("decoding scan result: %w", err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("decoding scan result: unexpected data after the JSON value")
+	}
+	return utilerrors.NewAggregate(validateValue(nil, "", resultSchema, value))
+}
+
+func schemaOf(t reflect.Type) extv1beta1.JSONSchemaProps {
+	switch t {
+	case timeType:
+		// A zero time is encoded as null.
+		return extv1beta1.JSONSchemaProps{Type: "string", Format: "date-time", Nullable: true}
+	case durationType:
+		return extv1beta1.JSONSchemaProps{Type: "string"}
+	case severityType:
+		enum := make([]extv1beta1.JSON, len(severityEnum))
+		for i, severity := range severityEnum {
+			enum[i] = extv1beta1.JSON{Raw: []byte(fmt.Sprintf("%q", severity))}
+		}
+		return extv1beta1.JSONSchemaProps{Type: "string", Enum: enum}
+	case bytesType:
+		return extv1beta1.JSONSchemaProps{Type: "string", Format: "byte", Nullable: true}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaOf(t.Elem())
+		schema.Nullable = true
+		return schema
+	case reflect.Struct:
+		schema := extv1beta1.JSONSchemaProps{
+			Type:                 "object",
+			Properties:           make(map[string]extv1beta1.JSONSchemaProps),
+			AdditionalProperties: &extv1beta1.JSONSchemaPropsOrBool{Allows: false},
+		}
+		addFieldSchemas(&schema, t)
+		return schema
+	case reflect.Slice, reflect.Array:
+		items := schemaOf(t.Elem())
+		return extv1beta1.JSONSchemaProps{
+			Type:     "array",
+			Items:    &extv1beta1.JSONSchemaPropsOrArray{Schema: &items},
+			Nullable: t.Kind() == reflect.Slice,
+		}
+	case reflect.Map:
+		values := schemaOf(t.Elem())
+		return extv1beta1.JSONSchemaProps{
+			Type:                 "object",
+			AdditionalProperties: &extv1beta1.JSONSchemaPropsOrBool{Allows: true, Schema: &values},
+			Nullable:             true,
+		}
+	case reflect.String:
+		return extv1beta1.JSONSchemaProps{Type: "string"}
+	case reflect.Bool:
+		return extv1beta1.JSONSchemaProps{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return extv1beta1.JSONSchemaProps{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return extv1beta1.JSONSchemaProps{Type: "number"}
+	default:
+		// Any value is allowed for types without a fixed encoding.
+		return extv1beta1.JSONSchemaProps{}
+	}
+}
+
+// addFieldSchemas adds the schemas of the encoded fields of the specified struct
+// type to the properties of the given schema. Fields of embedded structs without
+// a JSON name are inlined.
+func addFieldSchemas(schema *extv1beta1.JSONSchemaProps, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, options = tag[:comma], tag[comma:]
+		}
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addFieldSchemas(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = schemaOf(field.Type)
+		if !strings.Contains(options, ",omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// validateValue appends problems of the specified value decoded from JSON,
+// which does not conform to the given schema, to the given errors. The path
+// locates the value in the encoded result.
+func validateValue(errs []error, path string, schema extv1beta1.JSONSchemaProps, value interface{}) []error {
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return errs
+		}
+		return append(errs, fmt.Errorf("%s must not be null", pathOrRoot(path)))
+	}
+	if schema.Type != "" && typeOf(value) != schema.Type &&
+		!(schema.Type == "number" && typeOf(value) == "integer") {
+		return append(errs, fmt.Errorf("%s must be of type %s: got %s", pathOrRoot(path), schema.Type, typeOf(value)))
+	}
+	if len(schema.Enum) > 0 && !isEnumValue(schema.Enum, value) {
+		errs = append(errs, fmt.Errorf("%s is not a supported value: %v", pathOrRoot(path), value))
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := value[name]; !ok {
+				errs = append(errs, fmt.Errorf("%s is required", joinPath(path, name)))
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok {
+				errs = validateValue(errs, joinPath(path, name), property, value[name])
+				continue
+			}
+			additional := schema.AdditionalProperties
+			switch {
+			case additional == nil:
+			case additional.Schema != nil:
+				errs = validateValue(errs, joinPath(path, name), *additional.Schema, value[name])
+			case !additional.Allows:
+				errs = append(errs, fmt.Errorf("%s is not a known field", joinPath(path, name)))
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range value {
+				errs = validateValue(errs, fmt.Sprintf("%s[%d]", path, i), *schema.Items.Schema, item)
+			}
+		}
+	}
+	return errs
+}
+
+// typeOf returns the JSON Schema type of the specified value decoded from JSON
+// with numbers decoded as json.Number.
+func typeOf(value interface{}) string {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	default:
+		return "null"
+	}
+}
+
+func isEnumValue(enum []extv1beta1.JSON, value interface{}) bool {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range enum {
+		if bytes.Equal(allowed.Raw, encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "scan result"
+	}
+	return path
+}
@@ -0,0 +1,179 @@
+package trivy_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestVulnerabilityScanResultSchema(t *testing.T) {
+	schema := trivy.VulnerabilityScanResultSchema()
+	assert.Equal(t, "object", schema.Type)
+	assert.Contains(t, schema.Required, "scanner")
+	assert.Contains(t, schema.Required, "vulnerabilities")
+	assert.NotContains(t, schema.Required, "osFamily")
+	assert.NotContains(t, schema.Properties, "RawReport")
+
+	vulnerability := schema.Properties["vulnerabilities"].Items.Schema
+	assert.Equal(t, "string", vulnerability.Properties["severity"].Type)
+	assert.Len(t, vulnerability.Properties["severity"].Enum, 6)
+	assert.Equal(t, "number", vulnerability.Properties["score"].Type)
+	assert.True(t, vulnerability.Properties["score"].Nullable)
+
+	t.Run("Should return copy of schema", func(t *testing.T) {
+		schema.Properties["scanner"] = schema.Properties["registry"]
+		assert.Equal(t, []string{"name", "vendor", "version"}, trivy.VulnerabilityScanResultSchema().Properties["scanner"].Required)
+	})
+
+	t.Run("Should encode schema as JSON", func(t *testing.T) {
+		data, err := json.Marshal(schema)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"additionalProperties":false`)
+	})
+}
+
+func TestValidateJSON(t *testing.T) {
+	publishedDate := metav1.NewTime(time.Date(2019, 9, 10, 17, 15, 0, 0, time.UTC))
+
+	t.Run("Should accept encoding of result with all fields set", func(t *testing.T) {
+		// Keep setting all fields of the result, so that the test fails if
+		// the schema does not describe the encoding of a new field.
+		result := starboardv1alpha1.VulnerabilityScanResult{
+			Scanner:   starboardv1alpha1.Scanner{Name: "Trivy", Vendor: "Aqua Security", Version: "0.9.1"},
+			Registry:  starboardv1alpha1.Registry{Server: "index.docker.io", IsDefaultRegistry: true},
+			Artifact:  starboardv1alpha1.Artifact{Repository: "library/alpine", Digest: "sha256:abc", Tag: "3.10.2", MimeType: "application/vnd.oci.image.manifest.v1+json", Architecture: "amd64"},
+			OSFamily:  "alpine",
+			OSVersion: "3.10.2",
+			Summary: starboardv1alpha1.VulnerabilitySummary{
+				MediumCount: 1,
+				RiskScore:   2,
+				Fixable:     &starboardv1alpha1.FixableSummary{MediumCount: 1},
+			},
+			Vulnerabilities: []starboardv1alpha1.Vulnerability{
+				{
+					VulnerabilityID:  "CVE-2019-1549",
+					Resource:         "openssl",
+					PkgPath:          "lib/libssl.so",
+					PkgIdentifier:    &starboardv1alpha1.PkgIdentifier{PURL: "pkg:apk/alpine/openssl@1.1.1c-r0", GroupID: "g", ArtifactID: "a", Version: "1"},
+					InstalledVersion: "1.1.1c-r0",
+					FixedVersion:     "1.1.1d-r0",
+					FixedVersions:    []string{"1.1.1d-r0"},
+					Severity:         starboardv1alpha1.SeverityMedium,
+					VendorSeverity:   map[string]string{"nvd": "MEDIUM"},
+					Status:           starboardv1alpha1.VulnerabilityStatusFixed,
+					Title:            "openssl: information disclosure in fork()",
+					Description:      "OpenSSL 1.1.1 introduced a rewritten random number generator.",
+					Links:            []string{"https://www.openssl.org/news/secadv/20190910.txt"},
+					PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2019-1549",
+					Score:            pointer.Float64Ptr(5.3),
+					CVSSVector:       "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:N/A:N",
+					Target:           "alpine:3.10.2 (alpine 3.10.2)",
+					Layer:            &starboardv1alpha1.Layer{Digest: "sha256:def", DiffID: "sha256:012"},
+					CweIDs:           []string{"CWE-330"},
+					PublishedDate:    &publishedDate,
+					LastModifiedDate: &publishedDate,
+					DataSource:       starboardv1alpha1.DataSource{ID: "alpine", Name: "Alpine Secdb", URL: "https://secdb.alpinelinux.org/"},
+					ScannerVersion:   "0.9.1",
+					Aliases:          []string{"GHSA-xxxx-xxxx-xxxx"},
+					AffectedPackages: []string{"openssl", "libssl1.1"},
+					CategorizedLinks: &starboardv1alpha1.CategorizedLinks{
+						Advisory: []string{"https://www.openssl.org/news/secadv/20190910.txt"},
+						Patch:    []string{"https://github.com/openssl/openssl/commit/1b0fe00"},
+						Exploit:  []string{"https://www.exploit-db.com/exploits/1"},
+						Other:    []string{"https://example.com"},
+					},
+					Ecosystem: "alpine",
+				},
+			},
+			UpdateTimestamp: metav1.NewTime(fixedTime),
+			ScanDuration:    metav1.Duration{Duration: 3 * time.Second},
+			Truncated:       true,
+			DroppedCount:    1,
+			Warnings:        []string{"could not parse image reference"},
+			WorkloadKind:    "Deployment",
+			WorkloadName:    "nginx",
+			Namespace:       "default",
+			Secrets: []starboardv1alpha1.SecretFinding{
+				{RuleID: "aws-access-key-id", Category: "AWS", Severity: starboardv1alpha1.SeverityCritical, Title: "AWS Access Key ID", Target: "/app/config", StartLine: 1, EndLine: 1},
+			},
+			ScannedTargets: []string{"alpine:3.10.2 (alpine 3.10.2)"},
+			EcosystemSummary: map[string]starboardv1alpha1.VulnerabilitySummary{
+				"alpine": {MediumCount: 1},
+			},
+			FilterStats: &starboardv1alpha1.FilterStats{BelowThreshold: 1, BelowMinScore: 1, Ignored: 1, Unfixed: 1, Deduplicated: 1},
+			RawReport:   []byte("[]"),
+		}
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		assert.NoError(t, trivy.ValidateJSON(data))
+	})
+
+	t.Run("Should accept encoding of zero result", func(t *testing.T) {
+		data, err := json.Marshal(starboardv1alpha1.VulnerabilityScanResult{})
+		require.NoError(t, err)
+		assert.NoError(t, trivy.ValidateJSON(data))
+	})
+
+	t.Run("Should accept encoding of converted result", func(t *testing.T) {
+		config := starboard.ConfigData{"trivy.imageRef": "aquasec/trivy:0.9.1"}
+		result, err := trivy.NewConverter(trivy.WithClock(fixedClock)).Convert(config, "alpine:3.10.2", strings.NewReader(sampleReportAsString))
+		require.NoError(t, err)
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		assert.NoError(t, trivy.ValidateJSON(data))
+	})
+
+	testCases := []struct {
+		name          string
+		json          string
+		expectedError string
+	}{
+		{
+			name:          "Should reject malformed JSON",
+			json:          `{"scanner":`,
+			expectedError: "decoding scan result: unexpected EOF",
+		},
+		{
+			name:          "Should reject value other than object",
+			json:          `[]`,
+			expectedError: "scan result must be of type object: got array",
+		},
+		{
+			name:          "Should reject missing required fields",
+			json:          `{"scanner":{"name":"Trivy","vendor":"Aqua Security","version":"0.9.1"},"registry":{"server":""},"artifact":{"repository":""},"summary":{"criticalCount":0,"highCount":0,"mediumCount":0,"lowCount":0,"noneCount":0,"unknownCount":0}}`,
+			expectedError: "[vulnerabilities is required, updateTimestamp is required]",
+		},
+		{
+			name:          "Should reject unknown field",
+			json:          `{"scanner":{"name":"Trivy","vendor":"Aqua Security","version":"0.9.1","url":"https://github.com/aquasecurity/trivy"},"registry":{"server":""},"artifact":{"repository":""},"summary":{"criticalCount":0,"highCount":0,"mediumCount":0,"lowCount":0,"noneCount":0,"unknownCount":0},"vulnerabilities":[],"updateTimestamp":null}`,
+			expectedError: "scanner.url is not a known field",
+		},
+		{
+			name:          "Should reject values of wrong types and unsupported severity",
+			json:          `{"scanner":{"name":"Trivy","vendor":"Aqua Security","version":"0.9.1"},"registry":{"server":""},"artifact":{"repository":""},"summary":{"criticalCount":"1","highCount":0,"mediumCount":0.5,"lowCount":0,"noneCount":0,"unknownCount":0},"vulnerabilities":[{"vulnerabilityID":"CVE-2019-1549","resource":"openssl","installedVersion":"1.1.1c-r0","fixedVersion":"1.1.1d-r0","fixedVersions":null,"severity":"SEVERE","title":"","description":"","links":null,"cweIDs":null,"score":"5.3"}],"updateTimestamp":"2020-10-14T09:30:00Z"}`,
+			expectedError: "[summary.criticalCount must be of type integer: got string, summary.mediumCount must be of type integer: got number, vulnerabilities[0].score must be of type number: got string, vulnerabilities[0].severity is not a supported value: SEVERE]",
+		},
+		{
+			name:          "Should reject null value of required object",
+			json:          `{"scanner":null,"registry":{"server":""},"artifact":{"repository":""},"summary":{"criticalCount":0,"highCount":0,"mediumCount":0,"lowCount":0,"noneCount":0,"unknownCount":0},"vulnerabilities":[],"updateTimestamp":null}`,
+			expectedError: "scanner must not be null",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := trivy.ValidateJSON([]byte(tc.json))
+			require.Error(t, err)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package trivy
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+)
+
+// Writer is the interface that wraps the Write method.
+//
+// Write writes the specified VulnerabilityScanResult to the given io.Writer
+// in the JSON format used by Trivy. It's the inverse of Converter.Convert
+// for the fields that the Converter retains.
+type Writer interface {
+	Write(result starboardv1alpha1.VulnerabilityScanResult, w io.Writer) error
+}
+
+// WriterOption configures the Writer returned by NewWriter.
+type WriterOption func(*writer)
+
+// WithIndent sets the string used to indent nested JSON elements.
+// An empty string produces compact output.
+func WithIndent(indent string) WriterOption {
+	return func(w *writer) {
+		w.indent = indent
+	}
+}
+
+type writer struct {
+	indent string
+}
+
+// NewWriter constructs a new Writer with the specified options.
+// By default the output is indented with two spaces.
+func NewWriter(opts ...WriterOption) Writer {
+	w := &writer{
+		indent: "  ",
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func (w *writer) Write(result starboardv1alpha1.VulnerabilityScanResult, out io.Writer) error {
+	reports := w.toScanReports(result)
+
+	// Marshal the reports to generic maps first, so that the keys of each
+	// JSON object are sorted and the output is deterministic.
+	data, err := json.Marshal(reports)
+	if err != nil {
+		return err
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", w.indent)
+	return encoder.Encode(generic)
+}
+
+// toScanReports groups vulnerabilities by target preserving the order of the
+// ScannedTargets of the VulnerabilityScanResult, and then the order in which
+// other targets first appear in the result. Secrets are grouped into separate
+// reports of the secret class.
+func (w *writer) toScanReports(result starboardv1alpha1.VulnerabilityScanResult) []ScanReport {
+	reports := make([]ScanReport, 0)
+	indexByTarget := make(map[string]int)
+
+	for _, target := range result.ScannedTargets {
+		if _, ok := indexByTarget[target]; ok {
+			continue
+		}
+		indexByTarget[target] = len(reports)
+		reports = append(reports, ScanReport{
+			Target:          target,
+			Vulnerabilities: make([]Vulnerability, 0),
+		})
+	}
+
+	for _, v := range result.Vulnerabilities {
+		index, ok := indexByTarget[v.Target]
+		if !ok {
+			index = len(reports)
+			indexByTarget[v.Target] = index
+			reports = append(reports, ScanReport{
+				Target:          v.Target,
+				Vulnerabilities: make([]Vulnerability, 0),
+			})
+		}
+		reports[index].Vulnerabilities = append(reports[index].Vulnerabilities, w.toVulnerability(result.Scanner, v))
+	}
+
+	scannedIndexByTarget := indexByTarget
+	indexByTarget = make(map[string]int)
+	for _, secret := range result.Secrets {
+		index, ok := indexByTarget[secret.Target]
+		if scannedIndex, scanned := scannedIndexByTarget[secret.Target]; !ok && scanned && len(reports[scannedIndex].Vulnerabilities) == 0 {
+			// The target was scanned for secrets only.
+			index, ok = scannedIndex, true
+			indexByTarget[secret.Target] = index
+			reports[index].Class = ClassSecret
+			reports[index].Vulnerabilities = nil
+		}
+		if !ok {
+			index = len(reports)
+			indexByTarget[secret.Target] = index
+			reports = append(reports, ScanReport{
+				Target: secret.Target,
+				Class:  ClassSecret,
+			})
+		}
+		reports[index].Secrets = append(reports[index].Secrets, Secret{
+			RuleID:    secret.RuleID,
+			Category:  secret.Category,
+			Severity:  secret.Severity,
+			Title:     secret.Title,
+			StartLine: secret.StartLine,
+			EndLine:   secret.EndLine,
+		})
+	}
+	return reports
+}
+
+func (w *writer) toVulnerability(scanner starboardv1alpha1.Scanner, v starboardv1alpha1.Vulnerability) Vulnerability {
+	vulnerability := Vulnerability{
+		VulnerabilityID:  v.VulnerabilityID,
+		PkgName:          v.Resource,
+		PkgPath:          v.PkgPath,
+		InstalledVersion: v.InstalledVersion,
+		FixedVersion:     v.FixedVersion,
+		Title:            v.Title,
+		Description:      v.Description,
+		Severity:         v.Severity,
+		VendorSeverity:   w.toVendorSeverity(v.VendorSeverity),
+		Status:           string(v.Status),
+		PrimaryURL:       v.PrimaryURL,
+		References:       v.Links,
+		CweIDs:           v.CweIDs,
+	}
+	if v.PublishedDate != nil {
+		vulnerability.PublishedDate = v.PublishedDate.UTC().Format(time.RFC3339)
+	}
+	if v.LastModifiedDate != nil {
+		vulnerability.LastModifiedDate = v.LastModifiedDate.UTC().Format(time.RFC3339)
+	}
+	if v.DataSource != (starboardv1alpha1.DataSource{}) {
+		vulnerability.DataSource = &DataSource{
+			ID:   v.DataSource.ID,
+			Name: v.DataSource.Name,
+			URL:  v.DataSource.URL,
+		}
+	}
+	if v.PkgIdentifier != nil && v.PkgIdentifier.PURL != "" {
+		vulnerability.PkgIdentifier = &PkgIdentifier{PURL: v.PkgIdentifier.PURL}
+	}
+	if v.Layer != nil {
+		vulnerability.Layer = Layer{
+			Digest: v.Layer.Digest,
+			DiffID: v.Layer.DiffID,
+		}
+	}
+	if v.Score != nil {
+		// The vendor of the CVSS data is not retained, so it's attributed
+		// to the scanner that reported the vulnerability.
+		cvss := CVSS{}
+		if strings.HasPrefix(v.CVSSVector, "CVSS:3") {
+			cvss.V3Score = *v.Score
+			cvss.V3Vector = v.CVSSVector
+		} else {
+			cvss.V2Score = *v.Score
+			cvss.V2Vector = v.CVSSVector
+		}
+		vulnerability.CVSS = map[string]CVSS{
+			strings.ToLower(scanner.Name): cvss,
+		}
+	}
+	return vulnerability
+}
+
+func (w *writer) toVendorSeverity(vendorSeverity map[string]string) VendorSeverity {
+	if len(vendorSeverity) == 0 {
+		return nil
+	}
+	severities := make(VendorSeverity, len(vendorSeverity))
+	for vendor, severity := range vendorSeverity {
+		severities[vendor] = starboardv1alpha1.Severity(severity)
+	}
+	return severities
+}
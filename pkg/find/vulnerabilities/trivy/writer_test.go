@@ -0,0 +1,150 @@
+package trivy_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/starboard/pkg/find/vulnerabilities/trivy"
+	"github.com/aquasecurity/starboard/pkg/starboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+)
+
+func TestWriter_Write(t *testing.T) {
+	result := starboardv1alpha1.VulnerabilityScanResult{
+		Scanner: starboardv1alpha1.Scanner{
+			Name:    "Trivy",
+			Vendor:  "Aqua Security",
+			Version: "0.9.1",
+		},
+		Vulnerabilities: []starboardv1alpha1.Vulnerability{
+			{
+				VulnerabilityID:  "CVE-2019-1549",
+				Resource:         "openssl",
+				InstalledVersion: "1.1.1c-r0",
+				FixedVersion:     "1.1.1d-r0",
+				Severity:         starboardv1alpha1.SeverityMedium,
+				Title:            "openssl: information disclosure in fork()",
+				Links:            []string{},
+				Score:            pointer.Float64Ptr(5.3),
+				CVSSVector:       "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:N/A:N",
+				Target:           "alpine:3.10.2 (alpine 3.10.2)",
+				CweIDs:           []string{"CWE-330"},
+			},
+		},
+	}
+
+	t.Run("Should write indented JSON with sorted keys", func(t *testing.T) {
+		var out bytes.Buffer
+		err := trivy.NewWriter().Write(result, &out)
+		require.NoError(t, err)
+		assert.Equal(t, `[
+  {
+    "Target": "alpine:3.10.2 (alpine 3.10.2)",
+    "Vulnerabilities": [
+      {
+        "CVSS": {
+          "trivy": {
+            "V2Score": 0,
+            "V2Vector": "",
+            "V3Score": 5.3,
+            "V3Vector": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:N/A:N"
+          }
+        },
+        "CweIDs": [
+          "CWE-330"
+        ],
+        "Description": "",
+        "FixedVersion": "1.1.1d-r0",
+        "InstalledVersion": "1.1.1c-r0",
+        "Layer": {
+          "DiffID": "",
+          "Digest": ""
+        },
+        "LayerID": "",
+        "PkgName": "openssl",
+        "PrimaryURL": "",
+        "References": [],
+        "Severity": "MEDIUM",
+        "Title": "openssl: information disclosure in fork()",
+        "VulnerabilityID": "CVE-2019-1549"
+      }
+    ]
+  }
+]
+`, out.String())
+	})
+
+	t.Run("Should write compact JSON when indent is empty", func(t *testing.T) {
+		var out bytes.Buffer
+		err := trivy.NewWriter(trivy.WithIndent("")).Write(result, &out)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+		assert.True(t, strings.HasPrefix(out.String(), `[{"Target":"alpine:3.10.2 (alpine 3.10.2)","Vulnerabilities":[{"CVSS":`))
+	})
+}
+
+func TestWriter_Write_RoundTrip(t *testing.T) {
+	config := starboard.ConfigData{
+		"trivy.imageRef": "aquasec/trivy:0.9.1",
+	}
+	input := `[
+	{
+		"Target": "nginx:1.16 (debian 10.4)",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2019-18276",
+			"PkgName": "bash",
+			"InstalledVersion": "5.0-4",
+			"Title": "bash: when effective UID is not equal to its real UID the saved UID is not dropped",
+			"Description": "An issue was discovered in disable_priv_mode in shell.c in GNU Bash through 5.0 patch 11.",
+			"Severity": "LOW",
+			"PublishedDate": "2019-11-28T01:15:00Z",
+			"DataSource": {"ID": "debian", "Name": "Debian Security Tracker", "URL": "https://salsa.debian.org/security-tracker-team/security-tracker"},
+			"LastModifiedDate": "2020-08-15T19:15:00Z",
+			"References": [
+				"http://packetstormsecurity.com/files/155498/Bash-5.0-Patch-11-Privilege-Escalation.html"
+			],
+			"Layer": {
+				"Digest": "sha256:bf5952930446728ddb3fc7fb2c4d8d0a4c8c8be06e1cfd6f2fc8acbdcd0344c0",
+				"DiffID": "sha256:d0f104dc0a1f9c744b65b23b3fd4d4d3236b4656e67f776fe13f8ad8423b955c"
+			},
+			"CVSS": {
+				"nvd": {"V2Vector": "AV:L/AC:L/Au:N/C:C/I:C/A:C", "V2Score": 7.2}
+			}
+		}
+	]
+	},
+	{
+		"Target": "usr/local/bin/app",
+		"Vulnerabilities": [
+		{
+			"VulnerabilityID": "CVE-2020-14040",
+			"PkgName": "golang.org/x/text",
+			"InstalledVersion": "v0.3.2",
+			"FixedVersion": "v0.3.3",
+			"Severity": "HIGH",
+			"CVSS": {
+				"nvd": {"V3Vector": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", "V3Score": 7.5}
+			}
+		}
+	]
+	}
+]`
+
+	converter := trivy.NewConverter(trivy.WithClock(fixedClock))
+
+	converted, err := converter.Convert(config, "nginx:1.16", strings.NewReader(input))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = trivy.NewWriter().Write(converted, &out)
+	require.NoError(t, err)
+
+	reconverted, err := converter.Convert(config, "nginx:1.16", &out)
+	require.NoError(t, err)
+	assert.Equal(t, converted, reconverted)
+}
@@ -3,6 +3,10 @@ package starboard
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -250,6 +254,389 @@ func (c ConfigData) GetTrivyImageRef() string {
 	return "docker.io/aquasec/trivy:0.9.1"
 }
 
+// GetSeverityThreshold returns the minimum severity of vulnerabilities stored
+// in vulnerability reports. An empty value means that all vulnerabilities are
+// stored regardless of their severity.
+func (c ConfigData) GetSeverityThreshold() string {
+	return c["trivy.severityThreshold"]
+}
+
+// GetFailOnSeverity returns the minimum severity of vulnerabilities that fail
+// a gated conversion of a vulnerability report. An empty value means that the
+// conversion never fails because of vulnerabilities.
+func (c ConfigData) GetFailOnSeverity() string {
+	return c["trivy.failOnSeverity"]
+}
+
+// GetDockerHubRegistry returns the canonical host of the Docker Hub registry
+// used in vulnerability reports for images pulled from Docker Hub.
+func (c ConfigData) GetDockerHubRegistry() string {
+	if registry, ok := c["trivy.dockerHubRegistry"]; ok && registry != "" {
+		return registry
+	}
+	return "index.docker.io"
+}
+
+// GetMaxLinks returns the maximum number of links stored for a vulnerability.
+// Zero means that the number is not limited.
+func (c ConfigData) GetMaxLinks() int {
+	value, ok := c["trivy.maxLinks"]
+	if !ok {
+		return 0
+	}
+	max, err := strconv.Atoi(value)
+	if err != nil || max < 0 {
+		return 0
+	}
+	return max
+}
+
+// DefaultMaxScanOutputSize is the default maximum size in bytes of the output
+// of Trivy returned by GetMaxScanOutputSize.
+const DefaultMaxScanOutputSize int64 = 200 * 1024 * 1024
+
+// GetMaxScanOutputSize returns the maximum size in bytes of the output of
+// Trivy, after decompression, that is converted into a vulnerability report.
+// It defaults to DefaultMaxScanOutputSize if not set, or set to an invalid or
+// non-positive value.
+func (c ConfigData) GetMaxScanOutputSize() int64 {
+	value, ok := c["trivy.maxScanOutputSize"]
+	if !ok {
+		return DefaultMaxScanOutputSize
+	}
+	max, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || max <= 0 {
+		return DefaultMaxScanOutputSize
+	}
+	return max
+}
+
+// GetMaxVulnerabilities returns the maximum number of vulnerabilities stored
+// in a vulnerability report. Zero means that the number is not limited.
+func (c ConfigData) GetMaxVulnerabilities() int {
+	value, ok := c["trivy.maxVulnerabilities"]
+	if !ok {
+		return 0
+	}
+	max, err := strconv.Atoi(value)
+	if err != nil || max < 0 {
+		return 0
+	}
+	return max
+}
+
+// GetMinScore returns the minimum CVSS score of vulnerabilities stored in
+// vulnerability reports. Zero means that vulnerabilities are stored regardless
+// of their score, and so does an invalid or negative value.
+func (c ConfigData) GetMinScore() float64 {
+	value, ok := c["trivy.minScore"]
+	if !ok {
+		return 0
+	}
+	min, err := strconv.ParseFloat(value, 64)
+	if err != nil || min < 0 {
+		return 0
+	}
+	return min
+}
+
+// GetDropUnscored returns whether vulnerabilities without a CVSS score are
+// omitted from vulnerability reports when the minimum score returned by
+// GetMinScore is set. It's false, i.e. such vulnerabilities are kept, unless
+// set to a valid boolean.
+func (c ConfigData) GetDropUnscored() bool {
+	drop, err := strconv.ParseBool(c["trivy.dropUnscored"])
+	return err == nil && drop
+}
+
+// GetRiskScoreWeights returns the weights of severity levels used to compute
+// the risk score of a vulnerability report. The weights are configured as a
+// comma separated list of SEVERITY=WEIGHT pairs, e.g. "CRITICAL=20,HIGH=8".
+// Severity levels that are not configured, or configured with an invalid
+// weight, default to CRITICAL=10, HIGH=5, MEDIUM=2, LOW=1 and UNKNOWN=1.
+func (c ConfigData) GetRiskScoreWeights() map[starboardv1alpha1.Severity]int {
+	weights := map[starboardv1alpha1.Severity]int{
+		starboardv1alpha1.SeverityCritical: 10,
+		starboardv1alpha1.SeverityHigh:     5,
+		starboardv1alpha1.SeverityMedium:   2,
+		starboardv1alpha1.SeverityLow:      1,
+		starboardv1alpha1.SeverityUnknown:  1,
+	}
+	value, ok := c["trivy.riskScoreWeights"]
+	if !ok {
+		return weights
+	}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		severity := starboardv1alpha1.Severity(strings.ToUpper(strings.TrimSpace(parts[0])))
+		if _, ok := weights[severity]; !ok {
+			continue
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || weight < 0 {
+			continue
+		}
+		weights[severity] = weight
+	}
+	return weights
+}
+
+// GetIncludedFields returns the set of optional fields of vulnerabilities,
+// such as description, links or title, stored in vulnerability reports. The
+// fields are configured as a comma separated list of their JSON names. A nil
+// set, returned when no field is listed, means that all fields are stored.
+func (c ConfigData) GetIncludedFields() map[string]bool {
+	var fields map[string]bool
+	for _, field := range strings.Split(c["trivy.includeFields"], ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]bool)
+		}
+		fields[field] = true
+	}
+	return fields
+}
+
+// GetIncludePackages returns the glob patterns, in the syntax of path.Match,
+// of the names of packages whose vulnerabilities are stored in vulnerability
+// reports, e.g. openssl or lib*. The patterns are configured as a comma
+// separated list, and malformed patterns are skipped. Vulnerabilities of all
+// packages are stored if there are no patterns.
+func (c ConfigData) GetIncludePackages() []string {
+	return parsePackagePatterns(c["trivy.includePackages"])
+}
+
+// GetExcludePackages returns the glob patterns, in the syntax of path.Match,
+// of the names of packages whose vulnerabilities are omitted from
+// vulnerability reports, e.g. busybox. The patterns are configured as a comma
+// separated list, and malformed patterns are skipped. Excluded packages are
+// omitted even if they're included by GetIncludePackages.
+func (c ConfigData) GetExcludePackages() []string {
+	return parsePackagePatterns(c["trivy.excludePackages"])
+}
+
+func parsePackagePatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// GetIgnorePolicy returns the policy of vulnerabilities omitted from
+// vulnerability reports, which is configured in the format of the .trivyignore
+// file, see ParseIgnorePolicy.
+func (c ConfigData) GetIgnorePolicy() IgnorePolicy {
+	return ParseIgnorePolicy(c["trivy.ignorePolicy"])
+}
+
+// IgnoreRule is the rule of an IgnorePolicy that omits the vulnerability with
+// the specified identifier.
+type IgnoreRule struct {
+	ID string
+	// ExpiresAt is the time when the rule expires. It's zero if the rule
+	// never expires.
+	ExpiresAt time.Time
+}
+
+// IsExpired checks whether the rule is expired at the specified time.
+func (r IgnoreRule) IsExpired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && now.After(r.ExpiresAt)
+}
+
+// IgnorePolicy is the list of rules of vulnerabilities to be omitted.
+type IgnorePolicy []IgnoreRule
+
+// Find returns the rule of the vulnerability with any of the specified
+// identifiers.
+func (p IgnorePolicy) Find(ids ...string) (IgnoreRule, bool) {
+	for _, rule := range p {
+		for _, id := range ids {
+			if rule.ID == id {
+				return rule, true
+			}
+		}
+	}
+	return IgnoreRule{}, false
+}
+
+// ParseIgnorePolicy parses the specified content of the .trivyignore file.
+// Each line holds the identifier of a vulnerability optionally followed by
+// the expiry date of the rule, e.g. "CVE-2019-1549 exp:2021-12-31". The rule
+// expires at the beginning of that date in UTC. Empty lines and comments that
+// begin with # are skipped, and so are lines with a malformed expiry date, so
+// that they never omit a vulnerability.
+func ParseIgnorePolicy(content string) IgnorePolicy {
+	var policy IgnorePolicy
+	for _, line := range strings.Split(content, "\n") {
+		if index := strings.IndexRune(line, '#'); index >= 0 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rule := IgnoreRule{ID: fields[0]}
+		valid := true
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "exp:") {
+				continue
+			}
+			expiresAt, err := time.Parse("2006-01-02", strings.TrimPrefix(field, "exp:"))
+			if err != nil {
+				valid = false
+				break
+			}
+			rule.ExpiresAt = expiresAt
+		}
+		if valid {
+			policy = append(policy, rule)
+		}
+	}
+	return policy
+}
+
+// GetSeverityOverrides returns the rules that override the severity of
+// matching vulnerabilities, see ParseSeverityOverrides.
+func (c ConfigData) GetSeverityOverrides() SeverityOverrides {
+	return ParseSeverityOverrides(c["trivy.severityOverrides"])
+}
+
+// SeverityOverride is the rule of SeverityOverrides that sets the severity of
+// vulnerabilities of packages matching the glob pattern, with the specified
+// identifier, or both.
+type SeverityOverride struct {
+	// Package is the glob pattern of the package name in the syntax of
+	// path.Match, e.g. openssl* or *-test. It matches any package if empty.
+	Package string
+	// ID is the identifier of the vulnerability. It matches any
+	// vulnerability if empty.
+	ID string
+	// Severity is the severity of matching vulnerabilities.
+	Severity starboardv1alpha1.Severity
+}
+
+// Matches checks whether the rule matches the vulnerability of the specified
+// package with any of the given identifiers.
+func (o SeverityOverride) Matches(pkgName string, ids ...string) bool {
+	if o.Package != "" {
+		if matched, err := path.Match(o.Package, pkgName); err != nil || !matched {
+			return false
+		}
+	}
+	if o.ID == "" {
+		return true
+	}
+	for _, id := range ids {
+		if o.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SeverityOverrides is the list of rules that override severities of
+// vulnerabilities.
+type SeverityOverrides []SeverityOverride
+
+// Find returns the first rule that matches the vulnerability of the specified
+// package with any of the given identifiers.
+func (s SeverityOverrides) Find(pkgName string, ids ...string) (SeverityOverride, bool) {
+	for _, override := range s {
+		if override.Matches(pkgName, ids...) {
+			return override, true
+		}
+	}
+	return SeverityOverride{}, false
+}
+
+// ParseSeverityOverrides parses the specified rules that override severities
+// of vulnerabilities. Each line holds the severity followed by the glob pattern
+// of the package name, the identifier of the vulnerability, or both, e.g.
+// "HIGH pkg:openssl*", "LOW pkg:*-test" or "CRITICAL id:CVE-2021-44228". Rules
+// are kept in order, so that the first matching rule wins. Empty lines and
+// comments that begin with # are skipped, and so are lines with an unknown
+// severity, a malformed pattern, or neither a pattern nor an identifier, so
+// that they never override a severity.
+func ParseSeverityOverrides(content string) SeverityOverrides {
+	var overrides SeverityOverrides
+	for _, line := range strings.Split(content, "\n") {
+		if index := strings.IndexRune(line, '#'); index >= 0 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		severity, err := starboardv1alpha1.ParseSeverity(fields[0])
+		if err != nil || severity == starboardv1alpha1.SeverityNone {
+			continue
+		}
+		override := SeverityOverride{Severity: severity}
+		valid := true
+		for _, field := range fields[1:] {
+			switch {
+			case strings.HasPrefix(field, "pkg:"):
+				override.Package = strings.TrimPrefix(field, "pkg:")
+				if _, err := path.Match(override.Package, ""); err != nil {
+					valid = false
+				}
+			case strings.HasPrefix(field, "id:"):
+				override.ID = strings.TrimPrefix(field, "id:")
+			}
+		}
+		if valid && (override.Package != "" || override.ID != "") {
+			overrides = append(overrides, override)
+		}
+	}
+	return overrides
+}
+
+// GetScannerName returns the name of the vulnerability scanner reported in
+// vulnerability reports.
+func (c ConfigData) GetScannerName() string {
+	if name, ok := c["trivy.scannerName"]; ok && name != "" {
+		return name
+	}
+	return "Trivy"
+}
+
+// GetIgnoreUnfixed returns whether vulnerabilities without a fixed version are
+// omitted from vulnerability reports. It's false unless set to a valid boolean.
+func (c ConfigData) GetIgnoreUnfixed() bool {
+	ignore, err := strconv.ParseBool(c["trivy.ignoreUnfixed"])
+	return err == nil && ignore
+}
+
+// GetStrictImageRefValidation returns whether image references must be fully
+// specified, i.e. with the registry, the full repository path and the tag or
+// the digest, to be parsed. It's false unless set to a valid boolean.
+func (c ConfigData) GetStrictImageRefValidation() bool {
+	strict, err := strconv.ParseBool(c["trivy.strictImageRefValidation"])
+	return err == nil && strict
+}
+
+// GetScannerVendor returns the vendor of the vulnerability scanner reported in
+// vulnerability reports.
+func (c ConfigData) GetScannerVendor() string {
+	if vendor, ok := c["trivy.scannerVendor"]; ok && vendor != "" {
+		return vendor
+	}
+	return "Aqua Security"
+}
+
 // GetKubeBenchImageRef returns Docker image of kube-bench scanner.
 func (c ConfigData) GetKubeBenchImageRef() string {
 	if imageRef, ok := c["kube-bench.imageRef"]; ok {
@@ -3,6 +3,7 @@ package starboard_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 
@@ -10,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	starboardv1alpha1 "github.com/aquasecurity/starboard/pkg/apis/aquasecurity/v1alpha1"
 	"github.com/aquasecurity/starboard/pkg/starboard"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/client-go/kubernetes/fake"
@@ -78,6 +80,679 @@ func TestConfigData_GetTrivyImageRef(t *testing.T) {
 	}
 }
 
+func TestConfigData_GetSeverityThreshold(t *testing.T) {
+	testCases := []struct {
+		name              string
+		configData        starboard.ConfigData
+		expectedThreshold string
+	}{
+		{
+			name:              "Should return empty threshold by default",
+			configData:        starboard.ConfigData{},
+			expectedThreshold: "",
+		},
+		{
+			name: "Should return threshold from config data",
+			configData: starboard.ConfigData{
+				"trivy.severityThreshold": "HIGH",
+			},
+			expectedThreshold: "HIGH",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			threshold := tc.configData.GetSeverityThreshold()
+			assert.Equal(t, tc.expectedThreshold, threshold)
+		})
+	}
+}
+
+func TestConfigData_GetFailOnSeverity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		configData       starboard.ConfigData
+		expectedSeverity string
+	}{
+		{
+			name:             "Should return empty severity by default",
+			configData:       starboard.ConfigData{},
+			expectedSeverity: "",
+		},
+		{
+			name: "Should return severity from config data",
+			configData: starboard.ConfigData{
+				"trivy.failOnSeverity": "CRITICAL",
+			},
+			expectedSeverity: "CRITICAL",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			severity := tc.configData.GetFailOnSeverity()
+			assert.Equal(t, tc.expectedSeverity, severity)
+		})
+	}
+}
+
+func TestConfigData_GetDockerHubRegistry(t *testing.T) {
+	testCases := []struct {
+		name             string
+		configData       starboard.ConfigData
+		expectedRegistry string
+	}{
+		{
+			name:             "Should return default registry",
+			configData:       starboard.ConfigData{},
+			expectedRegistry: "index.docker.io",
+		},
+		{
+			name: "Should return registry from config data",
+			configData: starboard.ConfigData{
+				"trivy.dockerHubRegistry": "docker.io",
+			},
+			expectedRegistry: "docker.io",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := tc.configData.GetDockerHubRegistry()
+			assert.Equal(t, tc.expectedRegistry, registry)
+		})
+	}
+}
+
+func TestConfigData_GetMaxVulnerabilities(t *testing.T) {
+	testCases := []struct {
+		name        string
+		configData  starboard.ConfigData
+		expectedMax int
+	}{
+		{
+			name:        "Should return zero when limit is not set",
+			configData:  starboard.ConfigData{},
+			expectedMax: 0,
+		},
+		{
+			name: "Should return limit from config data",
+			configData: starboard.ConfigData{
+				"trivy.maxVulnerabilities": "1000",
+			},
+			expectedMax: 1000,
+		},
+		{
+			name: "Should return zero when limit is invalid",
+			configData: starboard.ConfigData{
+				"trivy.maxVulnerabilities": "many",
+			},
+			expectedMax: 0,
+		},
+		{
+			name: "Should return zero when limit is negative",
+			configData: starboard.ConfigData{
+				"trivy.maxVulnerabilities": "-1",
+			},
+			expectedMax: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			max := tc.configData.GetMaxVulnerabilities()
+			assert.Equal(t, tc.expectedMax, max)
+		})
+	}
+}
+
+func TestConfigData_GetMaxLinks(t *testing.T) {
+	testCases := []struct {
+		name        string
+		configData  starboard.ConfigData
+		expectedMax int
+	}{
+		{
+			name:        "Should return zero when limit is not set",
+			configData:  starboard.ConfigData{},
+			expectedMax: 0,
+		},
+		{
+			name: "Should return limit from config data",
+			configData: starboard.ConfigData{
+				"trivy.maxLinks": "5",
+			},
+			expectedMax: 5,
+		},
+		{
+			name: "Should return zero when limit is invalid",
+			configData: starboard.ConfigData{
+				"trivy.maxLinks": "few",
+			},
+			expectedMax: 0,
+		},
+		{
+			name: "Should return zero when limit is negative",
+			configData: starboard.ConfigData{
+				"trivy.maxLinks": "-5",
+			},
+			expectedMax: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			max := tc.configData.GetMaxLinks()
+			assert.Equal(t, tc.expectedMax, max)
+		})
+	}
+}
+
+func TestConfigData_GetMaxScanOutputSize(t *testing.T) {
+	testCases := []struct {
+		name         string
+		configData   starboard.ConfigData
+		expectedSize int64
+	}{
+		{
+			name:         "Should return default size when limit is not set",
+			configData:   starboard.ConfigData{},
+			expectedSize: starboard.DefaultMaxScanOutputSize,
+		},
+		{
+			name: "Should return limit from config data",
+			configData: starboard.ConfigData{
+				"trivy.maxScanOutputSize": "1048576",
+			},
+			expectedSize: 1048576,
+		},
+		{
+			name: "Should return default size when limit is invalid",
+			configData: starboard.ConfigData{
+				"trivy.maxScanOutputSize": "1Mi",
+			},
+			expectedSize: starboard.DefaultMaxScanOutputSize,
+		},
+		{
+			name: "Should return default size when limit is zero",
+			configData: starboard.ConfigData{
+				"trivy.maxScanOutputSize": "0",
+			},
+			expectedSize: starboard.DefaultMaxScanOutputSize,
+		},
+		{
+			name: "Should return default size when limit is negative",
+			configData: starboard.ConfigData{
+				"trivy.maxScanOutputSize": "-1",
+			},
+			expectedSize: starboard.DefaultMaxScanOutputSize,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			size := tc.configData.GetMaxScanOutputSize()
+			assert.Equal(t, tc.expectedSize, size)
+		})
+	}
+}
+
+func TestConfigData_GetRiskScoreWeights(t *testing.T) {
+	testCases := []struct {
+		name            string
+		configData      starboard.ConfigData
+		expectedWeights map[starboardv1alpha1.Severity]int
+	}{
+		{
+			name:       "Should return default weights",
+			configData: starboard.ConfigData{},
+			expectedWeights: map[starboardv1alpha1.Severity]int{
+				starboardv1alpha1.SeverityCritical: 10,
+				starboardv1alpha1.SeverityHigh:     5,
+				starboardv1alpha1.SeverityMedium:   2,
+				starboardv1alpha1.SeverityLow:      1,
+				starboardv1alpha1.SeverityUnknown:  1,
+			},
+		},
+		{
+			name: "Should override default weights with weights from config data",
+			configData: starboard.ConfigData{
+				"trivy.riskScoreWeights": "CRITICAL=20, high=8,UNKNOWN=0",
+			},
+			expectedWeights: map[starboardv1alpha1.Severity]int{
+				starboardv1alpha1.SeverityCritical: 20,
+				starboardv1alpha1.SeverityHigh:     8,
+				starboardv1alpha1.SeverityMedium:   2,
+				starboardv1alpha1.SeverityLow:      1,
+				starboardv1alpha1.SeverityUnknown:  0,
+			},
+		},
+		{
+			name: "Should ignore invalid weights",
+			configData: starboard.ConfigData{
+				"trivy.riskScoreWeights": "CRITICAL=many,HIGH=-1,NONE=3,MEDIUM",
+			},
+			expectedWeights: map[starboardv1alpha1.Severity]int{
+				starboardv1alpha1.SeverityCritical: 10,
+				starboardv1alpha1.SeverityHigh:     5,
+				starboardv1alpha1.SeverityMedium:   2,
+				starboardv1alpha1.SeverityLow:      1,
+				starboardv1alpha1.SeverityUnknown:  1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			weights := tc.configData.GetRiskScoreWeights()
+			assert.Equal(t, tc.expectedWeights, weights)
+		})
+	}
+}
+
+func TestConfigData_GetScannerName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		configData   starboard.ConfigData
+		expectedName string
+	}{
+		{
+			name:         "Should return default scanner name",
+			configData:   starboard.ConfigData{},
+			expectedName: "Trivy",
+		},
+		{
+			name: "Should return scanner name from config data",
+			configData: starboard.ConfigData{
+				"trivy.scannerName": "Acme Scanner",
+			},
+			expectedName: "Acme Scanner",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			name := tc.configData.GetScannerName()
+			assert.Equal(t, tc.expectedName, name)
+		})
+	}
+}
+
+func TestConfigData_GetScannerVendor(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configData     starboard.ConfigData
+		expectedVendor string
+	}{
+		{
+			name:           "Should return default scanner vendor",
+			configData:     starboard.ConfigData{},
+			expectedVendor: "Aqua Security",
+		},
+		{
+			name: "Should return scanner vendor from config data",
+			configData: starboard.ConfigData{
+				"trivy.scannerVendor": "Acme Corp",
+			},
+			expectedVendor: "Acme Corp",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vendor := tc.configData.GetScannerVendor()
+			assert.Equal(t, tc.expectedVendor, vendor)
+		})
+	}
+}
+
+func TestConfigData_GetStrictImageRefValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configData     starboard.ConfigData
+		expectedStrict bool
+	}{
+		{
+			name:           "Should return false by default",
+			configData:     starboard.ConfigData{},
+			expectedStrict: false,
+		},
+		{
+			name: "Should return true when enabled",
+			configData: starboard.ConfigData{
+				"trivy.strictImageRefValidation": "true",
+			},
+			expectedStrict: true,
+		},
+		{
+			name: "Should return false when value is invalid",
+			configData: starboard.ConfigData{
+				"trivy.strictImageRefValidation": "yes please",
+			},
+			expectedStrict: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			strict := tc.configData.GetStrictImageRefValidation()
+			assert.Equal(t, tc.expectedStrict, strict)
+		})
+	}
+}
+
+func TestConfigData_GetMinScore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		configData    starboard.ConfigData
+		expectedScore float64
+	}{
+		{
+			name:          "Should return zero by default",
+			configData:    starboard.ConfigData{},
+			expectedScore: 0,
+		},
+		{
+			name: "Should return configured score",
+			configData: starboard.ConfigData{
+				"trivy.minScore": "7.0",
+			},
+			expectedScore: 7.0,
+		},
+		{
+			name: "Should return zero when value is negative",
+			configData: starboard.ConfigData{
+				"trivy.minScore": "-1.5",
+			},
+			expectedScore: 0,
+		},
+		{
+			name: "Should return zero when value is invalid",
+			configData: starboard.ConfigData{
+				"trivy.minScore": "high",
+			},
+			expectedScore: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score := tc.configData.GetMinScore()
+			assert.Equal(t, tc.expectedScore, score)
+		})
+	}
+}
+
+func TestConfigData_GetDropUnscored(t *testing.T) {
+	assert.False(t, starboard.ConfigData{}.GetDropUnscored())
+	assert.True(t, starboard.ConfigData{"trivy.dropUnscored": "true"}.GetDropUnscored())
+	assert.False(t, starboard.ConfigData{"trivy.dropUnscored": "maybe"}.GetDropUnscored())
+}
+
+func TestConfigData_GetIgnoreUnfixed(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configData     starboard.ConfigData
+		expectedIgnore bool
+	}{
+		{
+			name:           "Should return false by default",
+			configData:     starboard.ConfigData{},
+			expectedIgnore: false,
+		},
+		{
+			name: "Should return true when enabled",
+			configData: starboard.ConfigData{
+				"trivy.ignoreUnfixed": "true",
+			},
+			expectedIgnore: true,
+		},
+		{
+			name: "Should return false when disabled",
+			configData: starboard.ConfigData{
+				"trivy.ignoreUnfixed": "false",
+			},
+			expectedIgnore: false,
+		},
+		{
+			name: "Should return false when value is invalid",
+			configData: starboard.ConfigData{
+				"trivy.ignoreUnfixed": "sometimes",
+			},
+			expectedIgnore: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := tc.configData.GetIgnoreUnfixed()
+			assert.Equal(t, tc.expectedIgnore, ignore)
+		})
+	}
+}
+
+func TestConfigData_GetIncludedFields(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configData     starboard.ConfigData
+		expectedFields map[string]bool
+	}{
+		{
+			name:           "Should return nil set by default",
+			configData:     starboard.ConfigData{},
+			expectedFields: nil,
+		},
+		{
+			name: "Should return set of listed fields",
+			configData: starboard.ConfigData{
+				"trivy.includeFields": "title, links,,primaryURL ",
+			},
+			expectedFields: map[string]bool{"title": true, "links": true, "primaryURL": true},
+		},
+		{
+			name: "Should return nil set when value is empty",
+			configData: starboard.ConfigData{
+				"trivy.includeFields": "",
+			},
+			expectedFields: nil,
+		},
+		{
+			name: "Should return nil set when no field is listed",
+			configData: starboard.ConfigData{
+				"trivy.includeFields": " , ",
+			},
+			expectedFields: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := tc.configData.GetIncludedFields()
+			assert.Equal(t, tc.expectedFields, fields)
+		})
+	}
+}
+
+func TestConfigData_GetPackagePatterns(t *testing.T) {
+	testCases := []struct {
+		name            string
+		configData      starboard.ConfigData
+		expectedInclude []string
+		expectedExclude []string
+	}{
+		{
+			name:       "Should return nil patterns by default",
+			configData: starboard.ConfigData{},
+		},
+		{
+			name: "Should return listed patterns",
+			configData: starboard.ConfigData{
+				"trivy.includePackages": "openssl, lib*,,",
+				"trivy.excludePackages": " busybox ",
+			},
+			expectedInclude: []string{"openssl", "lib*"},
+			expectedExclude: []string{"busybox"},
+		},
+		{
+			name: "Should skip malformed patterns",
+			configData: starboard.ConfigData{
+				"trivy.includePackages": "[openssl,musl",
+				"trivy.excludePackages": "busy[box",
+			},
+			expectedInclude: []string{"musl"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedInclude, tc.configData.GetIncludePackages())
+			assert.Equal(t, tc.expectedExclude, tc.configData.GetExcludePackages())
+		})
+	}
+}
+
+func TestConfigData_GetIgnorePolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configData     starboard.ConfigData
+		expectedPolicy starboard.IgnorePolicy
+	}{
+		{
+			name:           "Should return empty policy by default",
+			configData:     starboard.ConfigData{},
+			expectedPolicy: nil,
+		},
+		{
+			name: "Should parse rules with and without expiry dates",
+			configData: starboard.ConfigData{
+				"trivy.ignorePolicy": "# Accepted risks\n\nCVE-2019-1549\n  CVE-2019-1547 exp:2021-12-31 # until upgrade\nGHSA-35jh-r3h4-6jhm\n",
+			},
+			expectedPolicy: starboard.IgnorePolicy{
+				{ID: "CVE-2019-1549"},
+				{ID: "CVE-2019-1547", ExpiresAt: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)},
+				{ID: "GHSA-35jh-r3h4-6jhm"},
+			},
+		},
+		{
+			name: "Should skip rules with malformed expiry dates",
+			configData: starboard.ConfigData{
+				"trivy.ignorePolicy": "CVE-2019-1549 exp:31/12/2021\nCVE-2019-1547",
+			},
+			expectedPolicy: starboard.IgnorePolicy{
+				{ID: "CVE-2019-1547"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := tc.configData.GetIgnorePolicy()
+			assert.Equal(t, tc.expectedPolicy, policy)
+		})
+	}
+}
+
+func TestIgnoreRule_IsExpired(t *testing.T) {
+	rule := starboard.IgnoreRule{ID: "CVE-2019-1549", ExpiresAt: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)}
+	assert.False(t, rule.IsExpired(time.Date(2021, 12, 30, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, rule.IsExpired(time.Date(2021, 12, 31, 0, 1, 0, 0, time.UTC)))
+	assert.False(t, starboard.IgnoreRule{ID: "CVE-2019-1549"}.IsExpired(time.Now()))
+}
+
+func TestConfigData_GetSeverityOverrides(t *testing.T) {
+	testCases := []struct {
+		name              string
+		configData        starboard.ConfigData
+		expectedOverrides starboard.SeverityOverrides
+	}{
+		{
+			name:              "Should return no overrides by default",
+			configData:        starboard.ConfigData{},
+			expectedOverrides: nil,
+		},
+		{
+			name: "Should parse rules in order",
+			configData: starboard.ConfigData{
+				"trivy.severityOverrides": "# Policy of the platform team\n\nhigh pkg:openssl*\n  LOW pkg:*-test # test-only packages\nCRITICAL id:CVE-2021-44228\nMEDIUM pkg:libssl1.1 id:CVE-2021-3449\n",
+			},
+			expectedOverrides: starboard.SeverityOverrides{
+				{Package: "openssl*", Severity: starboardv1alpha1.SeverityHigh},
+				{Package: "*-test", Severity: starboardv1alpha1.SeverityLow},
+				{ID: "CVE-2021-44228", Severity: starboardv1alpha1.SeverityCritical},
+				{Package: "libssl1.1", ID: "CVE-2021-3449", Severity: starboardv1alpha1.SeverityMedium},
+			},
+		},
+		{
+			name: "Should skip malformed rules",
+			configData: starboard.ConfigData{
+				"trivy.severityOverrides": "SEVERE pkg:openssl\nNONE pkg:openssl\nHIGH\nHIGH pkg:[openssl\nLOW id:CVE-2019-1547",
+			},
+			expectedOverrides: starboard.SeverityOverrides{
+				{ID: "CVE-2019-1547", Severity: starboardv1alpha1.SeverityLow},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			overrides := tc.configData.GetSeverityOverrides()
+			assert.Equal(t, tc.expectedOverrides, overrides)
+		})
+	}
+}
+
+func TestSeverityOverrides_Find(t *testing.T) {
+	overrides := starboard.SeverityOverrides{
+		{Package: "openssl", ID: "CVE-2019-1547", Severity: starboardv1alpha1.SeverityLow},
+		{Package: "openssl*", Severity: starboardv1alpha1.SeverityHigh},
+		{ID: "GHSA-35jh-r3h4-6jhm", Severity: starboardv1alpha1.SeverityCritical},
+	}
+
+	testCases := []struct {
+		name             string
+		pkgName          string
+		ids              []string
+		expectedFound    bool
+		expectedSeverity starboardv1alpha1.Severity
+	}{
+		{
+			name:             "Should match package and identifier",
+			pkgName:          "openssl",
+			ids:              []string{"CVE-2019-1547"},
+			expectedFound:    true,
+			expectedSeverity: starboardv1alpha1.SeverityLow,
+		},
+		{
+			name:             "Should match package glob",
+			pkgName:          "openssl-libs",
+			ids:              []string{"CVE-2019-1547"},
+			expectedFound:    true,
+			expectedSeverity: starboardv1alpha1.SeverityHigh,
+		},
+		{
+			name:             "Should match alias",
+			pkgName:          "lodash",
+			ids:              []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"},
+			expectedFound:    true,
+			expectedSeverity: starboardv1alpha1.SeverityCritical,
+		},
+		{
+			name:          "Should not match other packages",
+			pkgName:       "libssl1.1",
+			ids:           []string{"CVE-2019-1547"},
+			expectedFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			override, found := overrides.Find(tc.pkgName, tc.ids...)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedSeverity, override.Severity)
+		})
+	}
+}
+
 func TestConfigData_GetKubeBenchImageRef(t *testing.T) {
 	testCases := []struct {
 		name             string